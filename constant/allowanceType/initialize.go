@@ -1,4 +1,102 @@
 package allowanceType
 
+import "github.com/larb26656/assessment-tax/money"
+
 const Donation = "donation"
 const KReceipt = "k-receipt"
+const RMF = "rmf"
+const SSF = "ssf"
+const ProvidentFund = "provident-fund"
+const PensionInsurance = "pension-insurance"
+const LifeInsurance = "life-insurance"
+const HealthInsurance = "health-insurance"
+const ParentHealthInsurance = "parent-health-insurance"
+
+// DonationCapRate is the setting key of the percentage of net income a donation is capped at
+const DonationCapRate = "donation-cap-rate"
+
+// bases of a percentage cap
+const (
+	// CapBaseGrossIncome is the total income
+	CapBaseGrossIncome = "gross-income"
+	// CapBaseNetIncome is the net income after the deductions of every type deducted before
+	CapBaseNetIncome = "net-income"
+	// CapBaseAmount is the amount contributed to the allowance type itself
+	CapBaseAmount = "amount"
+)
+
+// registry holds every allowance type in the order they are deducted,
+// donation is capped on the net income after the other deductions so it goes last
+var registry = []AllowanceType{
+	{
+		Key:             KReceipt,
+		MinMaxDeduction: money.FromBaht(0),
+		MaxMaxDeduction: money.FromBaht(100000),
+	},
+	{
+		Key:             RMF,
+		MinMaxDeduction: money.FromBaht(0),
+		MaxMaxDeduction: money.FromBaht(500000),
+		CapRate:         money.FromPercent(30),
+		CapBase:         CapBaseGrossIncome,
+	},
+	{
+		Key:             SSF,
+		MinMaxDeduction: money.FromBaht(0),
+		MaxMaxDeduction: money.FromBaht(200000),
+		CapRate:         money.FromPercent(30),
+		CapBase:         CapBaseGrossIncome,
+	},
+	{
+		Key:             ProvidentFund,
+		MinMaxDeduction: money.FromBaht(0),
+		MaxMaxDeduction: money.FromBaht(500000),
+		CapRate:         money.FromPercent(15),
+		CapBase:         CapBaseGrossIncome,
+	},
+	{
+		Key:             PensionInsurance,
+		MinMaxDeduction: money.FromBaht(0),
+		MaxMaxDeduction: money.FromBaht(200000),
+		CapRate:         money.FromPercent(15),
+		CapBase:         CapBaseGrossIncome,
+	},
+	{
+		Key:             LifeInsurance,
+		MinMaxDeduction: money.FromBaht(0),
+		MaxMaxDeduction: money.FromBaht(100000),
+	},
+	{
+		Key:             HealthInsurance,
+		MinMaxDeduction: money.FromBaht(0),
+		MaxMaxDeduction: money.FromBaht(25000),
+	},
+	{
+		Key:             ParentHealthInsurance,
+		MinMaxDeduction: money.FromBaht(0),
+		MaxMaxDeduction: money.FromBaht(15000),
+	},
+	{
+		Key:             Donation,
+		MinMaxDeduction: money.FromBaht(0),
+		MaxMaxDeduction: money.FromBaht(100000),
+		CapRateKey:      DonationCapRate,
+		MaxCapRate:      money.FromPercent(100),
+		CapBase:         CapBaseNetIncome,
+	},
+}
+
+// All returns every registered allowance type in the order they are deducted
+func All() []AllowanceType {
+	return append([]AllowanceType{}, registry...)
+}
+
+func Get(key string) (AllowanceType, bool) {
+	for _, allowanceType := range registry {
+		if allowanceType.Key == key {
+			return allowanceType, true
+		}
+	}
+
+	return AllowanceType{}, false
+}
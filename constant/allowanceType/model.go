@@ -0,0 +1,21 @@
+package allowanceType
+
+import "github.com/larb26656/assessment-tax/money"
+
+// AllowanceType declares an allowance the calculator accepts
+type AllowanceType struct {
+	Key string
+
+	// the cap is read from tax_deduction_setting under Key and can be set by the admin
+	// between MinMaxDeduction and MaxMaxDeduction
+	MinMaxDeduction money.Money
+	MaxMaxDeduction money.Money
+
+	// CapRate further limits the deduction to a percentage of CapBase, zero means no percentage cap.
+	// When CapRateKey is set the rate is read from tax_deduction_setting under CapRateKey instead
+	// and can be set by the admin up to MaxCapRate
+	CapRate    money.Rate
+	CapRateKey string
+	MaxCapRate money.Rate
+	CapBase    string
+}
@@ -1,5 +1,18 @@
 package deductionType
 
 const (
-	Personal = "personal"
+	Personal          = "personal"
+	Spouse            = "spouse"
+	Child             = "child"
+	ChildExtra        = "child-extra"
+	Parent            = "parent"
+	ParentMaxIncome   = "parent-max-income"
+	DisabledDependent = "disabled-dependent"
+)
+
+const (
+	// ChildExtraFromBirthYear is the first birth year of a second or later child that gets the child extra
+	ChildExtraFromBirthYear = 2561
+	// ParentMinAge is the age a parent must reach to be deducted
+	ParentMinAge = 60
 )
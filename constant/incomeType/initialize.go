@@ -0,0 +1,85 @@
+package incomeType
+
+import "github.com/larb26656/assessment-tax/money"
+
+const Salary = "40(1)"
+const Service = "40(2)"
+const Royalty = "40(3)"
+const Investment = "40(4)"
+const Rental = "40(5)"
+const Professional = "40(6)"
+const Contracting = "40(7)"
+const Business = "40(8)"
+
+// methods of an expense deduction
+const (
+	// ExpenseMethodStandard deducts ExpenseRate of the income up to MaxExpense
+	ExpenseMethodStandard = "standard"
+	// ExpenseMethodActual deducts the actual expense
+	ExpenseMethodActual = "actual"
+)
+
+// registry holds every section 40 income type, 40(1) and 40(2) share one 100,000 expense cap
+var registry = []IncomeType{
+	{
+		Key:          Salary,
+		ExpenseRate:  money.FromPercent(50),
+		ExpenseGroup: "40(1)-40(2)",
+		MaxExpense:   money.FromBaht(100000),
+	},
+	{
+		Key:          Service,
+		ExpenseRate:  money.FromPercent(50),
+		ExpenseGroup: "40(1)-40(2)",
+		MaxExpense:   money.FromBaht(100000),
+	},
+	{
+		Key:          Royalty,
+		ExpenseRate:  money.FromPercent(50),
+		ExpenseGroup: Royalty,
+		MaxExpense:   money.FromBaht(100000),
+	},
+	{
+		Key:          Investment,
+		ExpenseGroup: Investment,
+	},
+	{
+		Key:           Rental,
+		ExpenseRate:   money.FromPercent(30),
+		ExpenseGroup:  Rental,
+		ActualExpense: true,
+	},
+	{
+		Key:           Professional,
+		ExpenseRate:   money.FromPercent(60),
+		ExpenseGroup:  Professional,
+		ActualExpense: true,
+	},
+	{
+		Key:           Contracting,
+		ExpenseRate:   money.FromPercent(60),
+		ExpenseGroup:  Contracting,
+		ActualExpense: true,
+	},
+	{
+		Key:           Business,
+		ExpenseRate:   money.FromPercent(60),
+		ExpenseGroup:  Business,
+		ActualExpense: true,
+	},
+}
+
+// All returns every registered income type
+func All() []IncomeType {
+	return append([]IncomeType{}, registry...)
+}
+
+func Get(key string) (IncomeType, bool) {
+	for _, incomeType := range registry {
+		if incomeType.Key == key {
+			return incomeType, true
+		}
+	}
+
+	return IncomeType{}, false
+}
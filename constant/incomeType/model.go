@@ -0,0 +1,18 @@
+package incomeType
+
+import "github.com/larb26656/assessment-tax/money"
+
+// IncomeType declares a section 40 income category and the expense deducted from it
+type IncomeType struct {
+	Key string
+
+	// ExpenseRate is the standard expense as a percentage of the income, zero means no expense
+	ExpenseRate money.Rate
+
+	// MaxExpense caps the standard expense of every income type sharing ExpenseGroup, zero means no cap
+	ExpenseGroup string
+	MaxExpense   money.Money
+
+	// ActualExpense allows the actual expense to be deducted instead of the standard expense
+	ActualExpense bool
+}
@@ -0,0 +1,24 @@
+package investmentIncome
+
+import "github.com/larb26656/assessment-tax/money"
+
+const (
+	Interest = "interest"
+	Dividend = "dividend"
+)
+
+const (
+	// OptionFinal keeps the withholding tax as the final tax and leaves the income out of the progressive tax
+	OptionFinal = "final"
+	// OptionInclude adds the income to the progressive tax and credits its withholding tax
+	OptionInclude = "include"
+	// OptionAuto picks the option with the lower tax to pay
+	OptionAuto = "auto"
+)
+
+const (
+	InterestWithholdingRate = 15 * money.Percent
+	DividendWithholdingRate = 10 * money.Percent
+	// DefaultCorporateTaxRate is the tax the company paid on the profit a dividend is paid from
+	DefaultCorporateTaxRate = 20 * money.Percent
+)
@@ -0,0 +1,14 @@
+package jobStatus
+
+const (
+	// Pending is a job waiting for a worker
+	Pending = "pending"
+	// Running is a job a worker is processing, it goes back to a worker when its lease runs out
+	Running = "running"
+	// Completed is a job with every row processed
+	Completed = "completed"
+	// Failed is a job stopped by a bad row in strict mode or by an error
+	Failed = "failed"
+	// Cancelled is a job cancelled by the user, the rows processed before are kept
+	Cancelled = "cancelled"
+)
@@ -0,0 +1,17 @@
+package taxMethod
+
+import "github.com/larb26656/assessment-tax/money"
+
+const (
+	// Progressive is the tax on net income by the tax brackets
+	Progressive = "progressive"
+	// Minimum is MinimumTaxRate of the gross income other than 40(1)
+	Minimum = "minimum"
+)
+
+const (
+	// MinimumTaxRate is the rate of the minimum tax
+	MinimumTaxRate money.Rate = 50
+	// MinimumTaxThreshold is the income other than 40(1) that must be exceeded for the minimum tax to apply
+	MinimumTaxThreshold = 120000 * money.Baht
+)
@@ -0,0 +1,12 @@
+package taxYear
+
+// Default is the tax year used when a request does not specify one
+const Default = 2567
+
+func Resolve(taxYear int) int {
+	if taxYear == 0 {
+		return Default
+	}
+
+	return taxYear
+}
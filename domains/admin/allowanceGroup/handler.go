@@ -0,0 +1,78 @@
+package allowanceGroup
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+)
+
+type AllowanceGroupHttpHandler interface {
+	GetAllowanceGroups(c echo.Context) error
+	UpdateAllowanceGroups(c echo.Context) error
+}
+
+type allowanceGroupHttpHandler struct {
+	allowanceGroupUsecase AllowanceGroupUsecase
+}
+
+func NewAllowanceGroupHttpHandler(allowanceGroupUsecase AllowanceGroupUsecase) AllowanceGroupHttpHandler {
+	return &allowanceGroupHttpHandler{
+		allowanceGroupUsecase: allowanceGroupUsecase,
+	}
+}
+
+func (a *allowanceGroupHttpHandler) GetAllowanceGroups(c echo.Context) error {
+	year := 0
+
+	err := echo.QueryParamsBinder(c).Int("taxYear", &year).BindError()
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	year = taxYear.Resolve(year)
+
+	allowanceGroups, err := a.allowanceGroupUsecase.GetAllowanceGroups(year)
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, AllowanceGroupsRes{
+		TaxYear:         year,
+		AllowanceGroups: allowanceGroups,
+	})
+}
+
+func (a *allowanceGroupHttpHandler) UpdateAllowanceGroups(c echo.Context) error {
+	var req UpdateAllowanceGroupsReq
+
+	err := c.Bind(&req)
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if err = c.Validate(req); err != nil {
+		return err
+	}
+
+	res, err := a.allowanceGroupUsecase.UpdateAllowanceGroups(req)
+
+	if errors.Is(err, ErrTaxYearNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tax year not found, clone the tax year first")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
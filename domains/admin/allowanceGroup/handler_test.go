@@ -0,0 +1,217 @@
+package allowanceGroup
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/money"
+	myValidator "github.com/larb26656/assessment-tax/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAllowanceGroupUsecaseCaseSuccess struct {
+	taxYear int
+}
+
+func (m *mockAllowanceGroupUsecaseCaseSuccess) GetAllowanceGroups(taxYear int) ([]AllowanceGroup, error) {
+	m.taxYear = taxYear
+
+	return []AllowanceGroup{
+		{Key: "retirement", MaxDeduction: money.FromBaht(500000), AllowanceTypes: []string{"rmf", "ssf"}},
+	}, nil
+}
+
+func (m *mockAllowanceGroupUsecaseCaseSuccess) UpdateAllowanceGroups(req UpdateAllowanceGroupsReq) (AllowanceGroupsRes, error) {
+	return AllowanceGroupsRes{
+		TaxYear: 2567,
+		AllowanceGroups: []AllowanceGroup{
+			{Key: "retirement", MaxDeduction: money.FromBaht(500000), AllowanceTypes: []string{"rmf", "ssf"}},
+		},
+	}, nil
+}
+
+type mockAllowanceGroupUsecaseCaseError struct {
+}
+
+func (m *mockAllowanceGroupUsecaseCaseError) GetAllowanceGroups(taxYear int) ([]AllowanceGroup, error) {
+	return nil, errors.New("database error")
+}
+
+func (m *mockAllowanceGroupUsecaseCaseError) UpdateAllowanceGroups(req UpdateAllowanceGroupsReq) (AllowanceGroupsRes, error) {
+	return AllowanceGroupsRes{}, errors.New("database error")
+}
+
+type mockAllowanceGroupUsecaseCaseTaxYearNotFound struct {
+}
+
+func (m *mockAllowanceGroupUsecaseCaseTaxYearNotFound) GetAllowanceGroups(taxYear int) ([]AllowanceGroup, error) {
+	return []AllowanceGroup{}, nil
+}
+
+func (m *mockAllowanceGroupUsecaseCaseTaxYearNotFound) UpdateAllowanceGroups(req UpdateAllowanceGroupsReq) (AllowanceGroupsRes, error) {
+	return AllowanceGroupsRes{}, ErrTaxYearNotFound
+}
+
+func mockGetAllowanceGroupsHttpReq(query string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/allowance-groups"+query, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+func mockUpdateAllowanceGroupsHttpReq(reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/allowance-groups", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+// GetAllowanceGroups
+func TestGetAllowanceGroupsHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	usecase := &mockAllowanceGroupUsecaseCaseSuccess{}
+	handler := NewAllowanceGroupHttpHandler(usecase)
+	_, c, rec := mockGetAllowanceGroupsHttpReq("?taxYear=2568")
+
+	// Act
+	err := handler.GetAllowanceGroups(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2568, usecase.taxYear)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.JSONEq(t, `{
+		"taxYear": 2568,
+		"allowanceGroups": [
+			{"key": "retirement", "maxDeduction": 500000, "allowanceTypes": ["rmf", "ssf"]}
+		]
+	}`, rec.Body.String())
+}
+
+func TestGetAllowanceGroupsHandler_ShouldGetError_WhenWrongInputOrUsecaseFail(t *testing.T) {
+	testCases := []struct {
+		name         string
+		usecase      AllowanceGroupUsecase
+		query        string
+		expectedCode int
+	}{
+		{"Test case 1", &mockAllowanceGroupUsecaseCaseSuccess{}, "?taxYear=asdasd", http.StatusBadRequest},
+		{"Test case 2", &mockAllowanceGroupUsecaseCaseError{}, "", http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAllowanceGroupHttpHandler(tc.usecase)
+			_, c, _ := mockGetAllowanceGroupsHttpReq(tc.query)
+
+			// Act
+			err := handler.GetAllowanceGroups(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCode, he.Code)
+		})
+	}
+}
+
+// UpdateAllowanceGroups
+func TestUpdateAllowanceGroupsHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	handler := NewAllowanceGroupHttpHandler(&mockAllowanceGroupUsecaseCaseSuccess{})
+	_, c, rec := mockUpdateAllowanceGroupsHttpReq(`{
+		"allowanceGroups": [
+			{"key": "retirement", "maxDeduction": 500000, "allowanceTypes": ["rmf", "ssf"]}
+		]
+	}`)
+
+	// Act
+	err := handler.UpdateAllowanceGroups(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+}
+
+func TestUpdateAllowanceGroupsHandler_ShouldGetBadRequest_WhenWrongInput(t *testing.T) {
+	// Arrange
+	handler := NewAllowanceGroupHttpHandler(&mockAllowanceGroupUsecaseCaseSuccess{})
+
+	testCases := []struct {
+		name    string
+		reqBody string
+	}{
+		{"Test case 1", `{"allowanceGroups": asdasd}`},
+		{"Test case 2", `{"allowanceGroups": [{"key": "", "maxDeduction": 500000, "allowanceTypes": ["rmf", "ssf"]}]}`},
+		{"Test case 3", `{"allowanceGroups": [{"key": "retirement", "maxDeduction": -1, "allowanceTypes": ["rmf", "ssf"]}]}`},
+		{"Test case 4", `{"allowanceGroups": [{"key": "retirement", "maxDeduction": 500000, "allowanceTypes": ["rmf"]}]}`},
+		{"Test case 5", `{"allowanceGroups": [{"key": "retirement", "maxDeduction": 500000, "allowanceTypes": ["rmf", "rmf"]}]}`},
+		{"Test case 6", `{"allowanceGroups": [{"key": "retirement", "maxDeduction": 500000, "allowanceTypes": ["rmf", "unknown"]}]}`},
+		{"Test case 7", `{"allowanceGroups": [
+			{"key": "retirement", "maxDeduction": 500000, "allowanceTypes": ["rmf", "ssf"]},
+			{"key": "retirement", "maxDeduction": 100000, "allowanceTypes": ["donation", "k-receipt"]}
+		]}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, _ := mockUpdateAllowanceGroupsHttpReq(tc.reqBody)
+
+			// Act
+			err := handler.UpdateAllowanceGroups(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+func TestUpdateAllowanceGroupsHandler_ShouldGetInternalServerError_WhenErrorOnUpdate(t *testing.T) {
+	// Arrange
+	handler := NewAllowanceGroupHttpHandler(&mockAllowanceGroupUsecaseCaseError{})
+	_, c, _ := mockUpdateAllowanceGroupsHttpReq(`{"allowanceGroups": []}`)
+
+	// Act
+	err := handler.UpdateAllowanceGroups(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, he.Code)
+}
+
+func TestUpdateAllowanceGroupsHandler_ShouldGetNotFound_WhenTaxYearNotExists(t *testing.T) {
+	// Arrange
+	handler := NewAllowanceGroupHttpHandler(&mockAllowanceGroupUsecaseCaseTaxYearNotFound{})
+	_, c, _ := mockUpdateAllowanceGroupsHttpReq(`{"taxYear": 2599, "allowanceGroups": []}`)
+
+	// Act
+	err := handler.UpdateAllowanceGroups(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, he.Code)
+	assert.Equal(t, "Tax year not found, clone the tax year first", he.Message)
+}
@@ -0,0 +1,37 @@
+package allowanceGroup
+
+import "github.com/larb26656/assessment-tax/money"
+
+// AllowanceGroup is a combined cap shared by several allowance types
+type AllowanceGroup struct {
+	Key            string      `json:"key"`
+	MaxDeduction   money.Money `json:"maxDeduction"`
+	AllowanceTypes []string    `json:"allowanceTypes"`
+}
+
+type AllowanceGroupReq struct {
+	Key            string      `json:"key" validate:"required"`
+	MaxDeduction   money.Money `json:"maxDeduction" validate:"gte=0"`
+	AllowanceTypes []string    `json:"allowanceTypes" validate:"required,min=2,unique,dive,allowance_type"`
+}
+
+type UpdateAllowanceGroupsReq struct {
+	TaxYear         int                 `json:"taxYear" validate:"omitempty,gte=2500"`
+	AllowanceGroups []AllowanceGroupReq `json:"allowanceGroups" validate:"unique=Key,dive"`
+}
+
+type AllowanceGroupsRes struct {
+	TaxYear         int              `json:"taxYear"`
+	AllowanceGroups []AllowanceGroup `json:"allowanceGroups"`
+}
+
+// Contains reports whether the allowance type shares this group cap
+func (g AllowanceGroup) Contains(allowanceType string) bool {
+	for _, member := range g.AllowanceTypes {
+		if member == allowanceType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,125 @@
+package allowanceGroup
+
+import (
+	"database/sql"
+	"errors"
+)
+
+var ErrTaxYearNotFound = errors.New("tax year not found")
+
+type AllowanceGroupRepository interface {
+	GetAllowanceGroups(taxYear int) ([]AllowanceGroup, error)
+	ReplaceAllowanceGroups(taxYear int, allowanceGroups []AllowanceGroup) error
+}
+
+type allowanceGroupRepository struct {
+	db *sql.DB
+}
+
+func NewAllowanceGroupRepository(db *sql.DB) AllowanceGroupRepository {
+	return &allowanceGroupRepository{
+		db: db,
+	}
+}
+
+func (r *allowanceGroupRepository) GetAllowanceGroups(taxYear int) ([]AllowanceGroup, error) {
+	stmt, err := r.db.Prepare(`SELECT g."key", g.max_deduction, m.allowance_type FROM tax_allowance_group g JOIN tax_allowance_group_member m ON m.tax_year = g.tax_year AND m.group_key = g."key" WHERE g.tax_year = $1 ORDER BY g."key", m.allowance_type`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer stmt.Close()
+
+	rows, err := stmt.Query(taxYear)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	allowanceGroups := []AllowanceGroup{}
+
+	// rows are ordered by group, one row per member
+	for rows.Next() {
+		var allowanceGroup AllowanceGroup
+		var allowanceType string
+
+		err = rows.Scan(&allowanceGroup.Key, &allowanceGroup.MaxDeduction, &allowanceType)
+
+		if err != nil {
+			return nil, err
+		}
+
+		last := len(allowanceGroups) - 1
+
+		if last < 0 || allowanceGroups[last].Key != allowanceGroup.Key {
+			allowanceGroups = append(allowanceGroups, allowanceGroup)
+			last++
+		}
+
+		allowanceGroups[last].AllowanceTypes = append(allowanceGroups[last].AllowanceTypes, allowanceType)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return allowanceGroups, nil
+}
+
+// ReplaceAllowanceGroups replaces every group of the tax year, it returns ErrTaxYearNotFound when the year
+// has no deduction setting or tax bracket so groups are never left behind for a year that is not cloned yet
+func (r *allowanceGroupRepository) ReplaceAllowanceGroups(taxYear int, allowanceGroups []AllowanceGroup) error {
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	// rollback is no-op after commit
+	defer tx.Rollback()
+
+	var exists bool
+
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM tax_deduction_setting WHERE tax_year = $1) OR EXISTS (SELECT 1 FROM tax_bracket WHERE tax_year = $1)`, taxYear).Scan(&exists)
+
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return ErrTaxYearNotFound
+	}
+
+	_, err = tx.Exec(`DELETE FROM tax_allowance_group_member WHERE tax_year = $1`, taxYear)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM tax_allowance_group WHERE tax_year = $1`, taxYear)
+
+	if err != nil {
+		return err
+	}
+
+	for _, allowanceGroup := range allowanceGroups {
+		_, err = tx.Exec(`INSERT INTO tax_allowance_group (tax_year, "key", max_deduction) VALUES ($1, $2, $3)`, taxYear, allowanceGroup.Key, allowanceGroup.MaxDeduction)
+
+		if err != nil {
+			return err
+		}
+
+		for _, allowanceType := range allowanceGroup.AllowanceTypes {
+			_, err = tx.Exec(`INSERT INTO tax_allowance_group_member (tax_year, group_key, allowance_type) VALUES ($1, $2, $3)`, taxYear, allowanceGroup.Key, allowanceType)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,178 @@
+package allowanceGroup
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/larb26656/assessment-tax/money"
+	"github.com/stretchr/testify/assert"
+)
+
+const mockGetAllowanceGroupsQuery = `SELECT g."key", g.max_deduction, m.allowance_type FROM tax_allowance_group g JOIN tax_allowance_group_member m ON m.tax_year = g.tax_year AND m.group_key = g."key" WHERE g.tax_year = \$1 ORDER BY g."key", m.allowance_type`
+
+// GetAllowanceGroups
+
+func TestGetAllowanceGroups_ShouldReturnError_WhenErrorOnPrepare(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewAllowanceGroupRepository(db)
+	mock.ExpectPrepare(mockGetAllowanceGroupsQuery).WillReturnError(errors.New("error on prepare"))
+
+	// Act
+	_, err = repo.GetAllowanceGroups(2567)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestGetAllowanceGroups_ShouldReturnError_WhenErrorOnScan(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewAllowanceGroupRepository(db)
+	rows := sqlmock.NewRows([]string{"key", "max_deduction", "allowance_type"}).AddRow("retirement", "abc", "rmf")
+	mock.ExpectPrepare(mockGetAllowanceGroupsQuery).ExpectQuery().WithArgs(2567).WillReturnRows(rows)
+
+	// Act
+	_, err = repo.GetAllowanceGroups(2567)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestGetAllowanceGroups_ShouldGroupMembers_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewAllowanceGroupRepository(db)
+	rows := sqlmock.NewRows([]string{"key", "max_deduction", "allowance_type"}).
+		AddRow("insurance", "100000.00", "health-insurance").
+		AddRow("insurance", "100000.00", "life-insurance").
+		AddRow("retirement", "500000.00", "rmf").
+		AddRow("retirement", "500000.00", "ssf")
+	mock.ExpectPrepare(mockGetAllowanceGroupsQuery).ExpectQuery().WithArgs(2567).WillReturnRows(rows)
+
+	// Act
+	result, err := repo.GetAllowanceGroups(2567)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []AllowanceGroup{
+		{Key: "insurance", MaxDeduction: money.FromBaht(100000), AllowanceTypes: []string{"health-insurance", "life-insurance"}},
+		{Key: "retirement", MaxDeduction: money.FromBaht(500000), AllowanceTypes: []string{"rmf", "ssf"}},
+	}, result)
+}
+
+func TestGetAllowanceGroups_ShouldReturnEmpty_WhenNoGroup(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewAllowanceGroupRepository(db)
+	rows := sqlmock.NewRows([]string{"key", "max_deduction", "allowance_type"})
+	mock.ExpectPrepare(mockGetAllowanceGroupsQuery).ExpectQuery().WithArgs(2599).WillReturnRows(rows)
+
+	// Act
+	result, err := repo.GetAllowanceGroups(2599)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []AllowanceGroup{}, result)
+}
+
+// ReplaceAllowanceGroups
+
+func TestReplaceAllowanceGroups_ShouldReturnErrTaxYearNotFound_WhenTaxYearNotExists(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewAllowanceGroupRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM tax_deduction_setting WHERE tax_year = \$1\) OR EXISTS \(SELECT 1 FROM tax_bracket WHERE tax_year = \$1\)`).
+		WithArgs(2599).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectRollback()
+
+	// Act
+	err = repo.ReplaceAllowanceGroups(2599, []AllowanceGroup{
+		{Key: "retirement", MaxDeduction: money.FromBaht(500000), AllowanceTypes: []string{"rmf", "ssf"}},
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrTaxYearNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplaceAllowanceGroups_ShouldRollback_WhenErrorOnInsert(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewAllowanceGroupRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(2567).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec(`DELETE FROM tax_allowance_group_member`).WithArgs(2567).WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(`DELETE FROM tax_allowance_group `).WithArgs(2567).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO tax_allowance_group `).WithArgs(2567, "retirement", money.FromBaht(500000)).WillReturnError(errors.New("error on insert"))
+	mock.ExpectRollback()
+
+	// Act
+	err = repo.ReplaceAllowanceGroups(2567, []AllowanceGroup{
+		{Key: "retirement", MaxDeduction: money.FromBaht(500000), AllowanceTypes: []string{"rmf", "ssf"}},
+	})
+
+	// Assert
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplaceAllowanceGroups_ShouldSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewAllowanceGroupRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(2567).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec(`DELETE FROM tax_allowance_group_member`).WithArgs(2567).WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(`DELETE FROM tax_allowance_group `).WithArgs(2567).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO tax_allowance_group `).WithArgs(2567, "retirement", money.FromBaht(500000)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO tax_allowance_group_member`).WithArgs(2567, "retirement", "rmf").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO tax_allowance_group_member`).WithArgs(2567, "retirement", "ssf").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err = repo.ReplaceAllowanceGroups(2567, []AllowanceGroup{
+		{Key: "retirement", MaxDeduction: money.FromBaht(500000), AllowanceTypes: []string{"rmf", "ssf"}},
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
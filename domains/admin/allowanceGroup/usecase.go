@@ -0,0 +1,60 @@
+package allowanceGroup
+
+import (
+	"sort"
+
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+)
+
+type AllowanceGroupUsecase interface {
+	GetAllowanceGroups(taxYear int) ([]AllowanceGroup, error)
+	UpdateAllowanceGroups(req UpdateAllowanceGroupsReq) (AllowanceGroupsRes, error)
+}
+
+type allowanceGroupUsecase struct {
+	allowanceGroupRepository AllowanceGroupRepository
+}
+
+func NewAllowanceGroupUsecase(allowanceGroupRepository AllowanceGroupRepository) AllowanceGroupUsecase {
+	return &allowanceGroupUsecase{
+		allowanceGroupRepository: allowanceGroupRepository,
+	}
+}
+
+// GetAllowanceGroups returns the group caps of the tax year, a year without groups has none to apply
+func (a *allowanceGroupUsecase) GetAllowanceGroups(taxYear int) ([]AllowanceGroup, error) {
+	return a.allowanceGroupRepository.GetAllowanceGroups(taxYear)
+}
+
+// UpdateAllowanceGroups replaces every group cap of the tax year, groups and members are kept sorted by key
+func (a *allowanceGroupUsecase) UpdateAllowanceGroups(req UpdateAllowanceGroupsReq) (AllowanceGroupsRes, error) {
+	allowanceGroups := make([]AllowanceGroup, len(req.AllowanceGroups))
+
+	for i, allowanceGroup := range req.AllowanceGroups {
+		allowanceTypes := append([]string{}, allowanceGroup.AllowanceTypes...)
+		sort.Strings(allowanceTypes)
+
+		allowanceGroups[i] = AllowanceGroup{
+			Key:            allowanceGroup.Key,
+			MaxDeduction:   allowanceGroup.MaxDeduction,
+			AllowanceTypes: allowanceTypes,
+		}
+	}
+
+	sort.Slice(allowanceGroups, func(i, j int) bool {
+		return allowanceGroups[i].Key < allowanceGroups[j].Key
+	})
+
+	year := taxYear.Resolve(req.TaxYear)
+
+	err := a.allowanceGroupRepository.ReplaceAllowanceGroups(year, allowanceGroups)
+
+	if err != nil {
+		return AllowanceGroupsRes{}, err
+	}
+
+	return AllowanceGroupsRes{
+		TaxYear:         year,
+		AllowanceGroups: allowanceGroups,
+	}, nil
+}
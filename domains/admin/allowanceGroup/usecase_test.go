@@ -0,0 +1,92 @@
+package allowanceGroup
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/money"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAllowanceGroupRepositoryCaseSuccess struct {
+	taxYear         int
+	allowanceGroups []AllowanceGroup
+}
+
+func (m *mockAllowanceGroupRepositoryCaseSuccess) GetAllowanceGroups(taxYear int) ([]AllowanceGroup, error) {
+	m.taxYear = taxYear
+	return []AllowanceGroup{}, nil
+}
+
+func (m *mockAllowanceGroupRepositoryCaseSuccess) ReplaceAllowanceGroups(taxYear int, allowanceGroups []AllowanceGroup) error {
+	m.taxYear = taxYear
+	m.allowanceGroups = allowanceGroups
+	return nil
+}
+
+type mockAllowanceGroupRepositoryCaseError struct {
+}
+
+func (m *mockAllowanceGroupRepositoryCaseError) GetAllowanceGroups(taxYear int) ([]AllowanceGroup, error) {
+	return nil, errors.New("database error")
+}
+
+func (m *mockAllowanceGroupRepositoryCaseError) ReplaceAllowanceGroups(taxYear int, allowanceGroups []AllowanceGroup) error {
+	return errors.New("database error")
+}
+
+// GetAllowanceGroups
+func TestGetAllowanceGroups_ShouldReadTaxYear(t *testing.T) {
+	// Arrange
+	repo := &mockAllowanceGroupRepositoryCaseSuccess{}
+	usecase := NewAllowanceGroupUsecase(repo)
+
+	// Act
+	result, err := usecase.GetAllowanceGroups(2568)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2568, repo.taxYear)
+	assert.Equal(t, []AllowanceGroup{}, result)
+}
+
+// UpdateAllowanceGroups
+func TestUpdateAllowanceGroups_ShouldSortGroupsAndMembers_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	repo := &mockAllowanceGroupRepositoryCaseSuccess{}
+	usecase := NewAllowanceGroupUsecase(repo)
+	req := UpdateAllowanceGroupsReq{
+		AllowanceGroups: []AllowanceGroupReq{
+			{Key: "retirement", MaxDeduction: money.FromBaht(500000), AllowanceTypes: []string{allowanceType.SSF, allowanceType.RMF}},
+			{Key: "giving", MaxDeduction: money.FromBaht(100000), AllowanceTypes: []string{allowanceType.KReceipt, allowanceType.Donation}},
+		},
+	}
+
+	// Act
+	result, err := usecase.UpdateAllowanceGroups(req)
+
+	// Assert
+	expected := []AllowanceGroup{
+		{Key: "giving", MaxDeduction: money.FromBaht(100000), AllowanceTypes: []string{allowanceType.Donation, allowanceType.KReceipt}},
+		{Key: "retirement", MaxDeduction: money.FromBaht(500000), AllowanceTypes: []string{allowanceType.RMF, allowanceType.SSF}},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, taxYear.Default, repo.taxYear)
+	assert.Equal(t, expected, repo.allowanceGroups)
+	assert.Equal(t, AllowanceGroupsRes{TaxYear: taxYear.Default, AllowanceGroups: expected}, result)
+	assert.Equal(t, []string{allowanceType.SSF, allowanceType.RMF}, req.AllowanceGroups[0].AllowanceTypes)
+}
+
+func TestUpdateAllowanceGroups_ShouldReturnErr_WhenReplaceFail(t *testing.T) {
+	// Arrange
+	usecase := NewAllowanceGroupUsecase(&mockAllowanceGroupRepositoryCaseError{})
+
+	// Act
+	_, err := usecase.UpdateAllowanceGroups(UpdateAllowanceGroupsReq{TaxYear: 2568})
+
+	// Assert
+	assert.Error(t, err)
+}
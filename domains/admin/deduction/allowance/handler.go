@@ -0,0 +1,127 @@
+package allowance
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+)
+
+type AllowanceDeductionHttpHandler interface {
+	GetAllowanceTypes(c echo.Context) error
+	UpdateMaxDeduction(c echo.Context) error
+	UpdateCapRate(c echo.Context) error
+}
+
+type allowanceDeductionHttpHandler struct {
+	allowanceDeductionUsecase AllowanceDeductionUsecase
+}
+
+func NewAllowanceDeductionHttpHandler(allowanceDeductionUsecase AllowanceDeductionUsecase) AllowanceDeductionHttpHandler {
+	return &allowanceDeductionHttpHandler{
+		allowanceDeductionUsecase: allowanceDeductionUsecase,
+	}
+}
+
+func (a *allowanceDeductionHttpHandler) GetAllowanceTypes(c echo.Context) error {
+	year := 0
+
+	err := echo.QueryParamsBinder(c).Int("taxYear", &year).BindError()
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	res, err := a.allowanceDeductionUsecase.GetAllowanceTypes(taxYear.Resolve(year))
+
+	if errors.Is(err, deduction.ErrDeductionNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tax year not found")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (a *allowanceDeductionHttpHandler) UpdateMaxDeduction(c echo.Context) error {
+	var req UpdateAllowanceDeductionReq
+
+	err := c.Bind(&req)
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if err = c.Validate(req); err != nil {
+		return err
+	}
+
+	res, err := a.allowanceDeductionUsecase.UpdateMaxDeduction(c.Param("allowanceType"), req)
+
+	if errors.Is(err, ErrAllowanceTypeNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Allowance type not found")
+	}
+
+	if errors.Is(err, ErrMaxDeductionOutOfRange) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Max deduction out of range")
+	}
+
+	if errors.Is(err, deduction.ErrDeductionNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tax year not found, clone the tax year first")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (a *allowanceDeductionHttpHandler) UpdateCapRate(c echo.Context) error {
+	var req UpdateAllowanceCapRateReq
+
+	err := c.Bind(&req)
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if err = c.Validate(req); err != nil {
+		return err
+	}
+
+	res, err := a.allowanceDeductionUsecase.UpdateCapRate(c.Param("allowanceType"), req)
+
+	if errors.Is(err, ErrAllowanceTypeNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Allowance type not found")
+	}
+
+	if errors.Is(err, ErrCapRateNotConfigurable) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Cap rate not configurable")
+	}
+
+	if errors.Is(err, ErrCapRateOutOfRange) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Cap rate out of range")
+	}
+
+	if errors.Is(err, deduction.ErrDeductionNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tax year not found, clone the tax year first")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,306 @@
+package allowance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/money"
+	myValidator "github.com/larb26656/assessment-tax/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAllowanceDeductionUsecaseCaseSuccess struct {
+	taxYear int
+	key     string
+}
+
+func (m *mockAllowanceDeductionUsecaseCaseSuccess) GetMaxDeduction(taxYear int, key string) (money.Money, error) {
+	return money.FromBaht(50000), nil
+}
+
+func (m *mockAllowanceDeductionUsecaseCaseSuccess) GetAllowanceTypes(taxYear int) (AllowanceTypesRes, error) {
+	m.taxYear = taxYear
+
+	return AllowanceTypesRes{
+		TaxYear: taxYear,
+		AllowanceTypes: []AllowanceTypeRes{
+			{AllowanceType: allowanceType.Donation, MaxDeduction: money.FromBaht(100000)},
+		},
+	}, nil
+}
+
+func (m *mockAllowanceDeductionUsecaseCaseSuccess) UpdateMaxDeduction(key string, req UpdateAllowanceDeductionReq) (UpdateAllowanceDeductionRes, error) {
+	m.key = key
+
+	return UpdateAllowanceDeductionRes{
+		TaxYear:       2567,
+		AllowanceType: key,
+		MaxDeduction:  req.Amount,
+	}, nil
+}
+
+func (m *mockAllowanceDeductionUsecaseCaseSuccess) UpdateMaxDeductions(taxYear int, maxDeductions []deduction.Deduction) error {
+	return nil
+}
+
+func (m *mockAllowanceDeductionUsecaseCaseSuccess) GetCapRate(taxYear int, key string) (money.Rate, error) {
+	return money.FromPercent(10), nil
+}
+
+func (m *mockAllowanceDeductionUsecaseCaseSuccess) UpdateCapRate(key string, req UpdateAllowanceCapRateReq) (UpdateAllowanceCapRateRes, error) {
+	m.key = key
+
+	return UpdateAllowanceCapRateRes{
+		TaxYear:       2567,
+		AllowanceType: key,
+		CapRate:       req.CapRate,
+	}, nil
+}
+
+type mockAllowanceDeductionUsecaseCaseError struct {
+	err error
+}
+
+func (m *mockAllowanceDeductionUsecaseCaseError) GetMaxDeduction(taxYear int, key string) (money.Money, error) {
+	return 0, m.err
+}
+
+func (m *mockAllowanceDeductionUsecaseCaseError) GetAllowanceTypes(taxYear int) (AllowanceTypesRes, error) {
+	return AllowanceTypesRes{}, m.err
+}
+
+func (m *mockAllowanceDeductionUsecaseCaseError) UpdateMaxDeduction(key string, req UpdateAllowanceDeductionReq) (UpdateAllowanceDeductionRes, error) {
+	return UpdateAllowanceDeductionRes{}, m.err
+}
+
+func (m *mockAllowanceDeductionUsecaseCaseError) UpdateMaxDeductions(taxYear int, maxDeductions []deduction.Deduction) error {
+	return m.err
+}
+
+func (m *mockAllowanceDeductionUsecaseCaseError) GetCapRate(taxYear int, key string) (money.Rate, error) {
+	return 0, m.err
+}
+
+func (m *mockAllowanceDeductionUsecaseCaseError) UpdateCapRate(key string, req UpdateAllowanceCapRateReq) (UpdateAllowanceCapRateRes, error) {
+	return UpdateAllowanceCapRateRes{}, m.err
+}
+
+func mockGetAllowanceTypesHttpReq(query string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/allowance-types"+query, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+func mockUpdateMaxDeductionHttpReq(key string, reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/deductions/"+key, strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("allowanceType")
+	c.SetParamValues(key)
+
+	return e, c, rec
+}
+
+func mockUpdateCapRateHttpReq(key string, reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/deductions/"+key+"/cap-rate", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("allowanceType")
+	c.SetParamValues(key)
+
+	return e, c, rec
+}
+
+// GetAllowanceTypes
+func TestGetAllowanceTypesHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	usecase := &mockAllowanceDeductionUsecaseCaseSuccess{}
+	handler := NewAllowanceDeductionHttpHandler(usecase)
+
+	_, c, rec := mockGetAllowanceTypesHttpReq("?taxYear=2568")
+
+	// Act
+	err := handler.GetAllowanceTypes(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2568, usecase.taxYear)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.JSONEq(t, `{
+		"taxYear": 2568,
+		"allowanceTypes": [
+			{
+				"allowanceType": "donation",
+				"maxDeduction": 100000,
+				"minMaxDeduction": 0,
+				"maxMaxDeduction": 0
+			}
+		]
+	}`, rec.Body.String())
+}
+
+func TestGetAllowanceTypesHandler_ShouldGetError_WhenWrongInputOrUsecaseFail(t *testing.T) {
+	testCases := []struct {
+		name         string
+		query        string
+		err          error
+		expectedCode int
+	}{
+		{"Test case 1", "?taxYear=asdasd", nil, http.StatusBadRequest},
+		{"Test case 2", "?taxYear=2599", deduction.ErrDeductionNotFound, http.StatusNotFound},
+		{"Test case 3", "", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAllowanceDeductionHttpHandler(&mockAllowanceDeductionUsecaseCaseError{err: tc.err})
+			_, c, _ := mockGetAllowanceTypesHttpReq(tc.query)
+
+			// Act
+			err := handler.GetAllowanceTypes(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCode, he.Code)
+		})
+	}
+}
+
+// UpdateMaxDeduction
+func TestUpdateMaxDeductionHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	usecase := &mockAllowanceDeductionUsecaseCaseSuccess{}
+	handler := NewAllowanceDeductionHttpHandler(usecase)
+
+	_, c, rec := mockUpdateMaxDeductionHttpReq(allowanceType.KReceipt, `{
+		"amount": 70000.0
+	}`)
+
+	// Act
+	err := handler.UpdateMaxDeduction(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, allowanceType.KReceipt, usecase.key)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.JSONEq(t, `{
+		"taxYear": 2567,
+		"allowanceType": "k-receipt",
+		"maxDeduction": 70000
+	}`, rec.Body.String())
+}
+
+func TestUpdateMaxDeductionHandler_ShouldGetError_WhenWrongInputOrUsecaseFail(t *testing.T) {
+	testCases := []struct {
+		name         string
+		reqBody      string
+		err          error
+		expectedCode int
+	}{
+		{"Test case 1", `{"amount": asdasd}`, nil, http.StatusBadRequest},
+		{"Test case 2", `{"amount": -1}`, nil, http.StatusBadRequest},
+		{"Test case 3", `{"amount": 1000}`, ErrAllowanceTypeNotFound, http.StatusNotFound},
+		{"Test case 4", `{"amount": 1000}`, ErrMaxDeductionOutOfRange, http.StatusBadRequest},
+		{"Test case 5", `{"amount": 1000}`, errors.New("database error"), http.StatusInternalServerError},
+		{"Test case 6", `{"taxYear": 2570, "amount": 1000}`, deduction.ErrDeductionNotFound, http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAllowanceDeductionHttpHandler(&mockAllowanceDeductionUsecaseCaseError{err: tc.err})
+			_, c, _ := mockUpdateMaxDeductionHttpReq(allowanceType.KReceipt, tc.reqBody)
+
+			// Act
+			err := handler.UpdateMaxDeduction(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCode, he.Code)
+		})
+	}
+}
+
+// UpdateCapRate
+func TestUpdateCapRateHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	usecase := &mockAllowanceDeductionUsecaseCaseSuccess{}
+	handler := NewAllowanceDeductionHttpHandler(usecase)
+
+	_, c, rec := mockUpdateCapRateHttpReq(allowanceType.Donation, `{
+		"capRate": 15.0
+	}`)
+
+	// Act
+	err := handler.UpdateCapRate(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, allowanceType.Donation, usecase.key)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.JSONEq(t, `{
+		"taxYear": 2567,
+		"allowanceType": "donation",
+		"capRate": 15
+	}`, rec.Body.String())
+}
+
+func TestUpdateCapRateHandler_ShouldGetError_WhenWrongInputOrUsecaseFail(t *testing.T) {
+	testCases := []struct {
+		name         string
+		reqBody      string
+		err          error
+		expectedCode int
+	}{
+		{"Test case 1", `{"capRate": asdasd}`, nil, http.StatusBadRequest},
+		{"Test case 2", `{"capRate": 0}`, nil, http.StatusBadRequest},
+		{"Test case 3", `{"capRate": 10}`, ErrAllowanceTypeNotFound, http.StatusNotFound},
+		{"Test case 4", `{"capRate": 10}`, ErrCapRateNotConfigurable, http.StatusBadRequest},
+		{"Test case 5", `{"capRate": 101}`, ErrCapRateOutOfRange, http.StatusBadRequest},
+		{"Test case 6", `{"capRate": 10}`, errors.New("database error"), http.StatusInternalServerError},
+		{"Test case 7", `{"taxYear": 2570, "capRate": 10}`, deduction.ErrDeductionNotFound, http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAllowanceDeductionHttpHandler(&mockAllowanceDeductionUsecaseCaseError{err: tc.err})
+			_, c, _ := mockUpdateCapRateHttpReq(allowanceType.Donation, tc.reqBody)
+
+			// Act
+			err := handler.UpdateCapRate(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCode, he.Code)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package allowance
+
+import "github.com/larb26656/assessment-tax/money"
+
+type AllowanceTypeRes struct {
+	AllowanceType   string      `json:"allowanceType"`
+	MaxDeduction    money.Money `json:"maxDeduction"`
+	MinMaxDeduction money.Money `json:"minMaxDeduction"`
+	MaxMaxDeduction money.Money `json:"maxMaxDeduction"`
+	CapRate         money.Rate  `json:"capRate,omitempty"`
+	CapBase         string      `json:"capBase,omitempty"`
+}
+
+type AllowanceTypesRes struct {
+	TaxYear        int                `json:"taxYear"`
+	AllowanceTypes []AllowanceTypeRes `json:"allowanceTypes"`
+}
+
+type UpdateAllowanceDeductionReq struct {
+	TaxYear int         `json:"taxYear" validate:"omitempty,gte=2500"`
+	Amount  money.Money `json:"amount" validate:"gte=0"`
+}
+
+type UpdateAllowanceDeductionRes struct {
+	TaxYear       int         `json:"taxYear"`
+	AllowanceType string      `json:"allowanceType"`
+	MaxDeduction  money.Money `json:"maxDeduction"`
+}
+
+type UpdateAllowanceCapRateReq struct {
+	TaxYear int        `json:"taxYear" validate:"omitempty,gte=2500"`
+	CapRate money.Rate `json:"capRate" validate:"gt=0"`
+}
+
+type UpdateAllowanceCapRateRes struct {
+	TaxYear       int        `json:"taxYear"`
+	AllowanceType string     `json:"allowanceType"`
+	CapRate       money.Rate `json:"capRate"`
+}
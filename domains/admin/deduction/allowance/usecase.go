@@ -0,0 +1,168 @@
+package allowance
+
+import (
+	"errors"
+
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/money"
+)
+
+var ErrAllowanceTypeNotFound = errors.New("allowance type not found")
+var ErrMaxDeductionOutOfRange = errors.New("max deduction out of range")
+var ErrCapRateNotConfigurable = errors.New("cap rate not configurable")
+var ErrCapRateOutOfRange = errors.New("cap rate out of range")
+
+type AllowanceDeductionUsecase interface {
+	GetMaxDeduction(taxYear int, key string) (money.Money, error)
+	GetCapRate(taxYear int, key string) (money.Rate, error)
+	GetAllowanceTypes(taxYear int) (AllowanceTypesRes, error)
+	UpdateMaxDeduction(key string, req UpdateAllowanceDeductionReq) (UpdateAllowanceDeductionRes, error)
+	UpdateMaxDeductions(taxYear int, maxDeductions []deduction.Deduction) error
+	UpdateCapRate(key string, req UpdateAllowanceCapRateReq) (UpdateAllowanceCapRateRes, error)
+}
+
+type allowanceDeductionUsecase struct {
+	deductionRepository deduction.DeductionRepository
+}
+
+func NewAllowanceDeductionUsecase(deductionRepository deduction.DeductionRepository) AllowanceDeductionUsecase {
+	return &allowanceDeductionUsecase{
+		deductionRepository: deductionRepository,
+	}
+}
+
+// GetMaxDeduction returns the cap of a registered allowance type from the tax year setting
+func (a *allowanceDeductionUsecase) GetMaxDeduction(taxYear int, key string) (money.Money, error) {
+	if _, ok := allowanceType.Get(key); !ok {
+		return 0, ErrAllowanceTypeNotFound
+	}
+
+	return a.deductionRepository.GetDeduction(taxYear, key)
+}
+
+// GetCapRate returns the percentage cap of a registered allowance type, it is read from the tax year
+// setting when the type has a CapRateKey
+func (a *allowanceDeductionUsecase) GetCapRate(taxYear int, key string) (money.Rate, error) {
+	registered, ok := allowanceType.Get(key)
+
+	if !ok {
+		return 0, ErrAllowanceTypeNotFound
+	}
+
+	if registered.CapRateKey == "" {
+		return registered.CapRate, nil
+	}
+
+	return a.deductionRepository.GetRate(taxYear, registered.CapRateKey)
+}
+
+func (a *allowanceDeductionUsecase) GetAllowanceTypes(taxYear int) (AllowanceTypesRes, error) {
+	res := AllowanceTypesRes{
+		TaxYear:        taxYear,
+		AllowanceTypes: []AllowanceTypeRes{},
+	}
+
+	for _, registered := range allowanceType.All() {
+		maxDeduction, err := a.GetMaxDeduction(taxYear, registered.Key)
+
+		if err != nil {
+			return AllowanceTypesRes{}, err
+		}
+
+		capRate, err := a.GetCapRate(taxYear, registered.Key)
+
+		if err != nil {
+			return AllowanceTypesRes{}, err
+		}
+
+		res.AllowanceTypes = append(res.AllowanceTypes, AllowanceTypeRes{
+			AllowanceType:   registered.Key,
+			MaxDeduction:    maxDeduction,
+			MinMaxDeduction: registered.MinMaxDeduction,
+			MaxMaxDeduction: registered.MaxMaxDeduction,
+			CapRate:         capRate,
+			CapBase:         registered.CapBase,
+		})
+	}
+
+	return res, nil
+}
+
+// checkMaxDeduction checks a cap against the bounds of its registered allowance type
+func checkMaxDeduction(key string, maxDeduction money.Money) error {
+	registered, ok := allowanceType.Get(key)
+
+	if !ok {
+		return ErrAllowanceTypeNotFound
+	}
+
+	if maxDeduction < registered.MinMaxDeduction || maxDeduction > registered.MaxMaxDeduction {
+		return ErrMaxDeductionOutOfRange
+	}
+
+	return nil
+}
+
+func (a *allowanceDeductionUsecase) UpdateMaxDeduction(key string, req UpdateAllowanceDeductionReq) (UpdateAllowanceDeductionRes, error) {
+	if err := checkMaxDeduction(key, req.Amount); err != nil {
+		return UpdateAllowanceDeductionRes{}, err
+	}
+
+	year := taxYear.Resolve(req.TaxYear)
+
+	err := a.deductionRepository.UpdateDeduction(year, key, req.Amount)
+
+	if err != nil {
+		return UpdateAllowanceDeductionRes{}, err
+	}
+
+	return UpdateAllowanceDeductionRes{
+		TaxYear:       year,
+		AllowanceType: key,
+		MaxDeduction:  req.Amount,
+	}, nil
+}
+
+// UpdateMaxDeductions sets several caps of a tax year together, every cap is checked before any is saved
+func (a *allowanceDeductionUsecase) UpdateMaxDeductions(taxYear int, maxDeductions []deduction.Deduction) error {
+	for _, maxDeduction := range maxDeductions {
+		if err := checkMaxDeduction(maxDeduction.Key, maxDeduction.Value); err != nil {
+			return err
+		}
+	}
+
+	return a.deductionRepository.UpdateDeductions(taxYear, maxDeductions)
+}
+
+// UpdateCapRate sets the percentage cap of an allowance type with a CapRateKey, above 0 and up to MaxCapRate
+func (a *allowanceDeductionUsecase) UpdateCapRate(key string, req UpdateAllowanceCapRateReq) (UpdateAllowanceCapRateRes, error) {
+	registered, ok := allowanceType.Get(key)
+
+	if !ok {
+		return UpdateAllowanceCapRateRes{}, ErrAllowanceTypeNotFound
+	}
+
+	if registered.CapRateKey == "" {
+		return UpdateAllowanceCapRateRes{}, ErrCapRateNotConfigurable
+	}
+
+	if req.CapRate <= 0 || req.CapRate > registered.MaxCapRate {
+		return UpdateAllowanceCapRateRes{}, ErrCapRateOutOfRange
+	}
+
+	year := taxYear.Resolve(req.TaxYear)
+
+	err := a.deductionRepository.UpdateRate(year, registered.CapRateKey, req.CapRate)
+
+	if err != nil {
+		return UpdateAllowanceCapRateRes{}, err
+	}
+
+	return UpdateAllowanceCapRateRes{
+		TaxYear:       year,
+		AllowanceType: key,
+		CapRate:       req.CapRate,
+	}, nil
+}
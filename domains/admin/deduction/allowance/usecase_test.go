@@ -0,0 +1,373 @@
+package allowance
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/money"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDeductionRepositoryCaseDeductionFound struct {
+	taxYear    int
+	key        string
+	amount     money.Money
+	rate       money.Rate
+	deductions []deduction.Deduction
+}
+
+func (p *mockDeductionRepositoryCaseDeductionFound) GetDeduction(taxYear int, key string) (money.Money, error) {
+	p.taxYear = taxYear
+	p.key = key
+	return money.FromBaht(50000), nil
+}
+
+func (p *mockDeductionRepositoryCaseDeductionFound) UpdateDeduction(taxYear int, key string, deduction money.Money) error {
+	p.taxYear = taxYear
+	p.key = key
+	p.amount = deduction
+	return nil
+}
+
+func (p *mockDeductionRepositoryCaseDeductionFound) UpdateDeductions(taxYear int, deductions []deduction.Deduction) error {
+	p.taxYear = taxYear
+	p.deductions = deductions
+	return nil
+}
+
+func (p *mockDeductionRepositoryCaseDeductionFound) GetRate(taxYear int, key string) (money.Rate, error) {
+	p.taxYear = taxYear
+	p.key = key
+	return money.FromPercent(12), nil
+}
+
+func (p *mockDeductionRepositoryCaseDeductionFound) UpdateRate(taxYear int, key string, rate money.Rate) error {
+	p.taxYear = taxYear
+	p.key = key
+	p.rate = rate
+	return nil
+}
+
+type mockDeductionRepositoryCaseDeductionNotFound struct {
+}
+
+func (p *mockDeductionRepositoryCaseDeductionNotFound) GetDeduction(taxYear int, key string) (money.Money, error) {
+	return 0, deduction.ErrDeductionNotFound
+}
+
+func (p *mockDeductionRepositoryCaseDeductionNotFound) UpdateDeduction(taxYear int, key string, deduction money.Money) error {
+	return errors.New("Update deduction error")
+}
+
+func (p *mockDeductionRepositoryCaseDeductionNotFound) UpdateDeductions(taxYear int, deductions []deduction.Deduction) error {
+	return errors.New("Update deduction error")
+}
+
+func (p *mockDeductionRepositoryCaseDeductionNotFound) GetRate(taxYear int, key string) (money.Rate, error) {
+	return 0, deduction.ErrDeductionNotFound
+}
+
+func (p *mockDeductionRepositoryCaseDeductionNotFound) UpdateRate(taxYear int, key string, rate money.Rate) error {
+	return deduction.ErrDeductionNotFound
+}
+
+// GetMaxDeduction
+func TestGetMaxDeduction_ShouldReadSetting_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepositoryCaseDeductionFound{}
+	usecase := NewAllowanceDeductionUsecase(repo)
+
+	// Act
+	result, err := usecase.GetMaxDeduction(2568, allowanceType.KReceipt)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromBaht(50000), result)
+	assert.Equal(t, 2568, repo.taxYear)
+	assert.Equal(t, allowanceType.KReceipt, repo.key)
+}
+
+func TestGetMaxDeduction_ShouldReturnErr_WhenAllowanceTypeNotFound(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepositoryCaseDeductionFound{}
+	usecase := NewAllowanceDeductionUsecase(repo)
+
+	// Act
+	_, err := usecase.GetMaxDeduction(2567, "unknown")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrAllowanceTypeNotFound)
+}
+
+// GetCapRate
+func TestGetCapRate_ShouldReadSetting_WhenCapRateKeyIsSet(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepositoryCaseDeductionFound{}
+	usecase := NewAllowanceDeductionUsecase(repo)
+
+	// Act
+	result, err := usecase.GetCapRate(2568, allowanceType.Donation)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromPercent(12), result)
+	assert.Equal(t, 2568, repo.taxYear)
+	assert.Equal(t, allowanceType.DonationCapRate, repo.key)
+}
+
+func TestGetCapRate_ShouldReturnRegisteredCapRate_WhenCapRateKeyIsNotSet(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepositoryCaseDeductionFound{}
+	usecase := NewAllowanceDeductionUsecase(repo)
+
+	// Act
+	result, err := usecase.GetCapRate(2568, allowanceType.RMF)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromPercent(30), result)
+	assert.Equal(t, "", repo.key)
+}
+
+func TestGetCapRate_ShouldReturnErr_WhenAllowanceTypeNotFound(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepositoryCaseDeductionFound{}
+	usecase := NewAllowanceDeductionUsecase(repo)
+
+	// Act
+	_, err := usecase.GetCapRate(2567, "unknown")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrAllowanceTypeNotFound)
+}
+
+// GetAllowanceTypes
+func TestGetAllowanceTypes_ShouldReturnEveryRegisteredType(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepositoryCaseDeductionFound{}
+	usecase := NewAllowanceDeductionUsecase(repo)
+
+	// Act
+	result, err := usecase.GetAllowanceTypes(2567)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2567, result.TaxYear)
+	assert.Len(t, result.AllowanceTypes, len(allowanceType.All()))
+	assert.Equal(t, AllowanceTypeRes{
+		AllowanceType:   allowanceType.KReceipt,
+		MaxDeduction:    money.FromBaht(50000),
+		MaxMaxDeduction: money.FromBaht(100000),
+	}, result.AllowanceTypes[0])
+	assert.Equal(t, AllowanceTypeRes{
+		AllowanceType:   allowanceType.Donation,
+		MaxDeduction:    money.FromBaht(50000),
+		MaxMaxDeduction: money.FromBaht(100000),
+		CapRate:         money.FromPercent(12),
+		CapBase:         allowanceType.CapBaseNetIncome,
+	}, result.AllowanceTypes[len(result.AllowanceTypes)-1])
+}
+
+func TestGetAllowanceTypes_ShouldReturnErr_WhenDeductionNotFound(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepositoryCaseDeductionNotFound{}
+	usecase := NewAllowanceDeductionUsecase(repo)
+
+	// Act
+	_, err := usecase.GetAllowanceTypes(2599)
+
+	// Assert
+	assert.ErrorIs(t, err, deduction.ErrDeductionNotFound)
+}
+
+// UpdateMaxDeduction
+func TestUpdateMaxDeduction_ShouldReturnErr_WhenWrongInput(t *testing.T) {
+	testCases := []struct {
+		name     string
+		key      string
+		amount   money.Money
+		expected error
+	}{
+		{"Test case 1", "unknown", money.FromBaht(50000), ErrAllowanceTypeNotFound},
+		{"Test case 2", allowanceType.Donation, money.FromBaht(100001), ErrMaxDeductionOutOfRange},
+		{"Test case 3", allowanceType.KReceipt, money.FromBaht(-1), ErrMaxDeductionOutOfRange},
+		{"Test case 4", allowanceType.KReceipt, money.FromBaht(100001), ErrMaxDeductionOutOfRange},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			repo := &mockDeductionRepositoryCaseDeductionFound{}
+			usecase := NewAllowanceDeductionUsecase(repo)
+
+			// Act
+			_, err := usecase.UpdateMaxDeduction(tc.key, UpdateAllowanceDeductionReq{Amount: tc.amount})
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expected)
+			assert.Equal(t, "", repo.key)
+		})
+	}
+}
+
+func TestUpdateMaxDeduction_ShouldReturnErr_WhenUpdateDeductionFail(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepositoryCaseDeductionNotFound{}
+	usecase := NewAllowanceDeductionUsecase(repo)
+
+	// Act
+	_, err := usecase.UpdateMaxDeduction(allowanceType.KReceipt, UpdateAllowanceDeductionReq{Amount: money.FromBaht(50000)})
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestUpdateMaxDeduction_ShouldSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepositoryCaseDeductionFound{}
+	usecase := NewAllowanceDeductionUsecase(repo)
+
+	// Act
+	result, err := usecase.UpdateMaxDeduction(allowanceType.KReceipt, UpdateAllowanceDeductionReq{Amount: money.FromBaht(70000)})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, taxYear.Default, repo.taxYear)
+	assert.Equal(t, allowanceType.KReceipt, repo.key)
+	assert.Equal(t, money.FromBaht(70000), repo.amount)
+	assert.Equal(t, UpdateAllowanceDeductionRes{
+		TaxYear:       taxYear.Default,
+		AllowanceType: allowanceType.KReceipt,
+		MaxDeduction:  money.FromBaht(70000),
+	}, result)
+}
+
+// UpdateMaxDeductions
+func TestUpdateMaxDeductions_ShouldSaveNothing_WhenOneIsWrong(t *testing.T) {
+	testCases := []struct {
+		name          string
+		maxDeductions []deduction.Deduction
+		expected      error
+	}{
+		{"Test case 1", []deduction.Deduction{
+			{Key: allowanceType.LifeInsurance, Value: money.FromBaht(80000)},
+			{Key: allowanceType.HealthInsurance, Value: money.FromBaht(25001)},
+		}, ErrMaxDeductionOutOfRange},
+		{"Test case 2", []deduction.Deduction{
+			{Key: allowanceType.LifeInsurance, Value: money.FromBaht(80000)},
+			{Key: "unknown", Value: money.FromBaht(1000)},
+		}, ErrAllowanceTypeNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			repo := &mockDeductionRepositoryCaseDeductionFound{}
+			usecase := NewAllowanceDeductionUsecase(repo)
+
+			// Act
+			err := usecase.UpdateMaxDeductions(2568, tc.maxDeductions)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expected)
+			assert.Nil(t, repo.deductions)
+		})
+	}
+}
+
+func TestUpdateMaxDeductions_ShouldReturnErr_WhenUpdateDeductionsFail(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepositoryCaseDeductionNotFound{}
+	usecase := NewAllowanceDeductionUsecase(repo)
+
+	// Act
+	err := usecase.UpdateMaxDeductions(2568, []deduction.Deduction{
+		{Key: allowanceType.LifeInsurance, Value: money.FromBaht(80000)},
+	})
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestUpdateMaxDeductions_ShouldSaveTogether_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepositoryCaseDeductionFound{}
+	usecase := NewAllowanceDeductionUsecase(repo)
+	maxDeductions := []deduction.Deduction{
+		{Key: allowanceType.LifeInsurance, Value: money.FromBaht(80000)},
+		{Key: allowanceType.HealthInsurance, Value: money.FromBaht(20000)},
+	}
+
+	// Act
+	err := usecase.UpdateMaxDeductions(2568, maxDeductions)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2568, repo.taxYear)
+	assert.Equal(t, maxDeductions, repo.deductions)
+}
+
+// UpdateCapRate
+func TestUpdateCapRate_ShouldReturnErr_WhenWrongInput(t *testing.T) {
+	testCases := []struct {
+		name     string
+		key      string
+		capRate  money.Rate
+		expected error
+	}{
+		{"Test case 1", "unknown", money.FromPercent(10), ErrAllowanceTypeNotFound},
+		{"Test case 2", allowanceType.KReceipt, money.FromPercent(10), ErrCapRateNotConfigurable},
+		{"Test case 3", allowanceType.Donation, money.FromPercent(0), ErrCapRateOutOfRange},
+		{"Test case 4", allowanceType.Donation, money.FromPercent(101), ErrCapRateOutOfRange},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			repo := &mockDeductionRepositoryCaseDeductionFound{}
+			usecase := NewAllowanceDeductionUsecase(repo)
+
+			// Act
+			_, err := usecase.UpdateCapRate(tc.key, UpdateAllowanceCapRateReq{CapRate: tc.capRate})
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expected)
+			assert.Equal(t, "", repo.key)
+		})
+	}
+}
+
+func TestUpdateCapRate_ShouldReturnErr_WhenUpdateRateFail(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepositoryCaseDeductionNotFound{}
+	usecase := NewAllowanceDeductionUsecase(repo)
+
+	// Act
+	_, err := usecase.UpdateCapRate(allowanceType.Donation, UpdateAllowanceCapRateReq{TaxYear: 2570, CapRate: money.FromPercent(15)})
+
+	// Assert
+	assert.ErrorIs(t, err, deduction.ErrDeductionNotFound)
+}
+
+func TestUpdateCapRate_ShouldSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepositoryCaseDeductionFound{}
+	usecase := NewAllowanceDeductionUsecase(repo)
+
+	// Act
+	result, err := usecase.UpdateCapRate(allowanceType.Donation, UpdateAllowanceCapRateReq{CapRate: money.FromPercent(15)})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, taxYear.Default, repo.taxYear)
+	assert.Equal(t, allowanceType.DonationCapRate, repo.key)
+	assert.Equal(t, money.FromPercent(15), repo.rate)
+	assert.Equal(t, UpdateAllowanceCapRateRes{
+		TaxYear:       taxYear.Default,
+		AllowanceType: allowanceType.Donation,
+		CapRate:       money.FromPercent(15),
+	}, result)
+}
@@ -0,0 +1,88 @@
+package dependent
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+)
+
+type DependentDeductionHttpHandler interface {
+	GetDeduction(c echo.Context) error
+	UpdateDeduction(c echo.Context) error
+}
+
+type dependentDeductionHttpHandler struct {
+	dependentDeductionUsecase DependentDeductionUsecase
+}
+
+func NewDependentDeductionHttpHandler(dependentDeductionUsecase DependentDeductionUsecase) DependentDeductionHttpHandler {
+	return &dependentDeductionHttpHandler{
+		dependentDeductionUsecase: dependentDeductionUsecase,
+	}
+}
+
+func (p *dependentDeductionHttpHandler) GetDeduction(c echo.Context) error {
+	year := 0
+
+	err := echo.QueryParamsBinder(c).Int("taxYear", &year).BindError()
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	year = taxYear.Resolve(year)
+
+	dependent, err := p.dependentDeductionUsecase.GetDeduction(year)
+
+	if errors.Is(err, deduction.ErrDeductionNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Deduction not found")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, DependentDeductionRes{
+		TaxYear:           year,
+		Spouse:            dependent.Spouse,
+		Child:             dependent.Child,
+		ChildExtra:        dependent.ChildExtra,
+		Parent:            dependent.Parent,
+		ParentMaxIncome:   dependent.ParentMaxIncome,
+		DisabledDependent: dependent.DisabledDependent,
+	})
+}
+
+func (p *dependentDeductionHttpHandler) UpdateDeduction(c echo.Context) error {
+	var req UpdateDependentDeductionReq
+
+	err := c.Bind(&req)
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if err = c.Validate(req); err != nil {
+		return err
+	}
+
+	res, err := p.dependentDeductionUsecase.UpdateDeduction(req)
+
+	if errors.Is(err, deduction.ErrDeductionNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tax year not found, clone the tax year first")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,229 @@
+package dependent
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/money"
+	myValidator "github.com/larb26656/assessment-tax/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDependentDeductionUsecase struct {
+	taxYear int
+	err     error
+}
+
+func (m *mockDependentDeductionUsecase) GetDeduction(taxYear int) (DependentDeduction, error) {
+	m.taxYear = taxYear
+
+	if m.err != nil {
+		return DependentDeduction{}, m.err
+	}
+
+	return DependentDeduction{
+		Spouse:            money.FromBaht(60000),
+		Child:             money.FromBaht(30000),
+		ChildExtra:        money.FromBaht(30000),
+		Parent:            money.FromBaht(30000),
+		ParentMaxIncome:   money.FromBaht(30000),
+		DisabledDependent: money.FromBaht(60000),
+	}, nil
+}
+
+func (m *mockDependentDeductionUsecase) UpdateDeduction(req UpdateDependentDeductionReq) (UpdateDependentDeductionRes, error) {
+	if m.err != nil {
+		return UpdateDependentDeductionRes{}, m.err
+	}
+
+	return UpdateDependentDeductionRes{
+		TaxYear:           2567,
+		Spouse:            req.Spouse,
+		Child:             req.Child,
+		ChildExtra:        req.ChildExtra,
+		Parent:            req.Parent,
+		ParentMaxIncome:   req.ParentMaxIncome,
+		DisabledDependent: req.DisabledDependent,
+	}, nil
+}
+
+func mockUpdateDeductionHttpReq(reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/deductions/dependents", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+func mockGetDeductionHttpReq(query string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/deductions/dependents"+query, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+// UpdateDeduction
+func TestUpdateDeductionHandler_ShouldGetBadRequest_WhenWrongInput(t *testing.T) {
+	// Arrange
+	handler := NewDependentDeductionHttpHandler(&mockDependentDeductionUsecase{})
+
+	testCases := []struct {
+		name    string
+		reqBody string
+	}{
+		{"Test case 1", `{"spouse": asdasd}`},
+		{"Test case 2", `{"spouse": -1}`},
+		{"Test case 3", `{"child": 100001.0}`},
+		{"Test case 4", `{"parentMaxIncome": -1}`},
+		{"Test case 5", `{"disabledDependent": 100001.0}`},
+		{"Test case 6", `{"taxYear": 2499}`},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, _ := mockUpdateDeductionHttpReq(tc.reqBody)
+			err := handler.UpdateDeduction(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+func TestUpdateDeductionHandler_ShouldGetInternalServerError_WhenErrorOnUpdateDeduction(t *testing.T) {
+	// Arrange
+	handler := NewDependentDeductionHttpHandler(&mockDependentDeductionUsecase{err: errors.New("error on update")})
+	_, c, _ := mockUpdateDeductionHttpReq(`{"spouse": 60000}`)
+
+	// Act
+	err := handler.UpdateDeduction(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, he.Code)
+}
+
+func TestUpdateDeductionHandler_ShouldGetNotFound_WhenTaxYearNotFound(t *testing.T) {
+	// Arrange
+	handler := NewDependentDeductionHttpHandler(&mockDependentDeductionUsecase{err: deduction.ErrDeductionNotFound})
+	_, c, _ := mockUpdateDeductionHttpReq(`{"spouse": 60000}`)
+
+	// Act
+	err := handler.UpdateDeduction(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, he.Code)
+}
+
+func TestUpdateDeductionHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	handler := NewDependentDeductionHttpHandler(&mockDependentDeductionUsecase{})
+	_, c, rec := mockUpdateDeductionHttpReq(`{
+		"spouse": 60000,
+		"child": 30000,
+		"childExtra": 30000,
+		"parent": 30000,
+		"parentMaxIncome": 30000,
+		"disabledDependent": 60000
+	}`)
+
+	// Act
+	err := handler.UpdateDeduction(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.JSONEq(t, `{
+		"taxYear": 2567,
+		"spouse": 60000,
+		"child": 30000,
+		"childExtra": 30000,
+		"parent": 30000,
+		"parentMaxIncome": 30000,
+		"disabledDependent": 60000
+	}`, rec.Body.String())
+}
+
+// GetDeduction
+func TestGetDeductionHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	testCases := []struct {
+		name             string
+		query            string
+		expectedTaxYear  int
+		expectedResponse string
+	}{
+		{"Test case 1", "", 2567, `{"taxYear": 2567, "spouse": 60000, "child": 30000, "childExtra": 30000, "parent": 30000, "parentMaxIncome": 30000, "disabledDependent": 60000}`},
+		{"Test case 2", "?taxYear=2568", 2568, `{"taxYear": 2568, "spouse": 60000, "child": 30000, "childExtra": 30000, "parent": 30000, "parentMaxIncome": 30000, "disabledDependent": 60000}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			usecase := &mockDependentDeductionUsecase{}
+			handler := NewDependentDeductionHttpHandler(usecase)
+			_, c, rec := mockGetDeductionHttpReq(tc.query)
+
+			// Act
+			err := handler.GetDeduction(c)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTaxYear, usecase.taxYear)
+			assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+			assert.JSONEq(t, tc.expectedResponse, rec.Body.String())
+		})
+	}
+}
+
+func TestGetDeductionHandler_ShouldGetError_WhenWrongInputOrUsecaseFail(t *testing.T) {
+	testCases := []struct {
+		name         string
+		query        string
+		err          error
+		expectedCode int
+	}{
+		{"Test case 1", "?taxYear=asdasd", nil, http.StatusBadRequest},
+		{"Test case 2", "?taxYear=2599", deduction.ErrDeductionNotFound, http.StatusNotFound},
+		{"Test case 3", "", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler := NewDependentDeductionHttpHandler(&mockDependentDeductionUsecase{err: tc.err})
+			_, c, _ := mockGetDeductionHttpReq(tc.query)
+
+			// Act
+			err := handler.GetDeduction(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCode, he.Code)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package dependent
+
+import "github.com/larb26656/assessment-tax/money"
+
+type UpdateDependentDeductionReq struct {
+	TaxYear           int         `json:"taxYear" validate:"omitempty,gte=2500"`
+	Spouse            money.Money `json:"spouse" validate:"gte=0,lte=100000"`
+	Child             money.Money `json:"child" validate:"gte=0,lte=100000"`
+	ChildExtra        money.Money `json:"childExtra" validate:"gte=0,lte=100000"`
+	Parent            money.Money `json:"parent" validate:"gte=0,lte=100000"`
+	ParentMaxIncome   money.Money `json:"parentMaxIncome" validate:"gte=0,lte=100000"`
+	DisabledDependent money.Money `json:"disabledDependent" validate:"gte=0,lte=100000"`
+}
+
+type UpdateDependentDeductionRes struct {
+	TaxYear           int         `json:"taxYear,omitempty"`
+	Spouse            money.Money `json:"spouse"`
+	Child             money.Money `json:"child"`
+	ChildExtra        money.Money `json:"childExtra"`
+	Parent            money.Money `json:"parent"`
+	ParentMaxIncome   money.Money `json:"parentMaxIncome"`
+	DisabledDependent money.Money `json:"disabledDependent"`
+}
+
+// DependentDeduction is the fixed deduction of each dependent, ParentMaxIncome is the highest
+// yearly income a parent can have to be deducted
+type DependentDeduction struct {
+	Spouse            money.Money
+	Child             money.Money
+	ChildExtra        money.Money
+	Parent            money.Money
+	ParentMaxIncome   money.Money
+	DisabledDependent money.Money
+}
+
+type DependentDeductionRes struct {
+	TaxYear           int         `json:"taxYear"`
+	Spouse            money.Money `json:"spouse"`
+	Child             money.Money `json:"child"`
+	ChildExtra        money.Money `json:"childExtra"`
+	Parent            money.Money `json:"parent"`
+	ParentMaxIncome   money.Money `json:"parentMaxIncome"`
+	DisabledDependent money.Money `json:"disabledDependent"`
+}
@@ -0,0 +1,78 @@
+package dependent
+
+import (
+	"github.com/larb26656/assessment-tax/constant/deductionType"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/money"
+)
+
+type DependentDeductionUsecase interface {
+	GetDeduction(taxYear int) (DependentDeduction, error)
+	UpdateDeduction(req UpdateDependentDeductionReq) (UpdateDependentDeductionRes, error)
+}
+
+type dependentDeductionUsecase struct {
+	deductionRepository deduction.DeductionRepository
+}
+
+func NewDependentDeductionUsecase(deductionRepository deduction.DeductionRepository) DependentDeductionUsecase {
+	return &dependentDeductionUsecase{
+		deductionRepository: deductionRepository,
+	}
+}
+
+func (p *dependentDeductionUsecase) GetDeduction(taxYear int) (DependentDeduction, error) {
+	var res DependentDeduction
+
+	targets := []struct {
+		key       string
+		deduction *money.Money
+	}{
+		{deductionType.Spouse, &res.Spouse},
+		{deductionType.Child, &res.Child},
+		{deductionType.ChildExtra, &res.ChildExtra},
+		{deductionType.Parent, &res.Parent},
+		{deductionType.ParentMaxIncome, &res.ParentMaxIncome},
+		{deductionType.DisabledDependent, &res.DisabledDependent},
+	}
+
+	for _, target := range targets {
+		deduction, err := p.deductionRepository.GetDeduction(taxYear, target.key)
+
+		if err != nil {
+			return DependentDeduction{}, err
+		}
+
+		*target.deduction = deduction
+	}
+
+	return res, nil
+}
+
+func (p *dependentDeductionUsecase) UpdateDeduction(req UpdateDependentDeductionReq) (UpdateDependentDeductionRes, error) {
+	year := taxYear.Resolve(req.TaxYear)
+
+	err := p.deductionRepository.UpdateDeductions(year, []deduction.Deduction{
+		{Key: deductionType.Spouse, Value: req.Spouse},
+		{Key: deductionType.Child, Value: req.Child},
+		{Key: deductionType.ChildExtra, Value: req.ChildExtra},
+		{Key: deductionType.Parent, Value: req.Parent},
+		{Key: deductionType.ParentMaxIncome, Value: req.ParentMaxIncome},
+		{Key: deductionType.DisabledDependent, Value: req.DisabledDependent},
+	})
+
+	if err != nil {
+		return UpdateDependentDeductionRes{}, err
+	}
+
+	return UpdateDependentDeductionRes{
+		TaxYear:           year,
+		Spouse:            req.Spouse,
+		Child:             req.Child,
+		ChildExtra:        req.ChildExtra,
+		Parent:            req.Parent,
+		ParentMaxIncome:   req.ParentMaxIncome,
+		DisabledDependent: req.DisabledDependent,
+	}, nil
+}
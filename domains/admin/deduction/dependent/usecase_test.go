@@ -0,0 +1,158 @@
+package dependent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/larb26656/assessment-tax/constant/deductionType"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/money"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDeductionRepository struct {
+	taxYear    int
+	deductions map[string]money.Money
+	err        error
+}
+
+func (p *mockDeductionRepository) GetDeduction(taxYear int, key string) (money.Money, error) {
+	p.taxYear = taxYear
+
+	if p.err != nil {
+		return 0, p.err
+	}
+
+	return p.deductions[key], nil
+}
+
+func (p *mockDeductionRepository) UpdateDeduction(taxYear int, key string, deduction money.Money) error {
+	p.taxYear = taxYear
+
+	if p.err != nil {
+		return p.err
+	}
+
+	p.deductions[key] = deduction
+	return nil
+}
+
+func (p *mockDeductionRepository) UpdateDeductions(taxYear int, deductions []deduction.Deduction) error {
+	p.taxYear = taxYear
+
+	if p.err != nil {
+		return p.err
+	}
+
+	for _, deduction := range deductions {
+		p.deductions[deduction.Key] = deduction.Value
+	}
+
+	return nil
+}
+
+func (p *mockDeductionRepository) GetRate(taxYear int, key string) (money.Rate, error) {
+	return 0, nil
+}
+
+func (p *mockDeductionRepository) UpdateRate(taxYear int, key string, rate money.Rate) error {
+	return nil
+}
+
+// GetDeduction
+func TestGetDeduction_ShouldReturnErr_WhenDeductionNotFound(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepository{err: deduction.ErrDeductionNotFound}
+	usecase := NewDependentDeductionUsecase(repo)
+
+	// Act
+	_, err := usecase.GetDeduction(2568)
+
+	// Assert
+	assert.ErrorIs(t, err, deduction.ErrDeductionNotFound)
+	assert.Equal(t, 2568, repo.taxYear)
+}
+
+func TestGetDeduction_ShouldReturnDeduction_WhenDeductionFound(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepository{deductions: map[string]money.Money{
+		deductionType.Spouse:            money.FromBaht(60000),
+		deductionType.Child:             money.FromBaht(30000),
+		deductionType.ChildExtra:        money.FromBaht(30000),
+		deductionType.Parent:            money.FromBaht(30000),
+		deductionType.ParentMaxIncome:   money.FromBaht(30000),
+		deductionType.DisabledDependent: money.FromBaht(60000),
+	}}
+	usecase := NewDependentDeductionUsecase(repo)
+
+	// Act
+	result, err := usecase.GetDeduction(2568)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2568, repo.taxYear)
+	assert.Equal(t, DependentDeduction{
+		Spouse:            money.FromBaht(60000),
+		Child:             money.FromBaht(30000),
+		ChildExtra:        money.FromBaht(30000),
+		Parent:            money.FromBaht(30000),
+		ParentMaxIncome:   money.FromBaht(30000),
+		DisabledDependent: money.FromBaht(60000),
+	}, result)
+}
+
+// UpdateDeduction
+func TestUpdateDeduction_ShouldReturnError_WhenUpdateDeductionFail(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepository{err: errors.New("Update deduction error")}
+	usecase := NewDependentDeductionUsecase(repo)
+
+	// Act
+	_, err := usecase.UpdateDeduction(UpdateDependentDeductionReq{
+		Spouse: money.FromBaht(60000),
+	})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, taxYear.Default, repo.taxYear)
+}
+
+func TestUpdateDeduction_ShouldSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepository{deductions: map[string]money.Money{}}
+	usecase := NewDependentDeductionUsecase(repo)
+	req := UpdateDependentDeductionReq{
+		TaxYear:           2568,
+		Spouse:            money.FromBaht(60000),
+		Child:             money.FromBaht(30000),
+		ChildExtra:        money.FromBaht(30000),
+		Parent:            money.FromBaht(30000),
+		ParentMaxIncome:   money.FromBaht(30000),
+		DisabledDependent: money.FromBaht(60000),
+	}
+
+	// Act
+	result, err := usecase.UpdateDeduction(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2568, repo.taxYear)
+	assert.Equal(t, map[string]money.Money{
+		deductionType.Spouse:            money.FromBaht(60000),
+		deductionType.Child:             money.FromBaht(30000),
+		deductionType.ChildExtra:        money.FromBaht(30000),
+		deductionType.Parent:            money.FromBaht(30000),
+		deductionType.ParentMaxIncome:   money.FromBaht(30000),
+		deductionType.DisabledDependent: money.FromBaht(60000),
+	}, repo.deductions)
+	assert.Equal(t, UpdateDependentDeductionRes{
+		TaxYear:           2568,
+		Spouse:            money.FromBaht(60000),
+		Child:             money.FromBaht(30000),
+		ChildExtra:        money.FromBaht(30000),
+		Parent:            money.FromBaht(30000),
+		ParentMaxIncome:   money.FromBaht(30000),
+		DisabledDependent: money.FromBaht(60000),
+	}, result)
+}
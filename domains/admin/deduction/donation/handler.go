@@ -0,0 +1,97 @@
+package donation
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/allowance"
+)
+
+type DonationDeductionHttpHandler interface {
+	GetDeduction(c echo.Context) error
+	UpdateDeduction(c echo.Context) error
+}
+
+type donationDeductionHttpHandler struct {
+	allowanceDeductionUsecase allowance.AllowanceDeductionUsecase
+}
+
+// NewDonationDeductionHttpHandler keeps the donation endpoints, the cap and its bounds are handled by the
+// allowance type registry like any other allowance type
+func NewDonationDeductionHttpHandler(allowanceDeductionUsecase allowance.AllowanceDeductionUsecase) DonationDeductionHttpHandler {
+	return &donationDeductionHttpHandler{
+		allowanceDeductionUsecase: allowanceDeductionUsecase,
+	}
+}
+
+func (p *donationDeductionHttpHandler) GetDeduction(c echo.Context) error {
+	year := 0
+
+	err := echo.QueryParamsBinder(c).Int("taxYear", &year).BindError()
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	year = taxYear.Resolve(year)
+
+	donation, err := p.allowanceDeductionUsecase.GetMaxDeduction(year, allowanceType.Donation)
+
+	if errors.Is(err, deduction.ErrDeductionNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Deduction not found")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, DonationDeductionRes{
+		TaxYear:  year,
+		Donation: donation,
+	})
+}
+
+func (p *donationDeductionHttpHandler) UpdateDeduction(c echo.Context) error {
+	var req UpdateDonationDeductionReq
+
+	err := c.Bind(&req)
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if err = c.Validate(req); err != nil {
+		return err
+	}
+
+	res, err := p.allowanceDeductionUsecase.UpdateMaxDeduction(allowanceType.Donation, allowance.UpdateAllowanceDeductionReq{
+		TaxYear: req.TaxYear,
+		Amount:  req.Amount,
+	})
+
+	if errors.Is(err, allowance.ErrMaxDeductionOutOfRange) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Max deduction out of range")
+	}
+
+	if errors.Is(err, deduction.ErrDeductionNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tax year not found, clone the tax year first")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, UpdateDonationDeductionRes{
+		TaxYear:  res.TaxYear,
+		Donation: res.MaxDeduction,
+	})
+}
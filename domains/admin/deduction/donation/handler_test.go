@@ -0,0 +1,290 @@
+package donation
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/allowance"
+	"github.com/larb26656/assessment-tax/money"
+	myValidator "github.com/larb26656/assessment-tax/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDeductionRepository struct {
+	taxYear int
+	key     string
+	amount  money.Money
+	err     error
+}
+
+func (m *mockDeductionRepository) GetDeduction(taxYear int, key string) (money.Money, error) {
+	m.taxYear = taxYear
+	m.key = key
+
+	if m.err != nil {
+		return 0, m.err
+	}
+
+	return money.FromBaht(80000), nil
+}
+
+func (m *mockDeductionRepository) UpdateDeduction(taxYear int, key string, deduction money.Money) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.taxYear = taxYear
+	m.key = key
+	m.amount = deduction
+	return nil
+}
+
+func (m *mockDeductionRepository) UpdateDeductions(taxYear int, deductions []deduction.Deduction) error {
+	return m.err
+}
+
+func (m *mockDeductionRepository) GetRate(taxYear int, key string) (money.Rate, error) {
+	return 0, nil
+}
+
+func (m *mockDeductionRepository) UpdateRate(taxYear int, key string, rate money.Rate) error {
+	return nil
+}
+
+func mockUpdateDeductionHttpReq(reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/deductions/donation", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+func mockGetDeductionHttpReq(query string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/deductions/donation"+query, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+// UpdateDeduction
+func TestUpdateDeductionHandler_ShouldGetBadRequest_WhenWrongInput(t *testing.T) {
+	testCases := []struct {
+		name    string
+		reqBody string
+	}{
+		{
+			"Test case 1",
+			`{
+				"amount": asdasd
+			}`,
+		},
+		{
+			"Test case 2",
+			`{
+				"amount": -1
+			}`,
+		},
+		{
+			"Test case 3",
+			`{
+				"amount": 100001.0
+			}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			repo := &mockDeductionRepository{}
+			handler := NewDonationDeductionHttpHandler(allowance.NewAllowanceDeductionUsecase(repo))
+			_, c, _ := mockUpdateDeductionHttpReq(tc.reqBody)
+
+			// Act
+			err := handler.UpdateDeduction(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+			assert.Equal(t, "", repo.key)
+		})
+	}
+}
+
+func TestUpdateDeductionHandler_ShouldGetError_WhenErrorOnUpdateDeduction(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{"Test case 1", deduction.ErrDeductionNotFound, http.StatusNotFound},
+		{"Test case 2", errors.New("error on update"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler := NewDonationDeductionHttpHandler(allowance.NewAllowanceDeductionUsecase(&mockDeductionRepository{err: tc.err}))
+			_, c, _ := mockUpdateDeductionHttpReq(`{
+				"taxYear": 2570,
+				"amount": 50000.0
+			}`)
+
+			// Act
+			err := handler.UpdateDeduction(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCode, he.Code)
+		})
+	}
+}
+
+func TestUpdateDeductionHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	testCases := []struct {
+		name             string
+		reqBody          string
+		expectedTaxYear  int
+		expectedAmount   money.Money
+		expectedResponse string
+	}{
+		{
+			"Test case 1",
+			`{
+				"amount": 100000.0
+			}`,
+			taxYear.Default,
+			money.FromBaht(100000),
+			`{
+				"taxYear": 2567,
+				"donation": 100000
+			}`,
+		},
+		{
+			"Test case 2",
+			`{
+				"taxYear": 2568,
+				"amount": 70000.0
+			}`,
+			2568,
+			money.FromBaht(70000),
+			`{
+				"taxYear": 2568,
+				"donation": 70000
+			}`,
+		},
+		{
+			"Test case 3",
+			`{
+				"amount": 0.0
+			}`,
+			taxYear.Default,
+			money.FromBaht(0),
+			`{
+				"taxYear": 2567,
+				"donation": 0
+			}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			repo := &mockDeductionRepository{}
+			handler := NewDonationDeductionHttpHandler(allowance.NewAllowanceDeductionUsecase(repo))
+			_, c, rec := mockUpdateDeductionHttpReq(tc.reqBody)
+
+			// Act
+			err := handler.UpdateDeduction(c)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTaxYear, repo.taxYear)
+			assert.Equal(t, allowanceType.Donation, repo.key)
+			assert.Equal(t, tc.expectedAmount, repo.amount)
+			assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+			assert.JSONEq(t, tc.expectedResponse, rec.Body.String())
+		})
+	}
+}
+
+// GetDeduction
+func TestGetDeductionHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	testCases := []struct {
+		name             string
+		query            string
+		expectedTaxYear  int
+		expectedResponse string
+	}{
+		{"Test case 1", "", 2567, `{"taxYear": 2567, "donation": 80000}`},
+		{"Test case 2", "?taxYear=2568", 2568, `{"taxYear": 2568, "donation": 80000}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			repo := &mockDeductionRepository{}
+			handler := NewDonationDeductionHttpHandler(allowance.NewAllowanceDeductionUsecase(repo))
+			_, c, rec := mockGetDeductionHttpReq(tc.query)
+
+			// Act
+			err := handler.GetDeduction(c)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTaxYear, repo.taxYear)
+			assert.Equal(t, allowanceType.Donation, repo.key)
+			assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+			assert.JSONEq(t, tc.expectedResponse, rec.Body.String())
+		})
+	}
+}
+
+func TestGetDeductionHandler_ShouldGetError_WhenWrongInputOrUsecaseFail(t *testing.T) {
+	testCases := []struct {
+		name         string
+		query        string
+		err          error
+		expectedCode int
+	}{
+		{"Test case 1", "?taxYear=asdasd", nil, http.StatusBadRequest},
+		{"Test case 2", "?taxYear=2599", deduction.ErrDeductionNotFound, http.StatusNotFound},
+		{"Test case 3", "", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler := NewDonationDeductionHttpHandler(allowance.NewAllowanceDeductionUsecase(&mockDeductionRepository{err: tc.err}))
+			_, c, _ := mockGetDeductionHttpReq(tc.query)
+
+			// Act
+			err := handler.GetDeduction(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCode, he.Code)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package donation
+
+import "github.com/larb26656/assessment-tax/money"
+
+// UpdateDonationDeductionReq has no bounds on Amount, they are declared by constant/allowanceType
+type UpdateDonationDeductionReq struct {
+	TaxYear int         `json:"taxYear" validate:"omitempty,gte=2500"`
+	Amount  money.Money `json:"amount"`
+}
+
+type UpdateDonationDeductionRes struct {
+	TaxYear  int         `json:"taxYear,omitempty"`
+	Donation money.Money `json:"donation"`
+}
+
+type DonationDeductionRes struct {
+	TaxYear  int         `json:"taxYear"`
+	Donation money.Money `json:"donation"`
+}
@@ -0,0 +1,90 @@
+package insurance
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/allowance"
+)
+
+type InsuranceDeductionHttpHandler interface {
+	GetDeduction(c echo.Context) error
+	UpdateDeduction(c echo.Context) error
+}
+
+type insuranceDeductionHttpHandler struct {
+	insuranceDeductionUsecase InsuranceDeductionUsecase
+}
+
+func NewInsuranceDeductionHttpHandler(insuranceDeductionUsecase InsuranceDeductionUsecase) InsuranceDeductionHttpHandler {
+	return &insuranceDeductionHttpHandler{
+		insuranceDeductionUsecase: insuranceDeductionUsecase,
+	}
+}
+
+func (p *insuranceDeductionHttpHandler) GetDeduction(c echo.Context) error {
+	year := 0
+
+	err := echo.QueryParamsBinder(c).Int("taxYear", &year).BindError()
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	year = taxYear.Resolve(year)
+
+	insurance, err := p.insuranceDeductionUsecase.GetDeduction(year)
+
+	if errors.Is(err, deduction.ErrDeductionNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Deduction not found")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, InsuranceDeductionRes{
+		TaxYear:               year,
+		LifeInsurance:         insurance.LifeInsurance,
+		HealthInsurance:       insurance.HealthInsurance,
+		ParentHealthInsurance: insurance.ParentHealthInsurance,
+	})
+}
+
+func (p *insuranceDeductionHttpHandler) UpdateDeduction(c echo.Context) error {
+	var req UpdateInsuranceDeductionReq
+
+	err := c.Bind(&req)
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if err = c.Validate(req); err != nil {
+		return err
+	}
+
+	res, err := p.insuranceDeductionUsecase.UpdateDeduction(req)
+
+	if errors.Is(err, allowance.ErrMaxDeductionOutOfRange) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Max deduction out of range")
+	}
+
+	if errors.Is(err, deduction.ErrDeductionNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tax year not found, clone the tax year first")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
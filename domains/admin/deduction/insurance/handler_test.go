@@ -0,0 +1,225 @@
+package insurance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/allowance"
+	"github.com/larb26656/assessment-tax/money"
+	myValidator "github.com/larb26656/assessment-tax/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockInsuranceDeductionUsecase struct {
+	taxYear int
+	err     error
+}
+
+func (m *mockInsuranceDeductionUsecase) GetDeduction(taxYear int) (InsuranceDeduction, error) {
+	m.taxYear = taxYear
+
+	if m.err != nil {
+		return InsuranceDeduction{}, m.err
+	}
+
+	return InsuranceDeduction{
+		LifeInsurance:         money.FromBaht(100000),
+		HealthInsurance:       money.FromBaht(25000),
+		ParentHealthInsurance: money.FromBaht(15000),
+	}, nil
+}
+
+func (m *mockInsuranceDeductionUsecase) UpdateDeduction(req UpdateInsuranceDeductionReq) (UpdateInsuranceDeductionRes, error) {
+	if m.err != nil {
+		return UpdateInsuranceDeductionRes{}, m.err
+	}
+
+	return UpdateInsuranceDeductionRes{
+		TaxYear:               2567,
+		LifeInsurance:         req.LifeInsurance,
+		HealthInsurance:       req.HealthInsurance,
+		ParentHealthInsurance: req.ParentHealthInsurance,
+	}, nil
+}
+
+func mockUpdateDeductionHttpReq(reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/deductions/insurance", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+func mockGetDeductionHttpReq(query string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/deductions/insurance"+query, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+// UpdateDeduction
+func TestUpdateDeductionHandler_ShouldGetBadRequest_WhenWrongInput(t *testing.T) {
+	// Arrange
+	handler := NewInsuranceDeductionHttpHandler(&mockInsuranceDeductionUsecase{})
+
+	testCases := []struct {
+		name    string
+		reqBody string
+	}{
+		{"Test case 1", `{"lifeInsurance": asdasd}`},
+		{"Test case 2", `{"taxYear": 2400, "lifeInsurance": 100000}`},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, _ := mockUpdateDeductionHttpReq(tc.reqBody)
+			err := handler.UpdateDeduction(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+func TestUpdateDeductionHandler_ShouldGetBadRequest_WhenMaxDeductionOutOfRange(t *testing.T) {
+	// Arrange
+	handler := NewInsuranceDeductionHttpHandler(&mockInsuranceDeductionUsecase{err: allowance.ErrMaxDeductionOutOfRange})
+	_, c, _ := mockUpdateDeductionHttpReq(`{"lifeInsurance": 100001, "healthInsurance": 25000, "parentHealthInsurance": 15000}`)
+
+	// Act
+	err := handler.UpdateDeduction(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+}
+
+func TestUpdateDeductionHandler_ShouldGetInternalServerError_WhenErrorOnUpdateDeduction(t *testing.T) {
+	// Arrange
+	handler := NewInsuranceDeductionHttpHandler(&mockInsuranceDeductionUsecase{err: errors.New("error on update")})
+	_, c, _ := mockUpdateDeductionHttpReq(`{"lifeInsurance": 100000, "healthInsurance": 25000, "parentHealthInsurance": 15000}`)
+
+	// Act
+	err := handler.UpdateDeduction(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, he.Code)
+}
+
+func TestUpdateDeductionHandler_ShouldGetNotFound_WhenTaxYearNotFound(t *testing.T) {
+	// Arrange
+	handler := NewInsuranceDeductionHttpHandler(&mockInsuranceDeductionUsecase{err: deduction.ErrDeductionNotFound})
+	_, c, _ := mockUpdateDeductionHttpReq(`{"lifeInsurance": 100000, "healthInsurance": 25000, "parentHealthInsurance": 15000}`)
+
+	// Act
+	err := handler.UpdateDeduction(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, he.Code)
+}
+
+func TestUpdateDeductionHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	handler := NewInsuranceDeductionHttpHandler(&mockInsuranceDeductionUsecase{})
+	_, c, rec := mockUpdateDeductionHttpReq(`{"lifeInsurance": 80000, "healthInsurance": 20000, "parentHealthInsurance": 0}`)
+
+	// Act
+	err := handler.UpdateDeduction(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.JSONEq(t, `{
+		"taxYear": 2567,
+		"lifeInsurance": 80000,
+		"healthInsurance": 20000,
+		"parentHealthInsurance": 0
+	}`, rec.Body.String())
+}
+
+// GetDeduction
+func TestGetDeductionHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	testCases := []struct {
+		name             string
+		query            string
+		expectedTaxYear  int
+		expectedResponse string
+	}{
+		{"Test case 1", "", 2567, `{"taxYear": 2567, "lifeInsurance": 100000, "healthInsurance": 25000, "parentHealthInsurance": 15000}`},
+		{"Test case 2", "?taxYear=2568", 2568, `{"taxYear": 2568, "lifeInsurance": 100000, "healthInsurance": 25000, "parentHealthInsurance": 15000}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			usecase := &mockInsuranceDeductionUsecase{}
+			handler := NewInsuranceDeductionHttpHandler(usecase)
+			_, c, rec := mockGetDeductionHttpReq(tc.query)
+
+			// Act
+			err := handler.GetDeduction(c)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTaxYear, usecase.taxYear)
+			assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+			assert.JSONEq(t, tc.expectedResponse, rec.Body.String())
+		})
+	}
+}
+
+func TestGetDeductionHandler_ShouldGetError_WhenWrongInputOrUsecaseFail(t *testing.T) {
+	testCases := []struct {
+		name         string
+		query        string
+		err          error
+		expectedCode int
+	}{
+		{"Test case 1", "?taxYear=asdasd", nil, http.StatusBadRequest},
+		{"Test case 2", "?taxYear=2599", deduction.ErrDeductionNotFound, http.StatusNotFound},
+		{"Test case 3", "", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler := NewInsuranceDeductionHttpHandler(&mockInsuranceDeductionUsecase{err: tc.err})
+			_, c, _ := mockGetDeductionHttpReq(tc.query)
+
+			// Act
+			err := handler.GetDeduction(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCode, he.Code)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package insurance
+
+import "github.com/larb26656/assessment-tax/money"
+
+// UpdateInsuranceDeductionReq has no bounds on the caps, they are declared by constant/allowanceType
+type UpdateInsuranceDeductionReq struct {
+	TaxYear               int         `json:"taxYear" validate:"omitempty,gte=2500"`
+	LifeInsurance         money.Money `json:"lifeInsurance"`
+	HealthInsurance       money.Money `json:"healthInsurance"`
+	ParentHealthInsurance money.Money `json:"parentHealthInsurance"`
+}
+
+type UpdateInsuranceDeductionRes struct {
+	TaxYear               int         `json:"taxYear,omitempty"`
+	LifeInsurance         money.Money `json:"lifeInsurance"`
+	HealthInsurance       money.Money `json:"healthInsurance"`
+	ParentHealthInsurance money.Money `json:"parentHealthInsurance"`
+}
+
+type InsuranceDeduction struct {
+	LifeInsurance         money.Money
+	HealthInsurance       money.Money
+	ParentHealthInsurance money.Money
+}
+
+type InsuranceDeductionRes struct {
+	TaxYear               int         `json:"taxYear"`
+	LifeInsurance         money.Money `json:"lifeInsurance"`
+	HealthInsurance       money.Money `json:"healthInsurance"`
+	ParentHealthInsurance money.Money `json:"parentHealthInsurance"`
+}
@@ -0,0 +1,72 @@
+package insurance
+
+import (
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/allowance"
+)
+
+type InsuranceDeductionUsecase interface {
+	GetDeduction(taxYear int) (InsuranceDeduction, error)
+	UpdateDeduction(req UpdateInsuranceDeductionReq) (UpdateInsuranceDeductionRes, error)
+}
+
+type insuranceDeductionUsecase struct {
+	allowanceDeductionUsecase allowance.AllowanceDeductionUsecase
+}
+
+// NewInsuranceDeductionUsecase reads and sets the insurance caps through the allowance types, so their
+// bounds are only declared by constant/allowanceType
+func NewInsuranceDeductionUsecase(allowanceDeductionUsecase allowance.AllowanceDeductionUsecase) InsuranceDeductionUsecase {
+	return &insuranceDeductionUsecase{
+		allowanceDeductionUsecase: allowanceDeductionUsecase,
+	}
+}
+
+func (p *insuranceDeductionUsecase) GetDeduction(taxYear int) (InsuranceDeduction, error) {
+	lifeInsurance, err := p.allowanceDeductionUsecase.GetMaxDeduction(taxYear, allowanceType.LifeInsurance)
+
+	if err != nil {
+		return InsuranceDeduction{}, err
+	}
+
+	healthInsurance, err := p.allowanceDeductionUsecase.GetMaxDeduction(taxYear, allowanceType.HealthInsurance)
+
+	if err != nil {
+		return InsuranceDeduction{}, err
+	}
+
+	parentHealthInsurance, err := p.allowanceDeductionUsecase.GetMaxDeduction(taxYear, allowanceType.ParentHealthInsurance)
+
+	if err != nil {
+		return InsuranceDeduction{}, err
+	}
+
+	return InsuranceDeduction{
+		LifeInsurance:         lifeInsurance,
+		HealthInsurance:       healthInsurance,
+		ParentHealthInsurance: parentHealthInsurance,
+	}, nil
+}
+
+func (p *insuranceDeductionUsecase) UpdateDeduction(req UpdateInsuranceDeductionReq) (UpdateInsuranceDeductionRes, error) {
+	year := taxYear.Resolve(req.TaxYear)
+
+	err := p.allowanceDeductionUsecase.UpdateMaxDeductions(year, []deduction.Deduction{
+		{Key: allowanceType.LifeInsurance, Value: req.LifeInsurance},
+		{Key: allowanceType.HealthInsurance, Value: req.HealthInsurance},
+		{Key: allowanceType.ParentHealthInsurance, Value: req.ParentHealthInsurance},
+	})
+
+	if err != nil {
+		return UpdateInsuranceDeductionRes{}, err
+	}
+
+	return UpdateInsuranceDeductionRes{
+		TaxYear:               year,
+		LifeInsurance:         req.LifeInsurance,
+		HealthInsurance:       req.HealthInsurance,
+		ParentHealthInsurance: req.ParentHealthInsurance,
+	}, nil
+}
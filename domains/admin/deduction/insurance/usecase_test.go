@@ -0,0 +1,171 @@
+package insurance
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/allowance"
+	"github.com/larb26656/assessment-tax/money"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDeductionRepository struct {
+	taxYear    int
+	deductions map[string]money.Money
+	err        error
+}
+
+func (p *mockDeductionRepository) GetDeduction(taxYear int, key string) (money.Money, error) {
+	p.taxYear = taxYear
+
+	if p.err != nil {
+		return 0, p.err
+	}
+
+	return p.deductions[key], nil
+}
+
+func (p *mockDeductionRepository) UpdateDeduction(taxYear int, key string, deduction money.Money) error {
+	p.taxYear = taxYear
+
+	if p.err != nil {
+		return p.err
+	}
+
+	p.deductions[key] = deduction
+	return nil
+}
+
+func (p *mockDeductionRepository) UpdateDeductions(taxYear int, deductions []deduction.Deduction) error {
+	p.taxYear = taxYear
+
+	if p.err != nil {
+		return p.err
+	}
+
+	for _, deduction := range deductions {
+		p.deductions[deduction.Key] = deduction.Value
+	}
+
+	return nil
+}
+
+func (p *mockDeductionRepository) GetRate(taxYear int, key string) (money.Rate, error) {
+	return 0, nil
+}
+
+func (p *mockDeductionRepository) UpdateRate(taxYear int, key string, rate money.Rate) error {
+	return nil
+}
+
+// GetDeduction
+func TestGetDeduction_ShouldReturnErr_WhenDeductionNotFound(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepository{err: deduction.ErrDeductionNotFound}
+	usecase := NewInsuranceDeductionUsecase(allowance.NewAllowanceDeductionUsecase(repo))
+
+	// Act
+	_, err := usecase.GetDeduction(2568)
+
+	// Assert
+	assert.ErrorIs(t, err, deduction.ErrDeductionNotFound)
+	assert.Equal(t, 2568, repo.taxYear)
+}
+
+func TestGetDeduction_ShouldReturnDeduction_WhenDeductionFound(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepository{deductions: map[string]money.Money{
+		allowanceType.LifeInsurance:         money.FromBaht(100000),
+		allowanceType.HealthInsurance:       money.FromBaht(25000),
+		allowanceType.ParentHealthInsurance: money.FromBaht(15000),
+	}}
+	usecase := NewInsuranceDeductionUsecase(allowance.NewAllowanceDeductionUsecase(repo))
+
+	// Act
+	result, err := usecase.GetDeduction(2568)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2568, repo.taxYear)
+	assert.Equal(t, InsuranceDeduction{
+		LifeInsurance:         money.FromBaht(100000),
+		HealthInsurance:       money.FromBaht(25000),
+		ParentHealthInsurance: money.FromBaht(15000),
+	}, result)
+}
+
+// UpdateDeduction
+func TestUpdateDeduction_ShouldReturnError_WhenUpdateDeductionFail(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepository{err: errors.New("Update deduction error")}
+	usecase := NewInsuranceDeductionUsecase(allowance.NewAllowanceDeductionUsecase(repo))
+
+	// Act
+	_, err := usecase.UpdateDeduction(UpdateInsuranceDeductionReq{
+		LifeInsurance: money.FromBaht(100000),
+	})
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, taxYear.Default, repo.taxYear)
+}
+
+func TestUpdateDeduction_ShouldSaveNothing_WhenOneCapOutOfRange(t *testing.T) {
+	testCases := []struct {
+		name string
+		req  UpdateInsuranceDeductionReq
+	}{
+		{"Test case 1", UpdateInsuranceDeductionReq{LifeInsurance: money.FromBaht(-1), HealthInsurance: money.FromBaht(20000)}},
+		{"Test case 2", UpdateInsuranceDeductionReq{LifeInsurance: money.FromBaht(100001)}},
+		{"Test case 3", UpdateInsuranceDeductionReq{LifeInsurance: money.FromBaht(80000), HealthInsurance: money.FromBaht(25001)}},
+		{"Test case 4", UpdateInsuranceDeductionReq{ParentHealthInsurance: money.FromBaht(15001)}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			repo := &mockDeductionRepository{deductions: map[string]money.Money{}}
+			usecase := NewInsuranceDeductionUsecase(allowance.NewAllowanceDeductionUsecase(repo))
+
+			// Act
+			_, err := usecase.UpdateDeduction(tc.req)
+
+			// Assert
+			assert.ErrorIs(t, err, allowance.ErrMaxDeductionOutOfRange)
+			assert.Empty(t, repo.deductions)
+		})
+	}
+}
+
+func TestUpdateDeduction_ShouldSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	repo := &mockDeductionRepository{deductions: map[string]money.Money{}}
+	usecase := NewInsuranceDeductionUsecase(allowance.NewAllowanceDeductionUsecase(repo))
+	req := UpdateInsuranceDeductionReq{
+		TaxYear:               2568,
+		LifeInsurance:         money.FromBaht(80000),
+		HealthInsurance:       money.FromBaht(20000),
+		ParentHealthInsurance: money.FromBaht(10000),
+	}
+
+	// Act
+	result, err := usecase.UpdateDeduction(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2568, repo.taxYear)
+	assert.Equal(t, map[string]money.Money{
+		allowanceType.LifeInsurance:         money.FromBaht(80000),
+		allowanceType.HealthInsurance:       money.FromBaht(20000),
+		allowanceType.ParentHealthInsurance: money.FromBaht(10000),
+	}, repo.deductions)
+	assert.Equal(t, UpdateInsuranceDeductionRes{
+		TaxYear:               2568,
+		LifeInsurance:         money.FromBaht(80000),
+		HealthInsurance:       money.FromBaht(20000),
+		ParentHealthInsurance: money.FromBaht(10000),
+	}, result)
+}
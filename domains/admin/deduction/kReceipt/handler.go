@@ -1,10 +1,14 @@
 package kReceipt
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/allowance"
 )
 
 type KReceiptDeductionHttpHandler interface {
@@ -12,12 +16,14 @@ type KReceiptDeductionHttpHandler interface {
 }
 
 type kReceiptDeductionHttpHandler struct {
-	kReceiptDeductionUsecase KReceiptDeductionUsecase
+	allowanceDeductionUsecase allowance.AllowanceDeductionUsecase
 }
 
-func NewKReceiptDeductionHttpHandler(kReceiptDeductionUsecase KReceiptDeductionUsecase) KReceiptDeductionHttpHandler {
+// NewKReceiptDeductionHttpHandler keeps the k-receipt endpoint, the cap and its bounds are handled by the
+// allowance type registry like any other allowance type
+func NewKReceiptDeductionHttpHandler(allowanceDeductionUsecase allowance.AllowanceDeductionUsecase) KReceiptDeductionHttpHandler {
 	return &kReceiptDeductionHttpHandler{
-		kReceiptDeductionUsecase: kReceiptDeductionUsecase,
+		allowanceDeductionUsecase: allowanceDeductionUsecase,
 	}
 }
 
@@ -35,12 +41,26 @@ func (p *kReceiptDeductionHttpHandler) UpdateDeduction(c echo.Context) error {
 		return err
 	}
 
-	res, err := p.kReceiptDeductionUsecase.UpdateDeduction(req)
+	res, err := p.allowanceDeductionUsecase.UpdateMaxDeduction(allowanceType.KReceipt, allowance.UpdateAllowanceDeductionReq{
+		TaxYear: req.TaxYear,
+		Amount:  req.Amount,
+	})
+
+	if errors.Is(err, allowance.ErrMaxDeductionOutOfRange) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Max deduction out of range")
+	}
+
+	if errors.Is(err, deduction.ErrDeductionNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tax year not found, clone the tax year first")
+	}
 
 	if err != nil {
 		fmt.Println(err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
 	}
 
-	return c.JSON(http.StatusOK, res)
+	return c.JSON(http.StatusOK, UpdateKReceiptDeductionRes{
+		TaxYear:  res.TaxYear,
+		KReceipt: res.MaxDeduction,
+	})
 }
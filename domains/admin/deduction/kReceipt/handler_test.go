@@ -9,21 +9,47 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/allowance"
+	"github.com/larb26656/assessment-tax/money"
 	myValidator "github.com/larb26656/assessment-tax/validator"
 	"github.com/stretchr/testify/assert"
 )
 
-type mockKReceiptDeductionUsecaseCaseSuccess struct {
+type mockDeductionRepository struct {
+	taxYear int
+	key     string
+	amount  money.Money
+	err     error
 }
 
-func (m *mockKReceiptDeductionUsecaseCaseSuccess) GetDeduction() (float64, error) {
-	return 50000.0, nil
+func (m *mockDeductionRepository) GetDeduction(taxYear int, key string) (money.Money, error) {
+	return money.FromBaht(50000), m.err
 }
 
-func (m *mockKReceiptDeductionUsecaseCaseSuccess) UpdateDeduction(req UpdateKReceiptDeductionReq) (UpdateKReceiptDeductionRes, error) {
-	return UpdateKReceiptDeductionRes{
-		KReceipt: 50000.0,
-	}, nil
+func (m *mockDeductionRepository) UpdateDeduction(taxYear int, key string, deduction money.Money) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.taxYear = taxYear
+	m.key = key
+	m.amount = deduction
+	return nil
+}
+
+func (m *mockDeductionRepository) UpdateDeductions(taxYear int, deductions []deduction.Deduction) error {
+	return m.err
+}
+
+func (m *mockDeductionRepository) GetRate(taxYear int, key string) (money.Rate, error) {
+	return 0, nil
+}
+
+func (m *mockDeductionRepository) UpdateRate(taxYear int, key string, rate money.Rate) error {
+	return nil
 }
 
 func mockUpdateDeductionHttpReq(reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
@@ -40,12 +66,6 @@ func mockUpdateDeductionHttpReq(reqBody string) (*echo.Echo, echo.Context, *http
 }
 
 func TestUpdateDeductionHandler_ShouldGetBadRequest_WhenWrongInput(t *testing.T) {
-	// Arrange
-	usecase := &mockKReceiptDeductionUsecaseCaseSuccess{}
-	handler := NewKReceiptDeductionHttpHandler(
-		usecase,
-	)
-
 	testCases := []struct {
 		name    string
 		reqBody string
@@ -68,12 +88,23 @@ func TestUpdateDeductionHandler_ShouldGetBadRequest_WhenWrongInput(t *testing.T)
 				"amount": 100001.0
 			}`,
 		},
+		{
+			"Test case 4",
+			`{
+				"taxYear": 2400,
+				"amount": 50000.0
+			}`,
+		},
 	}
 
-	// Act
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			repo := &mockDeductionRepository{}
+			handler := NewKReceiptDeductionHttpHandler(allowance.NewAllowanceDeductionUsecase(repo))
 			_, c, _ := mockUpdateDeductionHttpReq(tc.reqBody)
+
+			// Act
 			err := handler.UpdateDeduction(c)
 
 			// Assert
@@ -81,54 +112,48 @@ func TestUpdateDeductionHandler_ShouldGetBadRequest_WhenWrongInput(t *testing.T)
 			he, ok := err.(*echo.HTTPError)
 			assert.True(t, ok)
 			assert.Equal(t, http.StatusBadRequest, he.Code)
+			assert.Equal(t, "", repo.key)
 		})
 	}
 }
 
-type mockKReceiptDeductionUsecaseCaseErrorOnUpdateDeduction struct {
-}
-
-func (m *mockKReceiptDeductionUsecaseCaseErrorOnUpdateDeduction) GetDeduction() (float64, error) {
-	return 50000.0, nil
-}
-
-func (m *mockKReceiptDeductionUsecaseCaseErrorOnUpdateDeduction) UpdateDeduction(req UpdateKReceiptDeductionReq) (UpdateKReceiptDeductionRes, error) {
-	return UpdateKReceiptDeductionRes{}, errors.New("error on update")
-}
-
-func TestUpdateDeductionHandler_ShouldGetInternalServerError_WhenErrorOnUpdateDeduction(t *testing.T) {
-	// Arrange
-	usecase := &mockKReceiptDeductionUsecaseCaseErrorOnUpdateDeduction{}
-	handler := NewKReceiptDeductionHttpHandler(
-		usecase,
-	)
-
-	reqBody := `{
-		"amount": 50000.0
-	}`
+func TestUpdateDeductionHandler_ShouldGetError_WhenErrorOnUpdateDeduction(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{"Test case 1", deduction.ErrDeductionNotFound, http.StatusNotFound},
+		{"Test case 2", errors.New("error on update"), http.StatusInternalServerError},
+	}
 
-	_, c, _ := mockUpdateDeductionHttpReq(reqBody)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler := NewKReceiptDeductionHttpHandler(allowance.NewAllowanceDeductionUsecase(&mockDeductionRepository{err: tc.err}))
+			_, c, _ := mockUpdateDeductionHttpReq(`{
+				"taxYear": 2570,
+				"amount": 50000.0
+			}`)
 
-	// Act
-	err := handler.UpdateDeduction(c)
+			// Act
+			err := handler.UpdateDeduction(c)
 
-	// Assert
-	assert.Error(t, err)
-	he, ok := err.(*echo.HTTPError)
-	assert.True(t, ok)
-	assert.Equal(t, http.StatusInternalServerError, he.Code)
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCode, he.Code)
+		})
+	}
 }
 
 func TestUpdateDeductionHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
-	// Arrange
-	usecase := &mockKReceiptDeductionUsecaseCaseSuccess{}
-	handler := NewKReceiptDeductionHttpHandler(
-		usecase,
-	)
-
 	testCases := []struct {
 		name             string
 		reqBody          string
+		expectedTaxYear  int
+		expectedAmount   money.Money
 		expectedResponse string
 	}{
 		{
@@ -136,56 +161,55 @@ func TestUpdateDeductionHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T)
 			`{
 				"amount": 50000.0
 			}`,
+			taxYear.Default,
+			money.FromBaht(50000),
 			`{
+				"taxYear": 2567,
 				"kReceipt": 50000
 			}`,
 		},
 		{
 			"Test case 2",
 			`{
-				"amount": 70000.0
-			}`,
-			`{
-				"kReceipt": 50000
-			}`,
-		},
-		{
-			"Test case 3",
-			`{
-				"amount": 10000.0
-			}`,
-			`{
-				"kReceipt": 50000
-			}`,
-		},
-		{
-			"Test case 4",
-			`{
+				"taxYear": 2568,
 				"amount": 100000.0
 			}`,
+			2568,
+			money.FromBaht(100000),
 			`{
-				"kReceipt": 50000
+				"taxYear": 2568,
+				"kReceipt": 100000
 			}`,
 		},
 		{
-			"Test case 5",
+			"Test case 3",
 			`{
 				"amount": 0.0
 			}`,
+			taxYear.Default,
+			money.FromBaht(0),
 			`{
-				"kReceipt": 50000
+				"taxYear": 2567,
+				"kReceipt": 0
 			}`,
 		},
 	}
 
-	// Act
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			repo := &mockDeductionRepository{}
+			handler := NewKReceiptDeductionHttpHandler(allowance.NewAllowanceDeductionUsecase(repo))
 			_, c, rec := mockUpdateDeductionHttpReq(tc.reqBody)
+
+			// Act
 			err := handler.UpdateDeduction(c)
 
 			// Assert
 			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTaxYear, repo.taxYear)
+			assert.Equal(t, allowanceType.KReceipt, repo.key)
+			assert.Equal(t, tc.expectedAmount, repo.amount)
 			assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
 			assert.JSONEq(t, tc.expectedResponse, rec.Body.String())
 		})
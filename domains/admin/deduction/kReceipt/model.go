@@ -1,9 +1,14 @@
 package kReceipt
 
+import "github.com/larb26656/assessment-tax/money"
+
+// UpdateKReceiptDeductionReq has no bounds on Amount, they are declared by constant/allowanceType
 type UpdateKReceiptDeductionReq struct {
-	Amount float64 `json:"amount" validate:"gte=0,lte=100000"`
+	TaxYear int         `json:"taxYear" validate:"omitempty,gte=2500"`
+	Amount  money.Money `json:"amount"`
 }
 
 type UpdateKReceiptDeductionRes struct {
-	KReceipt float64 `json:"kReceipt"`
+	TaxYear  int         `json:"taxYear,omitempty"`
+	KReceipt money.Money `json:"kReceipt"`
 }
@@ -1,10 +1,12 @@
 package personal
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
 )
 
 type PersonalDeductionHttpHandler interface {
@@ -37,6 +39,10 @@ func (p *personalDeductionHttpHandler) UpdateDeduction(c echo.Context) error {
 
 	res, err := p.personalDeductionUsecase.UpdateDeduction(req)
 
+	if errors.Is(err, deduction.ErrDeductionNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tax year not found, clone the tax year first")
+	}
+
 	if err != nil {
 		fmt.Println(err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
@@ -9,6 +9,8 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/money"
 	myValidator "github.com/larb26656/assessment-tax/validator"
 	"github.com/stretchr/testify/assert"
 )
@@ -16,13 +18,13 @@ import (
 type mockPersonalDeductionUsecaseCaseSuccess struct {
 }
 
-func (m *mockPersonalDeductionUsecaseCaseSuccess) GetDeduction() (float64, error) {
-	return 60000.0, nil
+func (m *mockPersonalDeductionUsecaseCaseSuccess) GetDeduction(taxYear int) (money.Money, error) {
+	return money.FromBaht(60000), nil
 }
 
 func (m *mockPersonalDeductionUsecaseCaseSuccess) UpdateDeduction(req UpdatePersonalDeductionReq) (UpdatePersonalDeductionRes, error) {
 	return UpdatePersonalDeductionRes{
-		PersonalDeduction: 60000.0,
+		PersonalDeduction: money.FromBaht(60000),
 	}, nil
 }
 
@@ -102,8 +104,8 @@ func TestUpdateDeductionHandler_ShouldGetBadRequest_WhenWrongInput(t *testing.T)
 type mockPersonalDeductionUsecaseCaseErrorOnUpdateDeduction struct {
 }
 
-func (m *mockPersonalDeductionUsecaseCaseErrorOnUpdateDeduction) GetDeduction() (float64, error) {
-	return 60000.0, nil
+func (m *mockPersonalDeductionUsecaseCaseErrorOnUpdateDeduction) GetDeduction(taxYear int) (money.Money, error) {
+	return money.FromBaht(60000), nil
 }
 
 func (m *mockPersonalDeductionUsecaseCaseErrorOnUpdateDeduction) UpdateDeduction(req UpdatePersonalDeductionReq) (UpdatePersonalDeductionRes, error) {
@@ -133,6 +135,41 @@ func TestUpdateDeductionHandler_ShouldGetInternalServerError_WhenErrorOnUpdateDe
 	assert.Equal(t, http.StatusInternalServerError, he.Code)
 }
 
+type mockPersonalDeductionUsecaseCaseTaxYearNotFound struct {
+}
+
+func (m *mockPersonalDeductionUsecaseCaseTaxYearNotFound) GetDeduction(taxYear int) (money.Money, error) {
+	return 0, deduction.ErrDeductionNotFound
+}
+
+func (m *mockPersonalDeductionUsecaseCaseTaxYearNotFound) UpdateDeduction(req UpdatePersonalDeductionReq) (UpdatePersonalDeductionRes, error) {
+	return UpdatePersonalDeductionRes{}, deduction.ErrDeductionNotFound
+}
+
+func TestUpdateDeductionHandler_ShouldGetNotFound_WhenTaxYearNotFound(t *testing.T) {
+	// Arrange
+	usecase := &mockPersonalDeductionUsecaseCaseTaxYearNotFound{}
+	handler := NewPersonalDeductionHttpHandler(
+		usecase,
+	)
+
+	reqBody := `{
+		"taxYear": 2570,
+		"amount": 60000.0
+	}`
+
+	_, c, _ := mockUpdateDeductionHttpReq(reqBody)
+
+	// Act
+	err := handler.UpdateDeduction(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, he.Code)
+}
+
 func TestUpdateDeductionHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
 	// Arrange
 	usecase := &mockPersonalDeductionUsecaseCaseSuccess{}
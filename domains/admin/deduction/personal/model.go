@@ -1,9 +1,13 @@
 package personal
 
+import "github.com/larb26656/assessment-tax/money"
+
 type UpdatePersonalDeductionReq struct {
-	Amount float64 `json:"amount" validate:"gte=10000,lte=100000"`
+	TaxYear int         `json:"taxYear" validate:"omitempty,gte=2500"`
+	Amount  money.Money `json:"amount" validate:"gte=10000,lte=100000"`
 }
 
 type UpdatePersonalDeductionRes struct {
-	PersonalDeduction float64 `json:"personalDeduction"`
+	TaxYear           int         `json:"taxYear,omitempty"`
+	PersonalDeduction money.Money `json:"personalDeduction"`
 }
@@ -2,11 +2,13 @@ package personal
 
 import (
 	"github.com/larb26656/assessment-tax/constant/deductionType"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
 	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/money"
 )
 
 type PersonalDeductionUsecase interface {
-	GetDeduction() (float64, error)
+	GetDeduction(taxYear int) (money.Money, error)
 	UpdateDeduction(req UpdatePersonalDeductionReq) (UpdatePersonalDeductionRes, error)
 }
 
@@ -20,24 +22,27 @@ func NewPersonalDeductionUsecase(deductionRepository deduction.DeductionReposito
 	}
 }
 
-func (p *personalDeductionUsecase) GetDeduction() (float64, error) {
-	deduction, err := p.deductionRepository.GetDeduction(deductionType.Personal)
+func (p *personalDeductionUsecase) GetDeduction(taxYear int) (money.Money, error) {
+	deduction, err := p.deductionRepository.GetDeduction(taxYear, deductionType.Personal)
 
 	if err != nil {
-		return 0.0, err
+		return 0, err
 	}
 
 	return deduction, nil
 }
 
 func (p *personalDeductionUsecase) UpdateDeduction(req UpdatePersonalDeductionReq) (UpdatePersonalDeductionRes, error) {
-	err := p.deductionRepository.UpdateDeduction(deductionType.Personal, req.Amount)
+	year := taxYear.Resolve(req.TaxYear)
+
+	err := p.deductionRepository.UpdateDeduction(year, deductionType.Personal, req.Amount)
 
 	if err != nil {
 		return UpdatePersonalDeductionRes{}, err
 	}
 
 	return UpdatePersonalDeductionRes{
+		TaxYear:           year,
 		PersonalDeduction: req.Amount,
 	}, nil
 }
@@ -5,19 +5,36 @@ import (
 	"testing"
 
 	"github.com/larb26656/assessment-tax/constant/deductionType"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/money"
 	"github.com/stretchr/testify/assert"
 )
 
 type mockDeductionRepositoryCaseDeductionNotFound struct {
-	key string
+	taxYear int
+	key     string
 }
 
-func (p *mockDeductionRepositoryCaseDeductionNotFound) GetDeduction(key string) (float64, error) {
+func (p *mockDeductionRepositoryCaseDeductionNotFound) GetDeduction(taxYear int, key string) (money.Money, error) {
+	p.taxYear = taxYear
 	p.key = key
-	return 0.0, errors.New("deduction not found")
+	return 0, errors.New("deduction not found")
 }
 
-func (p *mockDeductionRepositoryCaseDeductionNotFound) UpdateDeduction(key string, deductions float64) error {
+func (p *mockDeductionRepositoryCaseDeductionNotFound) UpdateDeduction(taxYear int, key string, deductions money.Money) error {
+	return nil
+}
+
+func (p *mockDeductionRepositoryCaseDeductionNotFound) UpdateDeductions(taxYear int, deductions []deduction.Deduction) error {
+	return nil
+}
+
+func (p *mockDeductionRepositoryCaseDeductionNotFound) GetRate(taxYear int, key string) (money.Rate, error) {
+	return 0, nil
+}
+
+func (p *mockDeductionRepositoryCaseDeductionNotFound) UpdateRate(taxYear int, key string, rate money.Rate) error {
 	return nil
 }
 
@@ -27,23 +44,38 @@ func TestGetDeduction_ShouldReturnErr_WhenDeductionNotFound(t *testing.T) {
 	usecase := NewPersonalDeductionUsecase(repo)
 
 	// Act
-	_, err := usecase.GetDeduction()
+	_, err := usecase.GetDeduction(2568)
 
 	// Assert
 	assert.Error(t, err)
 	assert.Equal(t, deductionType.Personal, repo.key)
+	assert.Equal(t, 2568, repo.taxYear)
 }
 
 type mockDeductionRepositoryCaseDeductionFound struct {
-	key string
+	taxYear int
+	key     string
 }
 
-func (p *mockDeductionRepositoryCaseDeductionFound) GetDeduction(key string) (float64, error) {
+func (p *mockDeductionRepositoryCaseDeductionFound) GetDeduction(taxYear int, key string) (money.Money, error) {
+	p.taxYear = taxYear
 	p.key = key
-	return 60000.0, nil
+	return money.FromBaht(60000), nil
+}
+
+func (p *mockDeductionRepositoryCaseDeductionFound) UpdateDeduction(taxYear int, key string, deductions money.Money) error {
+	return nil
+}
+
+func (p *mockDeductionRepositoryCaseDeductionFound) UpdateDeductions(taxYear int, deductions []deduction.Deduction) error {
+	return nil
+}
+
+func (p *mockDeductionRepositoryCaseDeductionFound) GetRate(taxYear int, key string) (money.Rate, error) {
+	return 0, nil
 }
 
-func (p *mockDeductionRepositoryCaseDeductionFound) UpdateDeduction(key string, deductions float64) error {
+func (p *mockDeductionRepositoryCaseDeductionFound) UpdateRate(taxYear int, key string, rate money.Rate) error {
 	return nil
 }
 
@@ -53,36 +85,51 @@ func TestGetDeduction_ShouldReturnDeduction_WhenDeductionFound(t *testing.T) {
 	usecase := NewPersonalDeductionUsecase(repo)
 
 	// Act
-	deduction, err := usecase.GetDeduction()
+	deduction, err := usecase.GetDeduction(2568)
 
 	// Assert
 	assert.NoError(t, err)
-	assert.Equal(t, 60000.0, deduction)
+	assert.Equal(t, money.FromBaht(60000), deduction)
 	assert.Equal(t, deductionType.Personal, repo.key)
+	assert.Equal(t, 2568, repo.taxYear)
 }
 
 type mockDeductionRepositoryCaseUpdateDeductionError struct {
-	key    string
-	amount float64
+	taxYear int
+	key     string
+	amount  money.Money
 }
 
-func (p *mockDeductionRepositoryCaseUpdateDeductionError) GetDeduction(key string) (float64, error) {
-	return 60000.0, nil
+func (p *mockDeductionRepositoryCaseUpdateDeductionError) GetDeduction(taxYear int, key string) (money.Money, error) {
+	return money.FromBaht(60000), nil
 }
 
-func (p *mockDeductionRepositoryCaseUpdateDeductionError) UpdateDeduction(key string, deduction float64) error {
+func (p *mockDeductionRepositoryCaseUpdateDeductionError) UpdateDeduction(taxYear int, key string, deduction money.Money) error {
+	p.taxYear = taxYear
 	p.key = key
 	p.amount = deduction
 	return errors.New("Update deduction error")
 }
 
+func (p *mockDeductionRepositoryCaseUpdateDeductionError) UpdateDeductions(taxYear int, deductions []deduction.Deduction) error {
+	return errors.New("Update deduction error")
+}
+
+func (p *mockDeductionRepositoryCaseUpdateDeductionError) GetRate(taxYear int, key string) (money.Rate, error) {
+	return 0, nil
+}
+
+func (p *mockDeductionRepositoryCaseUpdateDeductionError) UpdateRate(taxYear int, key string, rate money.Rate) error {
+	return nil
+}
+
 // UpdateDeduction
 func TestUpdateDeduction_ShouldReturnError_WhenUpdateDeductionFail(t *testing.T) {
 	// Arrange
 	repo := &mockDeductionRepositoryCaseUpdateDeductionError{}
 	usecase := NewPersonalDeductionUsecase(repo)
 	req := UpdatePersonalDeductionReq{
-		Amount: 70000.0,
+		Amount: money.FromBaht(70000),
 	}
 
 	// Act
@@ -91,30 +138,46 @@ func TestUpdateDeduction_ShouldReturnError_WhenUpdateDeductionFail(t *testing.T)
 	// Assert
 	assert.Error(t, err)
 	assert.Equal(t, deductionType.Personal, repo.key)
+	assert.Equal(t, taxYear.Default, repo.taxYear)
 	assert.Equal(t, req.Amount, repo.amount)
 }
 
 type mockDeductionRepositoryCaseUpdateSuccess struct {
-	key    string
-	amount float64
+	taxYear int
+	key     string
+	amount  money.Money
 }
 
-func (p *mockDeductionRepositoryCaseUpdateSuccess) GetDeduction(key string) (float64, error) {
-	return 60000.0, nil
+func (p *mockDeductionRepositoryCaseUpdateSuccess) GetDeduction(taxYear int, key string) (money.Money, error) {
+	return money.FromBaht(60000), nil
 }
 
-func (p *mockDeductionRepositoryCaseUpdateSuccess) UpdateDeduction(key string, deduction float64) error {
+func (p *mockDeductionRepositoryCaseUpdateSuccess) UpdateDeduction(taxYear int, key string, deduction money.Money) error {
+	p.taxYear = taxYear
 	p.key = key
 	p.amount = deduction
 	return nil
 }
 
+func (p *mockDeductionRepositoryCaseUpdateSuccess) UpdateDeductions(taxYear int, deductions []deduction.Deduction) error {
+	return nil
+}
+
+func (p *mockDeductionRepositoryCaseUpdateSuccess) GetRate(taxYear int, key string) (money.Rate, error) {
+	return 0, nil
+}
+
+func (p *mockDeductionRepositoryCaseUpdateSuccess) UpdateRate(taxYear int, key string, rate money.Rate) error {
+	return nil
+}
+
 func TestUpdateDeduction_ShouldSuccess_WhenCorrectInput(t *testing.T) {
 	// Arrange
 	repo := &mockDeductionRepositoryCaseUpdateSuccess{}
 	usecase := NewPersonalDeductionUsecase(repo)
 	req := UpdatePersonalDeductionReq{
-		Amount: 70000.0,
+		TaxYear: 2568,
+		Amount:  money.FromBaht(70000),
 	}
 
 	// Act
@@ -123,6 +186,8 @@ func TestUpdateDeduction_ShouldSuccess_WhenCorrectInput(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, deductionType.Personal, repo.key)
+	assert.Equal(t, 2568, repo.taxYear)
+	assert.Equal(t, 2568, result.TaxYear)
 	assert.Equal(t, req.Amount, repo.amount)
 	assert.Equal(t, req.Amount, result.PersonalDeduction)
 }
@@ -2,11 +2,26 @@ package deduction
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/larb26656/assessment-tax/money"
 )
 
+var ErrDeductionNotFound = errors.New("deduction not found")
+
+// Deduction is the value of a setting under Key
+type Deduction struct {
+	Key   string
+	Value money.Money
+}
+
 type DeductionRepository interface {
-	GetDeduction(key string) (float64, error)
-	UpdateDeduction(key string, deduction float64) error
+	GetDeduction(taxYear int, key string) (money.Money, error)
+	GetRate(taxYear int, key string) (money.Rate, error)
+	UpdateDeduction(taxYear int, key string, deduction money.Money) error
+	UpdateRate(taxYear int, key string, rate money.Rate) error
+	UpdateDeductions(taxYear int, deductions []Deduction) error
 }
 
 type deductionRepository struct {
@@ -19,37 +34,123 @@ func NewDeductionsRepository(db *sql.DB) DeductionRepository {
 	}
 }
 
-func (p *deductionRepository) GetDeduction(key string) (float64, error) {
-	stmt, err := p.db.Prepare(`SELECT value FROM tax_deduction_setting WHERE "key" = $1`)
+func (p *deductionRepository) GetDeduction(taxYear int, key string) (money.Money, error) {
+	var deduction money.Money
 
-	if err != nil {
-		return 0.0, err
+	if err := p.getValue(taxYear, key, &deduction); err != nil {
+		return 0, err
 	}
 
-	row := stmt.QueryRow(key)
-	deductions := 0.0
+	return deduction, nil
+}
 
-	err = row.Scan(&deductions)
+// GetRate returns a setting that is a percentage e.g. the cap rate of an allowance type
+func (p *deductionRepository) GetRate(taxYear int, key string) (money.Rate, error) {
+	var rate money.Rate
+
+	if err := p.getValue(taxYear, key, &rate); err != nil {
+		return 0, err
+	}
+
+	return rate, nil
+}
+
+func (p *deductionRepository) getValue(taxYear int, key string, value sql.Scanner) error {
+	stmt, err := p.db.Prepare(`SELECT value FROM tax_deduction_setting WHERE tax_year = $1 AND "key" = $2`)
 
 	if err != nil {
-		return 0.0, err
+		return err
 	}
 
-	return deductions, nil
+	defer stmt.Close()
+
+	row := stmt.QueryRow(taxYear, key)
+
+	err = row.Scan(value)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrDeductionNotFound
+	}
+
+	return err
+}
+
+// UpdateDeduction changes a setting of an existing tax year, it returns ErrDeductionNotFound when the
+// year has no such setting so a new year is only made by cloning
+func (p *deductionRepository) UpdateDeduction(taxYear int, key string, deduction money.Money) error {
+	return p.updateValue(taxYear, key, deduction)
 }
 
-func (p *deductionRepository) UpdateDeduction(key string, deduction float64) error {
-	stmt, err := p.db.Prepare(`UPDATE tax_deduction_setting SET value=$2 WHERE "key" = $1`)
+// UpdateRate changes a percentage setting of an existing tax year like UpdateDeduction
+func (p *deductionRepository) UpdateRate(taxYear int, key string, rate money.Rate) error {
+	return p.updateValue(taxYear, key, rate)
+}
+
+func (p *deductionRepository) updateValue(taxYear int, key string, value driver.Valuer) error {
+	stmt, err := p.db.Prepare(`UPDATE tax_deduction_setting SET value = $3 WHERE tax_year = $1 AND "key" = $2`)
 
 	if err != nil {
 		return err
 	}
 
-	_, err = stmt.Exec(key, deduction)
+	defer stmt.Close()
+
+	updated, err := stmt.Exec(taxYear, key, value)
 
 	if err != nil {
 		return err
 	}
 
+	count, err := updated.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return ErrDeductionNotFound
+	}
+
 	return nil
 }
+
+// UpdateDeductions changes several settings of an existing tax year in one transaction, nothing is
+// changed when one of them fails
+func (p *deductionRepository) UpdateDeductions(taxYear int, deductions []Deduction) error {
+	tx, err := p.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	// rollback is no-op after commit
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`UPDATE tax_deduction_setting SET value = $3 WHERE tax_year = $1 AND "key" = $2`)
+
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	for _, deduction := range deductions {
+		updated, err := stmt.Exec(taxYear, deduction.Key, deduction.Value)
+
+		if err != nil {
+			return err
+		}
+
+		count, err := updated.RowsAffected()
+
+		if err != nil {
+			return err
+		}
+
+		if count == 0 {
+			return ErrDeductionNotFound
+		}
+	}
+
+	return tx.Commit()
+}
@@ -5,7 +5,10 @@ import (
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
 	"github.com/larb26656/assessment-tax/constant/deductionType"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/money"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,11 +23,12 @@ func TestGetDeduction_ShouldReturnError_WhenErrorOnPrepare(t *testing.T) {
 	}
 
 	repo := NewDeductionsRepository(db)
+	year := taxYear.Default
 	key := deductionType.Personal
-	mock.ExpectPrepare(`SELECT value FROM tax_deduction_setting WHERE "key" = \$1`).WillReturnError(errors.New("error on prepare"))
+	mock.ExpectPrepare(`SELECT value FROM tax_deduction_setting WHERE tax_year = \$1 AND "key" = \$2`).WillReturnError(errors.New("error on prepare"))
 
 	// Act
-	_, err = repo.GetDeduction(key)
+	_, err = repo.GetDeduction(year, key)
 
 	// Assert
 	assert.Error(t, err)
@@ -39,17 +43,40 @@ func TestGetDeduction_ShouldReturnError_WhenErrorOnScan(t *testing.T) {
 	}
 
 	repo := NewDeductionsRepository(db)
+	year := taxYear.Default
 	key := deductionType.Personal
-	mock.ExpectPrepare(`SELECT value FROM tax_deduction_setting WHERE "key" = \$1`).ExpectQuery().
-		WithArgs(key).WillReturnError(errors.New("error on scan"))
+	mock.ExpectPrepare(`SELECT value FROM tax_deduction_setting WHERE tax_year = \$1 AND "key" = \$2`).ExpectQuery().
+		WithArgs(year, key).WillReturnError(errors.New("error on scan"))
 
 	// Act
-	_, err = repo.GetDeduction(key)
+	_, err = repo.GetDeduction(year, key)
 
 	// Assert
 	assert.Error(t, err)
 }
 
+func TestGetDeduction_ShouldReturnErrDeductionNotFound_WhenNoRows(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewDeductionsRepository(db)
+	year := 2568
+	key := deductionType.Personal
+	rows := sqlmock.NewRows([]string{"value"})
+	mock.ExpectPrepare(`SELECT value FROM tax_deduction_setting WHERE tax_year = \$1 AND "key" = \$2`).ExpectQuery().
+		WithArgs(year, key).WillReturnRows(rows)
+
+	// Act
+	_, err = repo.GetDeduction(year, key)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrDeductionNotFound)
+}
+
 func TestGetDeduction_ShouldReturnDeduction_WhenCorrectInput(t *testing.T) {
 	// Arrange
 	db, mock, err := sqlmock.New()
@@ -59,17 +86,67 @@ func TestGetDeduction_ShouldReturnDeduction_WhenCorrectInput(t *testing.T) {
 	}
 
 	repo := NewDeductionsRepository(db)
+	year := taxYear.Default
 	key := deductionType.Personal
-	rows := sqlmock.NewRows([]string{"value"}).AddRow(70000.0)
-	mock.ExpectPrepare(`SELECT value FROM tax_deduction_setting WHERE "key" = \$1`).ExpectQuery().
-		WithArgs(key).WillReturnRows(rows)
+	rows := sqlmock.NewRows([]string{"value"}).AddRow("70000.50")
+	mock.ExpectPrepare(`SELECT value FROM tax_deduction_setting WHERE tax_year = \$1 AND "key" = \$2`).WillBeClosed().ExpectQuery().
+		WithArgs(year, key).WillReturnRows(rows)
+
+	// Act
+	deduction, err := repo.GetDeduction(year, key)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, money.Money(7000050), deduction)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// GetRate
+
+func TestGetRate_ShouldReturnErrDeductionNotFound_WhenNoRows(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewDeductionsRepository(db)
+	year := 2568
+	key := allowanceType.DonationCapRate
+	rows := sqlmock.NewRows([]string{"value"})
+	mock.ExpectPrepare(`SELECT value FROM tax_deduction_setting WHERE tax_year = \$1 AND "key" = \$2`).ExpectQuery().
+		WithArgs(year, key).WillReturnRows(rows)
+
+	// Act
+	_, err = repo.GetRate(year, key)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrDeductionNotFound)
+}
+
+func TestGetRate_ShouldReturnRate_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewDeductionsRepository(db)
+	year := taxYear.Default
+	key := allowanceType.DonationCapRate
+	rows := sqlmock.NewRows([]string{"value"}).AddRow("12.50")
+	mock.ExpectPrepare(`SELECT value FROM tax_deduction_setting WHERE tax_year = \$1 AND "key" = \$2`).WillBeClosed().ExpectQuery().
+		WithArgs(year, key).WillReturnRows(rows)
 
 	// Act
-	deduction, err := repo.GetDeduction(key)
+	rate, err := repo.GetRate(year, key)
 
 	// Assert
 	assert.NoError(t, err)
-	assert.Equal(t, 70000.0, deduction)
+	assert.Equal(t, money.Rate(1250), rate)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 // UpdateDeduction
@@ -83,12 +160,13 @@ func TestUpdateDeduction_ShouldReturnErrorOnPrepare_WhenCorrectInput(t *testing.
 	}
 
 	repo := NewDeductionsRepository(db)
+	year := taxYear.Default
 	key := deductionType.Personal
-	deduction := 20000.0
-	mock.ExpectPrepare(`UPDATE tax_deduction_setting SET value=\$2 WHERE "key" = \$1`).WillReturnError(errors.New("error on prepare")) // 1 row affected
+	deduction := money.FromBaht(20000)
+	mock.ExpectPrepare(`UPDATE tax_deduction_setting SET value = \$3 WHERE tax_year = \$1 AND "key" = \$2`).WillReturnError(errors.New("error on prepare")) // 1 row affected
 
 	// Act
-	err = repo.UpdateDeduction(key, deduction)
+	err = repo.UpdateDeduction(year, key, deduction)
 
 	// Assert
 	assert.Error(t, err)
@@ -103,12 +181,13 @@ func TestUpdateDeduction_ShouldReturnErrorOnExec_WhenCorrectInput(t *testing.T)
 	}
 
 	repo := NewDeductionsRepository(db)
+	year := taxYear.Default
 	key := deductionType.Personal
-	deduction := 20000.0
-	mock.ExpectPrepare(`UPDATE tax_deduction_setting SET value=\$2 WHERE "key" = \$1`).ExpectExec().WillReturnError(errors.New("error on prepare")) // 1 row affected
+	deduction := money.FromBaht(20000)
+	mock.ExpectPrepare(`UPDATE tax_deduction_setting SET value = \$3 WHERE tax_year = \$1 AND "key" = \$2`).ExpectExec().WillReturnError(errors.New("error on prepare")) // 1 row affected
 
 	// Act
-	err = repo.UpdateDeduction(key, deduction)
+	err = repo.UpdateDeduction(year, key, deduction)
 
 	// Assert
 	assert.Error(t, err)
@@ -123,14 +202,167 @@ func TestUpdateDeduction_ShouldSuccess_WhenCorrectInput(t *testing.T) {
 	}
 
 	repo := NewDeductionsRepository(db)
+	year := taxYear.Default
+	key := deductionType.Personal
+	deduction := money.FromBaht(20000)
+	mock.ExpectPrepare(`UPDATE tax_deduction_setting SET value = \$3 WHERE tax_year = \$1 AND "key" = \$2`).WillBeClosed().ExpectExec().
+		WithArgs(year, key, deduction).WillReturnResult(sqlmock.NewResult(0, 1)) // 1 row affected
+
+	// Act
+	err = repo.UpdateDeduction(year, key, deduction)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateDeduction_ShouldReturnErrDeductionNotFound_WhenNoRowsAffected(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewDeductionsRepository(db)
+	year := 2570
 	key := deductionType.Personal
-	deduction := 20000.0
-	mock.ExpectPrepare(`UPDATE tax_deduction_setting SET value=\$2 WHERE "key" = \$1`).ExpectExec().
-		WithArgs(key, deduction).WillReturnResult(sqlmock.NewResult(0, 1)) // 1 row affected
+	deduction := money.FromBaht(20000)
+	mock.ExpectPrepare(`UPDATE tax_deduction_setting SET value = \$3 WHERE tax_year = \$1 AND "key" = \$2`).ExpectExec().
+		WithArgs(year, key, deduction).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err = repo.UpdateDeduction(year, key, deduction)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrDeductionNotFound)
+}
+
+// UpdateRate
+
+func TestUpdateRate_ShouldSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewDeductionsRepository(db)
+	year := taxYear.Default
+	key := allowanceType.DonationCapRate
+	rate := money.FromPercent(15)
+	mock.ExpectPrepare(`UPDATE tax_deduction_setting SET value = \$3 WHERE tax_year = \$1 AND "key" = \$2`).WillBeClosed().ExpectExec().
+		WithArgs(year, key, rate).WillReturnResult(sqlmock.NewResult(0, 1)) // 1 row affected
+
+	// Act
+	err = repo.UpdateRate(year, key, rate)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateRate_ShouldReturnErrDeductionNotFound_WhenNoRowsAffected(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewDeductionsRepository(db)
+	year := 2568
+	key := allowanceType.DonationCapRate
+	rate := money.FromPercent(15)
+	mock.ExpectPrepare(`UPDATE tax_deduction_setting SET value = \$3 WHERE tax_year = \$1 AND "key" = \$2`).ExpectExec().
+		WithArgs(year, key, rate).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Act
+	err = repo.UpdateRate(year, key, rate)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrDeductionNotFound)
+}
+
+// UpdateDeductions
+
+func TestUpdateDeductions_ShouldRollback_WhenErrorOnExec(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewDeductionsRepository(db)
+	year := taxYear.Default
+	mock.ExpectBegin()
+	prepare := mock.ExpectPrepare(`UPDATE tax_deduction_setting SET value = \$3 WHERE tax_year = \$1 AND "key" = \$2`)
+	prepare.ExpectExec().WithArgs(year, deductionType.Spouse, money.FromBaht(60000)).WillReturnResult(sqlmock.NewResult(0, 1))
+	prepare.ExpectExec().WithArgs(year, deductionType.Child, money.FromBaht(30000)).WillReturnError(errors.New("error on exec"))
+	mock.ExpectRollback()
+
+	// Act
+	err = repo.UpdateDeductions(year, []Deduction{
+		{Key: deductionType.Spouse, Value: money.FromBaht(60000)},
+		{Key: deductionType.Child, Value: money.FromBaht(30000)},
+	})
+
+	// Assert
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateDeductions_ShouldRollbackWithErrDeductionNotFound_WhenNoRowsAffected(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewDeductionsRepository(db)
+	year := 2570
+	mock.ExpectBegin()
+	mock.ExpectPrepare(`UPDATE tax_deduction_setting SET value = \$3 WHERE tax_year = \$1 AND "key" = \$2`).ExpectExec().
+		WithArgs(year, deductionType.Spouse, money.FromBaht(60000)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	// Act
+	err = repo.UpdateDeductions(year, []Deduction{
+		{Key: deductionType.Spouse, Value: money.FromBaht(60000)},
+		{Key: deductionType.Child, Value: money.FromBaht(30000)},
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrDeductionNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateDeductions_ShouldCommit_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewDeductionsRepository(db)
+	year := taxYear.Default
+	mock.ExpectBegin()
+	prepare := mock.ExpectPrepare(`UPDATE tax_deduction_setting SET value = \$3 WHERE tax_year = \$1 AND "key" = \$2`)
+	prepare.ExpectExec().WithArgs(year, deductionType.Spouse, money.FromBaht(60000)).WillReturnResult(sqlmock.NewResult(0, 1))
+	prepare.ExpectExec().WithArgs(year, deductionType.Child, money.FromBaht(30000)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
 
 	// Act
-	err = repo.UpdateDeduction(key, deduction)
+	err = repo.UpdateDeductions(year, []Deduction{
+		{Key: deductionType.Spouse, Value: money.FromBaht(60000)},
+		{Key: deductionType.Child, Value: money.FromBaht(30000)},
+	})
 
 	// Assert
 	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
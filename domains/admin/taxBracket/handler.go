@@ -0,0 +1,103 @@
+package taxBracket
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+)
+
+type TaxBracketHttpHandler interface {
+	GetTaxBrackets(c echo.Context) error
+	ValidateTaxBrackets(c echo.Context) error
+	UpdateTaxBrackets(c echo.Context) error
+}
+
+type taxBracketHttpHandler struct {
+	taxBracketUsecase TaxBracketUsecase
+}
+
+func NewTaxBracketHttpHandler(taxBracketUsecase TaxBracketUsecase) TaxBracketHttpHandler {
+	return &taxBracketHttpHandler{
+		taxBracketUsecase: taxBracketUsecase,
+	}
+}
+
+func (t *taxBracketHttpHandler) GetTaxBrackets(c echo.Context) error {
+	year := 0
+
+	err := echo.QueryParamsBinder(c).Int("taxYear", &year).BindError()
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	year = taxYear.Resolve(year)
+
+	taxBrackets, err := t.taxBracketUsecase.GetTaxBrackets(year)
+
+	if errors.Is(err, ErrTaxBracketsNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tax brackets not found")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, NewTaxBracketsRes(year, taxBrackets))
+}
+
+func (t *taxBracketHttpHandler) ValidateTaxBrackets(c echo.Context) error {
+	var req UpdateTaxBracketsReq
+
+	err := c.Bind(&req)
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if err = c.Validate(req); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, t.taxBracketUsecase.ValidateTaxBrackets(req))
+}
+
+func (t *taxBracketHttpHandler) UpdateTaxBrackets(c echo.Context) error {
+	var req UpdateTaxBracketsReq
+
+	err := c.Bind(&req)
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if err = c.Validate(req); err != nil {
+		return err
+	}
+
+	res, err := t.taxBracketUsecase.UpdateTaxBrackets(req)
+
+	if errors.Is(err, ErrTaxBracketsNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Tax year not found, clone the tax year first")
+	}
+
+	if err != nil {
+		var validationErr *ValidationError
+
+		if errors.As(err, &validationErr) {
+			return echo.NewHTTPError(http.StatusBadRequest, validationErr.Error())
+		}
+
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
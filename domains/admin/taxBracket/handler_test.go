@@ -0,0 +1,331 @@
+package taxBracket
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/money"
+	myValidator "github.com/larb26656/assessment-tax/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTaxBracketUsecaseCaseSuccess struct {
+}
+
+func (m *mockTaxBracketUsecaseCaseSuccess) GetTaxBrackets(taxYear int) ([]TaxBracket, error) {
+	return []TaxBracket{
+		{Level: 1, MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(150000), Rate: money.FromPercent(0)},
+		{Level: 2, MinIncome: money.FromBaht(150000), MaxIncome: nil, Rate: money.FromPercent(10)},
+	}, nil
+}
+
+func (m *mockTaxBracketUsecaseCaseSuccess) ValidateTaxBrackets(req UpdateTaxBracketsReq) ValidateTaxBracketsRes {
+	return ValidateTaxBracketsRes{
+		Valid:  true,
+		Errors: []string{},
+	}
+}
+
+func (m *mockTaxBracketUsecaseCaseSuccess) UpdateTaxBrackets(req UpdateTaxBracketsReq) (TaxBracketsRes, error) {
+	return TaxBracketsRes{
+		TaxYear: 2567,
+		TaxBrackets: []TaxBracketRes{
+			{Level: 1, Label: "0-150,000", MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(150000), Rate: money.FromPercent(0)},
+			{Level: 2, Label: "150,001 ขึ้นไป", MinIncome: money.FromBaht(150000), MaxIncome: nil, Rate: money.FromPercent(10)},
+		},
+	}, nil
+}
+
+func mockTaxBracketHttpReq(method string, reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	return mockTaxBracketHttpReqWithTarget(method, "/admin/tax-brackets", reqBody)
+}
+
+func mockTaxBracketHttpReqWithTarget(method string, target string, reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	req := httptest.NewRequest(method, target, strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+var mockUpdateTaxBracketsReqBody = `{
+	"taxBrackets": [
+		{
+			"minIncome": 0,
+			"maxIncome": 150000,
+			"rate": 0
+		},
+		{
+			"minIncome": 150000,
+			"maxIncome": null,
+			"rate": 10
+		}
+	]
+}`
+
+var mockTaxBracketsResBody = `{
+	"taxYear": 2567,
+	"taxBrackets": [
+		{
+			"level": 1,
+			"label": "0-150,000",
+			"minIncome": 0,
+			"maxIncome": 150000,
+			"rate": 0
+		},
+		{
+			"level": 2,
+			"label": "150,001 ขึ้นไป",
+			"minIncome": 150000,
+			"maxIncome": null,
+			"rate": 10
+		}
+	]
+}`
+
+// GetTaxBrackets
+func TestGetTaxBracketsHandler_ShouldGetSuccess_WhenTaxBracketsFound(t *testing.T) {
+	// Arrange
+	handler := NewTaxBracketHttpHandler(&mockTaxBracketUsecaseCaseSuccess{})
+	_, c, rec := mockTaxBracketHttpReq(http.MethodGet, "")
+
+	// Act
+	err := handler.GetTaxBrackets(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.JSONEq(t, mockTaxBracketsResBody, rec.Body.String())
+}
+
+func TestGetTaxBracketsHandler_ShouldGetBadRequest_WhenInvalidTaxYear(t *testing.T) {
+	// Arrange
+	handler := NewTaxBracketHttpHandler(&mockTaxBracketUsecaseCaseSuccess{})
+	_, c, _ := mockTaxBracketHttpReqWithTarget(http.MethodGet, "/admin/tax-brackets?taxYear=abc", "")
+
+	// Act
+	err := handler.GetTaxBrackets(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+}
+
+type mockTaxBracketUsecaseCaseNotFound struct {
+	mockTaxBracketUsecaseCaseSuccess
+}
+
+func (m *mockTaxBracketUsecaseCaseNotFound) GetTaxBrackets(taxYear int) ([]TaxBracket, error) {
+	return nil, ErrTaxBracketsNotFound
+}
+
+func TestGetTaxBracketsHandler_ShouldGetNotFound_WhenTaxYearNotFound(t *testing.T) {
+	// Arrange
+	handler := NewTaxBracketHttpHandler(&mockTaxBracketUsecaseCaseNotFound{})
+	_, c, _ := mockTaxBracketHttpReqWithTarget(http.MethodGet, "/admin/tax-brackets?taxYear=2500", "")
+
+	// Act
+	err := handler.GetTaxBrackets(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, he.Code)
+}
+
+type mockTaxBracketUsecaseCaseError struct {
+}
+
+func (m *mockTaxBracketUsecaseCaseError) GetTaxBrackets(taxYear int) ([]TaxBracket, error) {
+	return nil, errors.New("error on get")
+}
+
+func (m *mockTaxBracketUsecaseCaseError) ValidateTaxBrackets(req UpdateTaxBracketsReq) ValidateTaxBracketsRes {
+	return ValidateTaxBracketsRes{
+		Valid:  false,
+		Errors: []string{"level 1 must start at 0"},
+	}
+}
+
+func (m *mockTaxBracketUsecaseCaseError) UpdateTaxBrackets(req UpdateTaxBracketsReq) (TaxBracketsRes, error) {
+	return TaxBracketsRes{}, errors.New("error on update")
+}
+
+func TestGetTaxBracketsHandler_ShouldGetInternalServerError_WhenErrorOnGet(t *testing.T) {
+	// Arrange
+	handler := NewTaxBracketHttpHandler(&mockTaxBracketUsecaseCaseError{})
+	_, c, _ := mockTaxBracketHttpReq(http.MethodGet, "")
+
+	// Act
+	err := handler.GetTaxBrackets(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, he.Code)
+}
+
+// ValidateTaxBrackets
+func TestValidateTaxBracketsHandler_ShouldGetBadRequest_WhenWrongInput(t *testing.T) {
+	// Arrange
+	handler := NewTaxBracketHttpHandler(&mockTaxBracketUsecaseCaseSuccess{})
+
+	testCases := []struct {
+		name    string
+		reqBody string
+	}{
+		{"Test case 1", ``},
+		{"Test case 2", `{}`},
+		{"Test case 3", `{"taxBrackets": []}`},
+		{"Test case 4", `{"taxBrackets": [{"minIncome": -1, "maxIncome": null, "rate": 10}]}`},
+		{"Test case 5", `{"taxBrackets": [{"minIncome": 0, "maxIncome": null, "rate": 101}]}`},
+		{"Test case 6", `{"taxBrackets": [{"minIncome": "a", "maxIncome": null, "rate": 10}]}`},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, _ := mockTaxBracketHttpReq(http.MethodPost, tc.reqBody)
+			err := handler.ValidateTaxBrackets(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+func TestValidateTaxBracketsHandler_ShouldGetResult_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	handler := NewTaxBracketHttpHandler(&mockTaxBracketUsecaseCaseError{})
+	_, c, rec := mockTaxBracketHttpReq(http.MethodPost, mockUpdateTaxBracketsReqBody)
+
+	// Act
+	err := handler.ValidateTaxBrackets(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.JSONEq(t, `{"valid": false, "errors": ["level 1 must start at 0"]}`, rec.Body.String())
+}
+
+// UpdateTaxBrackets
+func TestUpdateTaxBracketsHandler_ShouldGetBadRequest_WhenWrongInput(t *testing.T) {
+	// Arrange
+	handler := NewTaxBracketHttpHandler(&mockTaxBracketUsecaseCaseSuccess{})
+
+	testCases := []struct {
+		name    string
+		reqBody string
+	}{
+		{"Test case 1", ``},
+		{"Test case 2", `{}`},
+		{"Test case 3", `{"taxBrackets": []}`},
+		{"Test case 4", `{"taxBrackets": [{"minIncome": -1, "maxIncome": null, "rate": 10}]}`},
+		{"Test case 5", `{"taxBrackets": [{"minIncome": 0, "maxIncome": -1, "rate": 10}]}`},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, _ := mockTaxBracketHttpReq(http.MethodPut, tc.reqBody)
+			err := handler.UpdateTaxBrackets(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+type mockTaxBracketUsecaseCaseValidationError struct {
+	mockTaxBracketUsecaseCaseSuccess
+}
+
+func (m *mockTaxBracketUsecaseCaseValidationError) UpdateTaxBrackets(req UpdateTaxBracketsReq) (TaxBracketsRes, error) {
+	return TaxBracketsRes{}, &ValidationError{
+		Errors: []string{"level 2 overlaps level 1"},
+	}
+}
+
+func TestUpdateTaxBracketsHandler_ShouldGetBadRequest_WhenInvalidTaxBrackets(t *testing.T) {
+	// Arrange
+	handler := NewTaxBracketHttpHandler(&mockTaxBracketUsecaseCaseValidationError{})
+	_, c, _ := mockTaxBracketHttpReq(http.MethodPut, mockUpdateTaxBracketsReqBody)
+
+	// Act
+	err := handler.UpdateTaxBrackets(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+	assert.Equal(t, "level 2 overlaps level 1", he.Message)
+}
+
+func (m *mockTaxBracketUsecaseCaseNotFound) UpdateTaxBrackets(req UpdateTaxBracketsReq) (TaxBracketsRes, error) {
+	return TaxBracketsRes{}, ErrTaxBracketsNotFound
+}
+
+func TestUpdateTaxBracketsHandler_ShouldGetNotFound_WhenTaxYearNotFound(t *testing.T) {
+	// Arrange
+	handler := NewTaxBracketHttpHandler(&mockTaxBracketUsecaseCaseNotFound{})
+	_, c, _ := mockTaxBracketHttpReq(http.MethodPut, mockUpdateTaxBracketsReqBody)
+
+	// Act
+	err := handler.UpdateTaxBrackets(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, he.Code)
+}
+
+func TestUpdateTaxBracketsHandler_ShouldGetInternalServerError_WhenErrorOnUpdate(t *testing.T) {
+	// Arrange
+	handler := NewTaxBracketHttpHandler(&mockTaxBracketUsecaseCaseError{})
+	_, c, _ := mockTaxBracketHttpReq(http.MethodPut, mockUpdateTaxBracketsReqBody)
+
+	// Act
+	err := handler.UpdateTaxBrackets(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, he.Code)
+}
+
+func TestUpdateTaxBracketsHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	handler := NewTaxBracketHttpHandler(&mockTaxBracketUsecaseCaseSuccess{})
+	_, c, rec := mockTaxBracketHttpReq(http.MethodPut, mockUpdateTaxBracketsReqBody)
+
+	// Act
+	err := handler.UpdateTaxBrackets(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.JSONEq(t, mockTaxBracketsResBody, rec.Body.String())
+}
@@ -0,0 +1,80 @@
+package taxBracket
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/larb26656/assessment-tax/money"
+)
+
+type TaxBracket struct {
+	Level     int          `json:"level"`
+	MinIncome money.Money  `json:"minIncome"`
+	MaxIncome *money.Money `json:"maxIncome"`
+	Rate      money.Rate   `json:"rate"`
+}
+
+type TaxBracketReq struct {
+	MinIncome money.Money  `json:"minIncome" validate:"gte=0"`
+	MaxIncome *money.Money `json:"maxIncome" validate:"omitempty,gte=0"`
+	Rate      money.Rate   `json:"rate" validate:"gte=0,lte=100"`
+}
+
+type UpdateTaxBracketsReq struct {
+	TaxYear     int             `json:"taxYear" validate:"omitempty,gte=2500"`
+	TaxBrackets []TaxBracketReq `json:"taxBrackets" validate:"required,min=1,dive"`
+}
+
+type TaxBracketRes struct {
+	Level     int          `json:"level"`
+	Label     string       `json:"label"`
+	MinIncome money.Money  `json:"minIncome"`
+	MaxIncome *money.Money `json:"maxIncome"`
+	Rate      money.Rate   `json:"rate"`
+}
+
+type TaxBracketsRes struct {
+	TaxYear     int             `json:"taxYear"`
+	TaxBrackets []TaxBracketRes `json:"taxBrackets"`
+}
+
+type ValidateTaxBracketsRes struct {
+	Valid  bool     `json:"valid"`
+	Errors []string `json:"errors"`
+}
+
+// Label returns the display text of the bracket e.g. "150,001-500,000" or "2,000,001 ขึ้นไป"
+func (b TaxBracket) Label() string {
+	from := formatAmount(b.MinIncome)
+
+	if b.MinIncome > 0 {
+		from = formatAmount(b.MinIncome + money.Baht)
+	}
+
+	if b.MaxIncome == nil {
+		return fmt.Sprintf("%s ขึ้นไป", from)
+	}
+
+	return fmt.Sprintf("%s-%s", from, formatAmount(*b.MaxIncome))
+}
+
+func formatAmount(amount money.Money) string {
+	integer, fraction, _ := strings.Cut(amount.String(), ".")
+
+	var sb strings.Builder
+
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteRune(',')
+		}
+
+		sb.WriteRune(digit)
+	}
+
+	if fraction != "00" {
+		sb.WriteString(".")
+		sb.WriteString(fraction)
+	}
+
+	return sb.String()
+}
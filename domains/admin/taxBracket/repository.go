@@ -0,0 +1,106 @@
+package taxBracket
+
+import (
+	"database/sql"
+)
+
+type TaxBracketRepository interface {
+	GetTaxBrackets(taxYear int) ([]TaxBracket, error)
+	ReplaceTaxBrackets(taxYear int, taxBrackets []TaxBracket) error
+}
+
+type taxBracketRepository struct {
+	db *sql.DB
+}
+
+func NewTaxBracketRepository(db *sql.DB) TaxBracketRepository {
+	return &taxBracketRepository{
+		db: db,
+	}
+}
+
+func (r *taxBracketRepository) GetTaxBrackets(taxYear int) ([]TaxBracket, error) {
+	stmt, err := r.db.Prepare(`SELECT level, min_income, max_income, rate FROM tax_bracket WHERE tax_year = $1 ORDER BY level`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer stmt.Close()
+
+	rows, err := stmt.Query(taxYear)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var taxBrackets []TaxBracket
+
+	for rows.Next() {
+		var taxBracket TaxBracket
+
+		err = rows.Scan(&taxBracket.Level, &taxBracket.MinIncome, &taxBracket.MaxIncome, &taxBracket.Rate)
+
+		if err != nil {
+			return nil, err
+		}
+
+		taxBrackets = append(taxBrackets, taxBracket)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return taxBrackets, nil
+}
+
+// ReplaceTaxBrackets replaces the brackets of an existing tax year, it returns ErrTaxBracketsNotFound
+// when the year has none
+func (r *taxBracketRepository) ReplaceTaxBrackets(taxYear int, taxBrackets []TaxBracket) error {
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	// rollback is no-op after commit
+	defer tx.Rollback()
+
+	deleted, err := tx.Exec(`DELETE FROM tax_bracket WHERE tax_year = $1`, taxYear)
+
+	if err != nil {
+		return err
+	}
+
+	count, err := deleted.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	// a year without brackets does not exist, it is made by cloning
+	if count == 0 {
+		return ErrTaxBracketsNotFound
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO tax_bracket (tax_year, level, min_income, max_income, rate) VALUES ($1, $2, $3, $4, $5)`)
+
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	for _, taxBracket := range taxBrackets {
+		_, err = stmt.Exec(taxYear, taxBracket.Level, taxBracket.MinIncome, taxBracket.MaxIncome, taxBracket.Rate)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
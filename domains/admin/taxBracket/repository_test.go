@@ -0,0 +1,210 @@
+package taxBracket
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/larb26656/assessment-tax/money"
+	"github.com/stretchr/testify/assert"
+)
+
+// GetTaxBrackets
+
+func TestGetTaxBrackets_ShouldReturnError_WhenErrorOnPrepare(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBracketRepository(db)
+	mock.ExpectPrepare(`SELECT level, min_income, max_income, rate FROM tax_bracket WHERE tax_year = \$1 ORDER BY level`).WillReturnError(errors.New("error on prepare"))
+
+	// Act
+	_, err = repo.GetTaxBrackets(2567)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestGetTaxBrackets_ShouldReturnError_WhenErrorOnQuery(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBracketRepository(db)
+	mock.ExpectPrepare(`SELECT level, min_income, max_income, rate FROM tax_bracket WHERE tax_year = \$1 ORDER BY level`).ExpectQuery().
+		WithArgs(2567).WillReturnError(errors.New("error on query"))
+
+	// Act
+	_, err = repo.GetTaxBrackets(2567)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestGetTaxBrackets_ShouldReturnError_WhenErrorOnScan(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBracketRepository(db)
+	rows := sqlmock.NewRows([]string{"level", "min_income", "max_income", "rate"}).AddRow("a", "0.00", "150000.00", "0.00")
+	mock.ExpectPrepare(`SELECT level, min_income, max_income, rate FROM tax_bracket WHERE tax_year = \$1 ORDER BY level`).ExpectQuery().
+		WithArgs(2567).WillReturnRows(rows)
+
+	// Act
+	_, err = repo.GetTaxBrackets(2567)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestGetTaxBrackets_ShouldReturnTaxBrackets_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBracketRepository(db)
+	rows := sqlmock.NewRows([]string{"level", "min_income", "max_income", "rate"}).
+		AddRow(1, "0.00", "150000.00", "0.00").
+		AddRow(2, "150000.00", nil, "10.00")
+	mock.ExpectPrepare(`SELECT level, min_income, max_income, rate FROM tax_bracket WHERE tax_year = \$1 ORDER BY level`).ExpectQuery().
+		WithArgs(2567).WillReturnRows(rows)
+
+	// Act
+	taxBrackets, err := repo.GetTaxBrackets(2567)
+
+	// Assert
+	maxIncome := money.FromBaht(150000)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []TaxBracket{
+		{Level: 1, MinIncome: money.FromBaht(0), MaxIncome: &maxIncome, Rate: money.FromPercent(0)},
+		{Level: 2, MinIncome: money.FromBaht(150000), MaxIncome: nil, Rate: money.FromPercent(10)},
+	}, taxBrackets)
+}
+
+// ReplaceTaxBrackets
+
+func TestReplaceTaxBrackets_ShouldReturnError_WhenErrorOnBegin(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBracketRepository(db)
+	mock.ExpectBegin().WillReturnError(errors.New("error on begin"))
+
+	// Act
+	err = repo.ReplaceTaxBrackets(2567, []TaxBracket{{Level: 1, MinIncome: money.FromBaht(0), MaxIncome: nil, Rate: money.FromPercent(10)}})
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestReplaceTaxBrackets_ShouldRollback_WhenErrorOnDelete(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBracketRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM tax_bracket WHERE tax_year = \$1`).WithArgs(2567).WillReturnError(errors.New("error on delete"))
+	mock.ExpectRollback()
+
+	// Act
+	err = repo.ReplaceTaxBrackets(2567, []TaxBracket{{Level: 1, MinIncome: money.FromBaht(0), MaxIncome: nil, Rate: money.FromPercent(10)}})
+
+	// Assert
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplaceTaxBrackets_ShouldReturnErrTaxBracketsNotFound_WhenTaxYearHasNoBrackets(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBracketRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM tax_bracket WHERE tax_year = \$1`).WithArgs(2570).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	// Act
+	err = repo.ReplaceTaxBrackets(2570, []TaxBracket{{Level: 1, MinIncome: money.FromBaht(0), MaxIncome: nil, Rate: money.FromPercent(10)}})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrTaxBracketsNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplaceTaxBrackets_ShouldRollback_WhenErrorOnInsert(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBracketRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM tax_bracket WHERE tax_year = \$1`).WithArgs(2567).WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectPrepare(`INSERT INTO tax_bracket \(tax_year, level, min_income, max_income, rate\) VALUES \(\$1, \$2, \$3, \$4, \$5\)`).ExpectExec().
+		WillReturnError(errors.New("error on insert"))
+	mock.ExpectRollback()
+
+	// Act
+	err = repo.ReplaceTaxBrackets(2567, []TaxBracket{{Level: 1, MinIncome: money.FromBaht(0), MaxIncome: nil, Rate: money.FromPercent(10)}})
+
+	// Assert
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplaceTaxBrackets_ShouldSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBracketRepository(db)
+	maxIncome := money.FromBaht(150000)
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM tax_bracket WHERE tax_year = \$1`).WithArgs(2567).WillReturnResult(sqlmock.NewResult(0, 5))
+	prepare := mock.ExpectPrepare(`INSERT INTO tax_bracket \(tax_year, level, min_income, max_income, rate\) VALUES \(\$1, \$2, \$3, \$4, \$5\)`)
+	prepare.ExpectExec().WithArgs(2567, 1, "0.00", "150000.00", "0.00").WillReturnResult(sqlmock.NewResult(0, 1))
+	prepare.ExpectExec().WithArgs(2567, 2, "150000.00", nil, "10.00").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err = repo.ReplaceTaxBrackets(2567, []TaxBracket{
+		{Level: 1, MinIncome: money.FromBaht(0), MaxIncome: &maxIncome, Rate: money.FromPercent(0)},
+		{Level: 2, MinIncome: money.FromBaht(150000), MaxIncome: nil, Rate: money.FromPercent(10)},
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,152 @@
+package taxBracket
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+)
+
+type TaxBracketUsecase interface {
+	GetTaxBrackets(taxYear int) ([]TaxBracket, error)
+	ValidateTaxBrackets(req UpdateTaxBracketsReq) ValidateTaxBracketsRes
+	UpdateTaxBrackets(req UpdateTaxBracketsReq) (TaxBracketsRes, error)
+}
+
+var ErrTaxBracketsNotFound = errors.New("tax brackets not found")
+
+// ValidationError is returned when the submitted bracket table is not a valid rate schedule
+type ValidationError struct {
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Errors, ", ")
+}
+
+type taxBracketUsecase struct {
+	taxBracketRepository TaxBracketRepository
+}
+
+func NewTaxBracketUsecase(taxBracketRepository TaxBracketRepository) TaxBracketUsecase {
+	return &taxBracketUsecase{
+		taxBracketRepository: taxBracketRepository,
+	}
+}
+
+func NewTaxBracketsRes(taxYear int, taxBrackets []TaxBracket) TaxBracketsRes {
+	res := TaxBracketsRes{
+		TaxYear:     taxYear,
+		TaxBrackets: make([]TaxBracketRes, len(taxBrackets)),
+	}
+
+	for i, taxBracket := range taxBrackets {
+		res.TaxBrackets[i] = TaxBracketRes{
+			Level:     taxBracket.Level,
+			Label:     taxBracket.Label(),
+			MinIncome: taxBracket.MinIncome,
+			MaxIncome: taxBracket.MaxIncome,
+			Rate:      taxBracket.Rate,
+		}
+	}
+
+	return res
+}
+
+func (t *taxBracketUsecase) GetTaxBrackets(taxYear int) ([]TaxBracket, error) {
+	taxBrackets, err := t.taxBracketRepository.GetTaxBrackets(taxYear)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(taxBrackets) == 0 {
+		return nil, ErrTaxBracketsNotFound
+	}
+
+	return taxBrackets, nil
+}
+
+func (t *taxBracketUsecase) ValidateTaxBrackets(req UpdateTaxBracketsReq) ValidateTaxBracketsRes {
+	errs := []string{}
+	openEndedCount := 0
+
+	for i, taxBracket := range req.TaxBrackets {
+		level := i + 1
+
+		if i == 0 && taxBracket.MinIncome != 0 {
+			errs = append(errs, fmt.Sprintf("level %d must start at 0", level))
+		}
+
+		if i > 0 {
+			prev := req.TaxBrackets[i-1]
+
+			if prev.MaxIncome != nil && taxBracket.MinIncome > *prev.MaxIncome {
+				errs = append(errs, fmt.Sprintf("level %d must start at the end of level %d, found a gap", level, level-1))
+			}
+
+			if prev.MaxIncome != nil && taxBracket.MinIncome < *prev.MaxIncome {
+				errs = append(errs, fmt.Sprintf("level %d overlaps level %d", level, level-1))
+			}
+
+			if taxBracket.Rate <= prev.Rate {
+				errs = append(errs, fmt.Sprintf("level %d rate must be greater than level %d rate", level, level-1))
+			}
+		}
+
+		if taxBracket.MaxIncome == nil {
+			openEndedCount++
+
+			if i != len(req.TaxBrackets)-1 {
+				errs = append(errs, fmt.Sprintf("level %d is open-ended but is not the top level", level))
+			}
+
+			continue
+		}
+
+		if *taxBracket.MaxIncome <= taxBracket.MinIncome {
+			errs = append(errs, fmt.Sprintf("level %d max income must be greater than min income", level))
+		}
+	}
+
+	if openEndedCount != 1 {
+		errs = append(errs, "there must be exactly one open-ended top level")
+	}
+
+	return ValidateTaxBracketsRes{
+		Valid:  len(errs) == 0,
+		Errors: errs,
+	}
+}
+
+func (t *taxBracketUsecase) UpdateTaxBrackets(req UpdateTaxBracketsReq) (TaxBracketsRes, error) {
+	validateRes := t.ValidateTaxBrackets(req)
+
+	if !validateRes.Valid {
+		return TaxBracketsRes{}, &ValidationError{
+			Errors: validateRes.Errors,
+		}
+	}
+
+	taxBrackets := make([]TaxBracket, len(req.TaxBrackets))
+
+	for i, taxBracket := range req.TaxBrackets {
+		taxBrackets[i] = TaxBracket{
+			Level:     i + 1,
+			MinIncome: taxBracket.MinIncome,
+			MaxIncome: taxBracket.MaxIncome,
+			Rate:      taxBracket.Rate,
+		}
+	}
+
+	year := taxYear.Resolve(req.TaxYear)
+
+	err := t.taxBracketRepository.ReplaceTaxBrackets(year, taxBrackets)
+
+	if err != nil {
+		return TaxBracketsRes{}, err
+	}
+
+	return NewTaxBracketsRes(year, taxBrackets), nil
+}
@@ -0,0 +1,286 @@
+package taxBracket
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockMaxIncome(maxIncome int64) *money.Money {
+	amount := money.FromBaht(maxIncome)
+
+	return &amount
+}
+
+type mockTaxBracketRepositoryCaseError struct {
+}
+
+func (r *mockTaxBracketRepositoryCaseError) GetTaxBrackets(taxYear int) ([]TaxBracket, error) {
+	return nil, errors.New("error on query")
+}
+
+func (r *mockTaxBracketRepositoryCaseError) ReplaceTaxBrackets(taxYear int, taxBrackets []TaxBracket) error {
+	return nil
+}
+
+// GetTaxBrackets
+func TestGetTaxBrackets_ShouldReturnErr_WhenRepositoryError(t *testing.T) {
+	// Arrange
+	usecase := NewTaxBracketUsecase(&mockTaxBracketRepositoryCaseError{})
+
+	// Act
+	_, err := usecase.GetTaxBrackets(2567)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+type mockTaxBracketRepositoryCaseEmpty struct {
+}
+
+func (r *mockTaxBracketRepositoryCaseEmpty) GetTaxBrackets(taxYear int) ([]TaxBracket, error) {
+	return nil, nil
+}
+
+func (r *mockTaxBracketRepositoryCaseEmpty) ReplaceTaxBrackets(taxYear int, taxBrackets []TaxBracket) error {
+	return nil
+}
+
+func TestGetTaxBrackets_ShouldReturnErr_WhenTaxBracketsNotFound(t *testing.T) {
+	// Arrange
+	usecase := NewTaxBracketUsecase(&mockTaxBracketRepositoryCaseEmpty{})
+
+	// Act
+	_, err := usecase.GetTaxBrackets(2567)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrTaxBracketsNotFound)
+}
+
+type mockTaxBracketRepositoryCaseFound struct {
+}
+
+func (r *mockTaxBracketRepositoryCaseFound) GetTaxBrackets(taxYear int) ([]TaxBracket, error) {
+	return []TaxBracket{
+		{Level: 1, MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(150000), Rate: money.FromPercent(0)},
+		{Level: 2, MinIncome: money.FromBaht(150000), MaxIncome: nil, Rate: money.FromPercent(10)},
+	}, nil
+}
+
+func (r *mockTaxBracketRepositoryCaseFound) ReplaceTaxBrackets(taxYear int, taxBrackets []TaxBracket) error {
+	return nil
+}
+
+func TestGetTaxBrackets_ShouldReturnTaxBrackets_WhenTaxBracketsFound(t *testing.T) {
+	// Arrange
+	usecase := NewTaxBracketUsecase(&mockTaxBracketRepositoryCaseFound{})
+
+	// Act
+	taxBrackets, err := usecase.GetTaxBrackets(2567)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, taxBrackets, 2)
+}
+
+// Label
+func TestLabel_ShouldFormatBracket_WhenCorrectInput(t *testing.T) {
+	testCases := []struct {
+		name          string
+		taxBracket    TaxBracket
+		expectedLabel string
+	}{
+		{"Test case 1", TaxBracket{Level: 1, MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(150000), Rate: money.FromPercent(0)}, "0-150,000"},
+		{"Test case 2", TaxBracket{Level: 2, MinIncome: money.FromBaht(150000), MaxIncome: mockMaxIncome(500000), Rate: money.FromPercent(10)}, "150,001-500,000"},
+		{"Test case 3", TaxBracket{Level: 4, MinIncome: money.FromBaht(1000000), MaxIncome: mockMaxIncome(2000000), Rate: money.FromPercent(20)}, "1,000,001-2,000,000"},
+		{"Test case 4", TaxBracket{Level: 5, MinIncome: money.FromBaht(2000000), MaxIncome: nil, Rate: money.FromPercent(35)}, "2,000,001 ขึ้นไป"},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			label := tc.taxBracket.Label()
+
+			// Assert
+			assert.Equal(t, tc.expectedLabel, label)
+		})
+	}
+}
+
+// ValidateTaxBrackets
+func TestValidateTaxBrackets_ShouldReturnInvalid_WhenWrongInput(t *testing.T) {
+	// Arrange
+	usecase := NewTaxBracketUsecase(&mockTaxBracketRepositoryCaseFound{})
+
+	testCases := []struct {
+		name        string
+		taxBrackets []TaxBracketReq
+	}{
+		{"Test case 1 not start at 0", []TaxBracketReq{
+			{MinIncome: money.FromBaht(100), MaxIncome: mockMaxIncome(150000), Rate: money.FromPercent(0)},
+			{MinIncome: money.FromBaht(150000), MaxIncome: nil, Rate: money.FromPercent(10)},
+		}},
+		{"Test case 2 gap", []TaxBracketReq{
+			{MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(150000), Rate: money.FromPercent(0)},
+			{MinIncome: money.FromBaht(160000), MaxIncome: nil, Rate: money.FromPercent(10)},
+		}},
+		{"Test case 3 overlap", []TaxBracketReq{
+			{MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(150000), Rate: money.FromPercent(0)},
+			{MinIncome: money.FromBaht(140000), MaxIncome: nil, Rate: money.FromPercent(10)},
+		}},
+		{"Test case 4 rate not go up", []TaxBracketReq{
+			{MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(150000), Rate: money.FromPercent(10)},
+			{MinIncome: money.FromBaht(150000), MaxIncome: nil, Rate: money.FromPercent(10)},
+		}},
+		{"Test case 5 no open-ended", []TaxBracketReq{
+			{MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(150000), Rate: money.FromPercent(0)},
+			{MinIncome: money.FromBaht(150000), MaxIncome: mockMaxIncome(500000), Rate: money.FromPercent(10)},
+		}},
+		{"Test case 6 open-ended not on top", []TaxBracketReq{
+			{MinIncome: money.FromBaht(0), MaxIncome: nil, Rate: money.FromPercent(0)},
+			{MinIncome: money.FromBaht(150000), MaxIncome: nil, Rate: money.FromPercent(10)},
+		}},
+		{"Test case 7 max less than min", []TaxBracketReq{
+			{MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(0), Rate: money.FromPercent(0)},
+			{MinIncome: money.FromBaht(0), MaxIncome: nil, Rate: money.FromPercent(10)},
+		}},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := usecase.ValidateTaxBrackets(UpdateTaxBracketsReq{TaxBrackets: tc.taxBrackets})
+
+			// Assert
+			assert.False(t, result.Valid)
+			assert.NotEmpty(t, result.Errors)
+		})
+	}
+}
+
+func TestValidateTaxBrackets_ShouldReturnValid_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	usecase := NewTaxBracketUsecase(&mockTaxBracketRepositoryCaseFound{})
+	req := UpdateTaxBracketsReq{
+		TaxBrackets: []TaxBracketReq{
+			{MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(150000), Rate: money.FromPercent(0)},
+			{MinIncome: money.FromBaht(150000), MaxIncome: mockMaxIncome(500000), Rate: money.FromPercent(10)},
+			{MinIncome: money.FromBaht(500000), MaxIncome: nil, Rate: money.FromPercent(15)},
+		},
+	}
+
+	// Act
+	result := usecase.ValidateTaxBrackets(req)
+
+	// Assert
+	assert.True(t, result.Valid)
+	assert.Empty(t, result.Errors)
+}
+
+// UpdateTaxBrackets
+type mockTaxBracketRepositoryCaseReplaceError struct {
+	taxYear     int
+	taxBrackets []TaxBracket
+}
+
+func (r *mockTaxBracketRepositoryCaseReplaceError) GetTaxBrackets(taxYear int) ([]TaxBracket, error) {
+	return nil, nil
+}
+
+func (r *mockTaxBracketRepositoryCaseReplaceError) ReplaceTaxBrackets(taxYear int, taxBrackets []TaxBracket) error {
+	r.taxYear = taxYear
+	r.taxBrackets = taxBrackets
+	return errors.New("error on replace")
+}
+
+func TestUpdateTaxBrackets_ShouldReturnValidationErr_WhenInvalidTaxBrackets(t *testing.T) {
+	// Arrange
+	repo := &mockTaxBracketRepositoryCaseReplaceError{}
+	usecase := NewTaxBracketUsecase(repo)
+	req := UpdateTaxBracketsReq{
+		TaxBrackets: []TaxBracketReq{
+			{MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(150000), Rate: money.FromPercent(0)},
+		},
+	}
+
+	// Act
+	_, err := usecase.UpdateTaxBrackets(req)
+
+	// Assert
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Nil(t, repo.taxBrackets)
+}
+
+func TestUpdateTaxBrackets_ShouldReturnErr_WhenReplaceFail(t *testing.T) {
+	// Arrange
+	repo := &mockTaxBracketRepositoryCaseReplaceError{}
+	usecase := NewTaxBracketUsecase(repo)
+	req := UpdateTaxBracketsReq{
+		TaxBrackets: []TaxBracketReq{
+			{MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(150000), Rate: money.FromPercent(0)},
+			{MinIncome: money.FromBaht(150000), MaxIncome: nil, Rate: money.FromPercent(10)},
+		},
+	}
+
+	// Act
+	_, err := usecase.UpdateTaxBrackets(req)
+
+	// Assert
+	var validationErr *ValidationError
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &validationErr))
+	assert.Equal(t, taxYear.Default, repo.taxYear)
+	assert.Len(t, repo.taxBrackets, 2)
+}
+
+type mockTaxBracketRepositoryCaseReplaceSuccess struct {
+	taxYear     int
+	taxBrackets []TaxBracket
+}
+
+func (r *mockTaxBracketRepositoryCaseReplaceSuccess) GetTaxBrackets(taxYear int) ([]TaxBracket, error) {
+	return nil, nil
+}
+
+func (r *mockTaxBracketRepositoryCaseReplaceSuccess) ReplaceTaxBrackets(taxYear int, taxBrackets []TaxBracket) error {
+	r.taxYear = taxYear
+	r.taxBrackets = taxBrackets
+	return nil
+}
+
+func TestUpdateTaxBrackets_ShouldSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	repo := &mockTaxBracketRepositoryCaseReplaceSuccess{}
+	usecase := NewTaxBracketUsecase(repo)
+	req := UpdateTaxBracketsReq{
+		TaxYear: 2568,
+		TaxBrackets: []TaxBracketReq{
+			{MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(200000), Rate: money.FromPercent(0)},
+			{MinIncome: money.FromBaht(200000), MaxIncome: nil, Rate: money.FromPercent(10)},
+		},
+	}
+
+	// Act
+	result, err := usecase.UpdateTaxBrackets(req)
+
+	// Assert
+	expectedTaxBrackets := []TaxBracket{
+		{Level: 1, MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(200000), Rate: money.FromPercent(0)},
+		{Level: 2, MinIncome: money.FromBaht(200000), MaxIncome: nil, Rate: money.FromPercent(10)},
+	}
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2568, repo.taxYear)
+	assert.Equal(t, expectedTaxBrackets, repo.taxBrackets)
+	assert.Equal(t, TaxBracketsRes{
+		TaxYear: 2568,
+		TaxBrackets: []TaxBracketRes{
+			{Level: 1, Label: "0-200,000", MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(200000), Rate: money.FromPercent(0)},
+			{Level: 2, Label: "200,001 ขึ้นไป", MinIncome: money.FromBaht(200000), MaxIncome: nil, Rate: money.FromPercent(10)},
+		},
+	}, result)
+}
@@ -0,0 +1,67 @@
+package taxYear
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TaxYearHttpHandler interface {
+	GetTaxYears(c echo.Context) error
+	CloneTaxYear(c echo.Context) error
+}
+
+type taxYearHttpHandler struct {
+	taxYearUsecase TaxYearUsecase
+}
+
+func NewTaxYearHttpHandler(taxYearUsecase TaxYearUsecase) TaxYearHttpHandler {
+	return &taxYearHttpHandler{
+		taxYearUsecase: taxYearUsecase,
+	}
+}
+
+func (t *taxYearHttpHandler) GetTaxYears(c echo.Context) error {
+	res, err := t.taxYearUsecase.GetTaxYears()
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (t *taxYearHttpHandler) CloneTaxYear(c echo.Context) error {
+	var req CloneTaxYearReq
+
+	err := c.Bind(&req)
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if err = c.Validate(req); err != nil {
+		return err
+	}
+
+	res, err := t.taxYearUsecase.CloneTaxYear(req)
+
+	if errors.Is(err, ErrSourceTaxYearNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Source tax year not found")
+	}
+
+	if errors.Is(err, ErrTaxYearAlreadyExists) {
+		return echo.NewHTTPError(http.StatusConflict, "Tax year already exists")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
@@ -0,0 +1,161 @@
+package taxYear
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	myValidator "github.com/larb26656/assessment-tax/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTaxYearUsecaseCaseSuccess struct {
+}
+
+func (m *mockTaxYearUsecaseCaseSuccess) GetTaxYears() (TaxYearsRes, error) {
+	return TaxYearsRes{
+		TaxYears: []int{2567, 2568},
+	}, nil
+}
+
+func (m *mockTaxYearUsecaseCaseSuccess) CloneTaxYear(req CloneTaxYearReq) (CloneTaxYearRes, error) {
+	return CloneTaxYearRes{
+		SourceTaxYear: 2567,
+		TaxYear:       2568,
+	}, nil
+}
+
+func mockTaxYearHttpReq(method string, reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	req := httptest.NewRequest(method, "/admin/tax-years", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+// GetTaxYears
+func TestGetTaxYearsHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	handler := NewTaxYearHttpHandler(&mockTaxYearUsecaseCaseSuccess{})
+	_, c, rec := mockTaxYearHttpReq(http.MethodGet, "")
+
+	// Act
+	err := handler.GetTaxYears(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.JSONEq(t, `{"taxYears": [2567, 2568]}`, rec.Body.String())
+}
+
+type mockTaxYearUsecaseCaseError struct {
+	err error
+}
+
+func (m *mockTaxYearUsecaseCaseError) GetTaxYears() (TaxYearsRes, error) {
+	return TaxYearsRes{}, m.err
+}
+
+func (m *mockTaxYearUsecaseCaseError) CloneTaxYear(req CloneTaxYearReq) (CloneTaxYearRes, error) {
+	return CloneTaxYearRes{}, m.err
+}
+
+func TestGetTaxYearsHandler_ShouldGetInternalServerError_WhenErrorOnGet(t *testing.T) {
+	// Arrange
+	handler := NewTaxYearHttpHandler(&mockTaxYearUsecaseCaseError{err: errors.New("error on get")})
+	_, c, _ := mockTaxYearHttpReq(http.MethodGet, "")
+
+	// Act
+	err := handler.GetTaxYears(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, he.Code)
+}
+
+// CloneTaxYear
+func TestCloneTaxYearHandler_ShouldGetBadRequest_WhenWrongInput(t *testing.T) {
+	// Arrange
+	handler := NewTaxYearHttpHandler(&mockTaxYearUsecaseCaseSuccess{})
+
+	testCases := []struct {
+		name    string
+		reqBody string
+	}{
+		{"Test case 1", ``},
+		{"Test case 2", `{}`},
+		{"Test case 3", `{"sourceTaxYear": 2567}`},
+		{"Test case 4", `{"sourceTaxYear": 2567, "taxYear": 2567}`},
+		{"Test case 5", `{"sourceTaxYear": 2567, "taxYear": 68}`},
+		{"Test case 6", `{"sourceTaxYear": "a", "taxYear": 2568}`},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, _ := mockTaxYearHttpReq(http.MethodPost, tc.reqBody)
+			err := handler.CloneTaxYear(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+func TestCloneTaxYearHandler_ShouldGetError_WhenErrorOnClone(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{"Test case 1", ErrSourceTaxYearNotFound, http.StatusNotFound},
+		{"Test case 2", ErrTaxYearAlreadyExists, http.StatusConflict},
+		{"Test case 3", errors.New("error on clone"), http.StatusInternalServerError},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler := NewTaxYearHttpHandler(&mockTaxYearUsecaseCaseError{err: tc.err})
+			_, c, _ := mockTaxYearHttpReq(http.MethodPost, `{"sourceTaxYear": 2567, "taxYear": 2568}`)
+
+			// Act
+			err := handler.CloneTaxYear(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCode, he.Code)
+		})
+	}
+}
+
+func TestCloneTaxYearHandler_ShouldGetCreated_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	handler := NewTaxYearHttpHandler(&mockTaxYearUsecaseCaseSuccess{})
+	_, c, rec := mockTaxYearHttpReq(http.MethodPost, `{"sourceTaxYear": 2567, "taxYear": 2568}`)
+
+	// Act
+	err := handler.CloneTaxYear(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Result().StatusCode)
+	assert.JSONEq(t, `{"sourceTaxYear": 2567, "taxYear": 2568}`, rec.Body.String())
+}
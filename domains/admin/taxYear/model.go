@@ -0,0 +1,15 @@
+package taxYear
+
+type CloneTaxYearReq struct {
+	SourceTaxYear int `json:"sourceTaxYear" validate:"required,gte=2500"`
+	TaxYear       int `json:"taxYear" validate:"required,gte=2500,nefield=SourceTaxYear"`
+}
+
+type CloneTaxYearRes struct {
+	SourceTaxYear int `json:"sourceTaxYear"`
+	TaxYear       int `json:"taxYear"`
+}
+
+type TaxYearsRes struct {
+	TaxYears []int `json:"taxYears"`
+}
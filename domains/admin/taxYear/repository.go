@@ -0,0 +1,96 @@
+package taxYear
+
+import (
+	"database/sql"
+)
+
+type TaxYearRepository interface {
+	GetTaxYears() ([]int, error)
+	CloneTaxYear(sourceTaxYear int, taxYear int) error
+}
+
+type taxYearRepository struct {
+	db *sql.DB
+}
+
+func NewTaxYearRepository(db *sql.DB) TaxYearRepository {
+	return &taxYearRepository{
+		db: db,
+	}
+}
+
+// GetTaxYears returns every year with a deduction setting, tax bracket or allowance group, so a clone
+// never collides with rows already there
+func (r *taxYearRepository) GetTaxYears() ([]int, error) {
+	stmt, err := r.db.Prepare(`SELECT tax_year FROM tax_deduction_setting UNION SELECT tax_year FROM tax_bracket UNION SELECT tax_year FROM tax_allowance_group ORDER BY tax_year`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer stmt.Close()
+
+	rows, err := stmt.Query()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	taxYears := []int{}
+
+	for rows.Next() {
+		var taxYear int
+
+		if err = rows.Scan(&taxYear); err != nil {
+			return nil, err
+		}
+
+		taxYears = append(taxYears, taxYear)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return taxYears, nil
+}
+
+// CloneTaxYear copies every deduction setting, allowance group and tax bracket of sourceTaxYear into taxYear
+func (r *taxYearRepository) CloneTaxYear(sourceTaxYear int, taxYear int) error {
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	// rollback is no-op after commit
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO tax_deduction_setting (tax_year, "key", value) SELECT $2, "key", value FROM tax_deduction_setting WHERE tax_year = $1`, sourceTaxYear, taxYear)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO tax_allowance_group (tax_year, "key", max_deduction) SELECT $2, "key", max_deduction FROM tax_allowance_group WHERE tax_year = $1`, sourceTaxYear, taxYear)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO tax_allowance_group_member (tax_year, group_key, allowance_type) SELECT $2, group_key, allowance_type FROM tax_allowance_group_member WHERE tax_year = $1`, sourceTaxYear, taxYear)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO tax_bracket (tax_year, level, min_income, max_income, rate) SELECT $2, level, min_income, max_income, rate FROM tax_bracket WHERE tax_year = $1`, sourceTaxYear, taxYear)
+
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,163 @@
+package taxYear
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// GetTaxYears
+
+func TestGetTaxYears_ShouldReturnError_WhenErrorOnPrepare(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxYearRepository(db)
+	mock.ExpectPrepare(`SELECT tax_year FROM tax_deduction_setting UNION SELECT tax_year FROM tax_bracket UNION SELECT tax_year FROM tax_allowance_group ORDER BY tax_year`).WillReturnError(errors.New("error on prepare"))
+
+	// Act
+	_, err = repo.GetTaxYears()
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestGetTaxYears_ShouldReturnError_WhenErrorOnQuery(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxYearRepository(db)
+	mock.ExpectPrepare(`SELECT tax_year FROM tax_deduction_setting UNION SELECT tax_year FROM tax_bracket UNION SELECT tax_year FROM tax_allowance_group ORDER BY tax_year`).ExpectQuery().
+		WillReturnError(errors.New("error on query"))
+
+	// Act
+	_, err = repo.GetTaxYears()
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestGetTaxYears_ShouldReturnTaxYears_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxYearRepository(db)
+	rows := sqlmock.NewRows([]string{"tax_year"}).AddRow(2567).AddRow(2568)
+	mock.ExpectPrepare(`SELECT tax_year FROM tax_deduction_setting UNION SELECT tax_year FROM tax_bracket UNION SELECT tax_year FROM tax_allowance_group ORDER BY tax_year`).ExpectQuery().
+		WillReturnRows(rows)
+
+	// Act
+	taxYears, err := repo.GetTaxYears()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2567, 2568}, taxYears)
+}
+
+// CloneTaxYear
+
+func TestCloneTaxYear_ShouldRollback_WhenErrorOnCopyDeduction(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxYearRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO tax_deduction_setting`).WithArgs(2567, 2568).WillReturnError(errors.New("error on insert"))
+	mock.ExpectRollback()
+
+	// Act
+	err = repo.CloneTaxYear(2567, 2568)
+
+	// Assert
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCloneTaxYear_ShouldRollback_WhenErrorOnCopyAllowanceGroup(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxYearRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO tax_deduction_setting`).WithArgs(2567, 2568).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO tax_allowance_group `).WithArgs(2567, 2568).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO tax_allowance_group_member`).WithArgs(2567, 2568).WillReturnError(errors.New("error on insert"))
+	mock.ExpectRollback()
+
+	// Act
+	err = repo.CloneTaxYear(2567, 2568)
+
+	// Assert
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCloneTaxYear_ShouldRollback_WhenErrorOnCopyTaxBracket(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxYearRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO tax_deduction_setting`).WithArgs(2567, 2568).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO tax_allowance_group `).WithArgs(2567, 2568).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO tax_allowance_group_member`).WithArgs(2567, 2568).WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(`INSERT INTO tax_bracket`).WithArgs(2567, 2568).WillReturnError(errors.New("error on insert"))
+	mock.ExpectRollback()
+
+	// Act
+	err = repo.CloneTaxYear(2567, 2568)
+
+	// Assert
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCloneTaxYear_ShouldSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxYearRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO tax_deduction_setting`).WithArgs(2567, 2568).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO tax_allowance_group `).WithArgs(2567, 2568).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO tax_allowance_group_member`).WithArgs(2567, 2568).WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(`INSERT INTO tax_bracket`).WithArgs(2567, 2568).WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectCommit()
+
+	// Act
+	err = repo.CloneTaxYear(2567, 2568)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,63 @@
+package taxYear
+
+import (
+	"errors"
+	"slices"
+)
+
+var ErrSourceTaxYearNotFound = errors.New("source tax year not found")
+var ErrTaxYearAlreadyExists = errors.New("tax year already exists")
+
+type TaxYearUsecase interface {
+	GetTaxYears() (TaxYearsRes, error)
+	CloneTaxYear(req CloneTaxYearReq) (CloneTaxYearRes, error)
+}
+
+type taxYearUsecase struct {
+	taxYearRepository TaxYearRepository
+}
+
+func NewTaxYearUsecase(taxYearRepository TaxYearRepository) TaxYearUsecase {
+	return &taxYearUsecase{
+		taxYearRepository: taxYearRepository,
+	}
+}
+
+func (t *taxYearUsecase) GetTaxYears() (TaxYearsRes, error) {
+	taxYears, err := t.taxYearRepository.GetTaxYears()
+
+	if err != nil {
+		return TaxYearsRes{}, err
+	}
+
+	return TaxYearsRes{
+		TaxYears: taxYears,
+	}, nil
+}
+
+func (t *taxYearUsecase) CloneTaxYear(req CloneTaxYearReq) (CloneTaxYearRes, error) {
+	taxYears, err := t.taxYearRepository.GetTaxYears()
+
+	if err != nil {
+		return CloneTaxYearRes{}, err
+	}
+
+	if !slices.Contains(taxYears, req.SourceTaxYear) {
+		return CloneTaxYearRes{}, ErrSourceTaxYearNotFound
+	}
+
+	if slices.Contains(taxYears, req.TaxYear) {
+		return CloneTaxYearRes{}, ErrTaxYearAlreadyExists
+	}
+
+	err = t.taxYearRepository.CloneTaxYear(req.SourceTaxYear, req.TaxYear)
+
+	if err != nil {
+		return CloneTaxYearRes{}, err
+	}
+
+	return CloneTaxYearRes{
+		SourceTaxYear: req.SourceTaxYear,
+		TaxYear:       req.TaxYear,
+	}, nil
+}
@@ -0,0 +1,133 @@
+package taxYear
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTaxYearRepositoryCaseError struct {
+}
+
+func (r *mockTaxYearRepositoryCaseError) GetTaxYears() ([]int, error) {
+	return nil, errors.New("error on query")
+}
+
+func (r *mockTaxYearRepositoryCaseError) CloneTaxYear(sourceTaxYear int, taxYear int) error {
+	return errors.New("error on clone")
+}
+
+// GetTaxYears
+func TestGetTaxYears_ShouldReturnErr_WhenRepositoryError(t *testing.T) {
+	// Arrange
+	usecase := NewTaxYearUsecase(&mockTaxYearRepositoryCaseError{})
+
+	// Act
+	_, err := usecase.GetTaxYears()
+
+	// Assert
+	assert.Error(t, err)
+}
+
+type mockTaxYearRepositoryCaseSuccess struct {
+	sourceTaxYear int
+	taxYear       int
+}
+
+func (r *mockTaxYearRepositoryCaseSuccess) GetTaxYears() ([]int, error) {
+	return []int{2566, 2567}, nil
+}
+
+func (r *mockTaxYearRepositoryCaseSuccess) CloneTaxYear(sourceTaxYear int, taxYear int) error {
+	r.sourceTaxYear = sourceTaxYear
+	r.taxYear = taxYear
+	return nil
+}
+
+func TestGetTaxYears_ShouldReturnTaxYearsRes_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	usecase := NewTaxYearUsecase(&mockTaxYearRepositoryCaseSuccess{})
+
+	// Act
+	result, err := usecase.GetTaxYears()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2566, 2567}, result.TaxYears)
+}
+
+// CloneTaxYear
+func TestCloneTaxYear_ShouldReturnErr_WhenGetTaxYearsError(t *testing.T) {
+	// Arrange
+	usecase := NewTaxYearUsecase(&mockTaxYearRepositoryCaseError{})
+
+	// Act
+	_, err := usecase.CloneTaxYear(CloneTaxYearReq{SourceTaxYear: 2567, TaxYear: 2568})
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestCloneTaxYear_ShouldReturnErr_WhenSourceTaxYearNotFound(t *testing.T) {
+	// Arrange
+	repo := &mockTaxYearRepositoryCaseSuccess{}
+	usecase := NewTaxYearUsecase(repo)
+
+	// Act
+	_, err := usecase.CloneTaxYear(CloneTaxYearReq{SourceTaxYear: 2565, TaxYear: 2568})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrSourceTaxYearNotFound)
+	assert.Equal(t, 0, repo.taxYear)
+}
+
+func TestCloneTaxYear_ShouldReturnErr_WhenTaxYearAlreadyExists(t *testing.T) {
+	// Arrange
+	repo := &mockTaxYearRepositoryCaseSuccess{}
+	usecase := NewTaxYearUsecase(repo)
+
+	// Act
+	_, err := usecase.CloneTaxYear(CloneTaxYearReq{SourceTaxYear: 2567, TaxYear: 2566})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrTaxYearAlreadyExists)
+	assert.Equal(t, 0, repo.taxYear)
+}
+
+type mockTaxYearRepositoryCaseCloneError struct {
+}
+
+func (r *mockTaxYearRepositoryCaseCloneError) GetTaxYears() ([]int, error) {
+	return []int{2567}, nil
+}
+
+func (r *mockTaxYearRepositoryCaseCloneError) CloneTaxYear(sourceTaxYear int, taxYear int) error {
+	return errors.New("error on clone")
+}
+
+func TestCloneTaxYear_ShouldReturnErr_WhenCloneFail(t *testing.T) {
+	// Arrange
+	usecase := NewTaxYearUsecase(&mockTaxYearRepositoryCaseCloneError{})
+
+	// Act
+	_, err := usecase.CloneTaxYear(CloneTaxYearReq{SourceTaxYear: 2567, TaxYear: 2568})
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestCloneTaxYear_ShouldReturnRes_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	repo := &mockTaxYearRepositoryCaseSuccess{}
+	usecase := NewTaxYearUsecase(repo)
+
+	// Act
+	result, err := usecase.CloneTaxYear(CloneTaxYearReq{SourceTaxYear: 2567, TaxYear: 2568})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2567, repo.sourceTaxYear)
+	assert.Equal(t, 2568, repo.taxYear)
+	assert.Equal(t, CloneTaxYearRes{SourceTaxYear: 2567, TaxYear: 2568}, result)
+}
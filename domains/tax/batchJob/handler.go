@@ -0,0 +1,178 @@
+package batchJob
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+)
+
+// defaultResultsLimit is the page size of results when limit is not given
+const defaultResultsLimit = 1000
+
+// defaultMaxFileSize is the largest file a job accepts, the file is streamed so this bounds storage not memory
+const defaultMaxFileSize = 100 << 20
+
+type TaxBatchJobHttpHandler interface {
+	CreateJob(c echo.Context) error
+	GetJob(c echo.Context) error
+	GetJobResults(c echo.Context) error
+	CancelJob(c echo.Context) error
+}
+
+type taxBatchJobHttpHandler struct {
+	taxBatchJobUseCase TaxBatchJobUseCase
+	maxFileSize        int64
+}
+
+func NewTaxBatchJobHttpHandler(taxBatchJobUseCase TaxBatchJobUseCase) TaxBatchJobHttpHandler {
+	return &taxBatchJobHttpHandler{
+		taxBatchJobUseCase: taxBatchJobUseCase,
+		maxFileSize:        defaultMaxFileSize,
+	}
+}
+
+// CreateJob accepts the same form as the csv upload and answers 202 with the job to poll
+func (t *taxBatchJobHttpHandler) CreateJob(c echo.Context) error {
+	file, err := c.FormFile("taxFile")
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	req := CreateTaxBatchJobReq{
+		Mode:     calculator.CSVModeStrict,
+		FileName: file.Filename,
+	}
+
+	if taxYearValue := c.FormValue("taxYear"); taxYearValue != "" {
+		req.TaxYear, err = strconv.Atoi(taxYearValue)
+
+		if err != nil {
+			fmt.Println("Error converting TaxYear:", err)
+			return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+		}
+	}
+
+	if err = echo.QueryParamsBinder(c).String("mode", &req.Mode).BindError(); err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if err = c.Validate(req); err != nil {
+		return err
+	}
+
+	if file.Size > t.maxFileSize {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "File too large")
+	}
+
+	src, err := file.Open()
+
+	if err != nil {
+		fmt.Println("Error opening file:", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	defer src.Close()
+
+	req.File = src
+
+	res, err := t.taxBatchJobUseCase.CreateJob(req)
+
+	// a bad header is reported like the csv upload does
+	var headerErr *calculator.TaxCSVHeaderError
+
+	if errors.As(err, &headerErr) {
+		return echo.NewHTTPError(http.StatusBadRequest, headerErr.RowErr)
+	}
+
+	if errors.Is(err, calculator.ErrUnreadableTaxCSV) {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if errors.Is(err, calculator.ErrTaxYearNotSupported) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Tax year not supported")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, c.Request().URL.Path+"/"+res.ID)
+
+	return c.JSON(http.StatusAccepted, res)
+}
+
+func (t *taxBatchJobHttpHandler) GetJob(c echo.Context) error {
+	res, err := t.taxBatchJobUseCase.GetJob(c.Param("id"))
+
+	if errors.Is(err, ErrJobNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Job not found")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (t *taxBatchJobHttpHandler) GetJobResults(c echo.Context) error {
+	req := TaxBatchJobResultsReq{
+		Limit: defaultResultsLimit,
+	}
+
+	err := echo.QueryParamsBinder(c).
+		Int("afterLine", &req.AfterLine).
+		Int("limit", &req.Limit).
+		BindError()
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if err = c.Validate(req); err != nil {
+		return err
+	}
+
+	res, err := t.taxBatchJobUseCase.GetJobResults(c.Param("id"), req)
+
+	if errors.Is(err, ErrJobNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Job not found")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (t *taxBatchJobHttpHandler) CancelJob(c echo.Context) error {
+	res, err := t.taxBatchJobUseCase.CancelJob(c.Param("id"))
+
+	if errors.Is(err, ErrJobFinished) {
+		return echo.NewHTTPError(http.StatusConflict, "Job already finished")
+	}
+
+	if errors.Is(err, ErrJobNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Job not found")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
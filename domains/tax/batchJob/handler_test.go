@@ -0,0 +1,387 @@
+package batchJob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/constant/jobStatus"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/money"
+	myValidator "github.com/larb26656/assessment-tax/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTaxBatchJobUseCase struct {
+	err         error
+	createdReq  CreateTaxBatchJobReq
+	createdFile string
+	resultsReq  TaxBatchJobResultsReq
+}
+
+func mockJobRes() TaxBatchJobRes {
+	return TaxBatchJobRes{
+		ID:            "0123456789abcdef0123456789abcdef",
+		Status:        jobStatus.Running,
+		TaxYear:       2567,
+		Mode:          calculator.CSVModeStrict,
+		FileName:      "taxes.csv",
+		TotalRows:     4,
+		ProcessedRows: 1,
+		Progress:      money.FromPercent(25),
+		CreatedAt:     time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func (m *mockTaxBatchJobUseCase) CreateJob(req CreateTaxBatchJobReq) (TaxBatchJobRes, error) {
+	// the upload is closed once the handler returns
+	file, _ := io.ReadAll(req.File)
+	req.File = nil
+
+	m.createdReq = req
+	m.createdFile = string(file)
+
+	return mockJobRes(), m.err
+}
+
+func (m *mockTaxBatchJobUseCase) GetJob(id string) (TaxBatchJobRes, error) {
+	return mockJobRes(), m.err
+}
+
+func (m *mockTaxBatchJobUseCase) GetJobResults(id string, req TaxBatchJobResultsReq) (TaxBatchJobResultsRes, error) {
+	m.resultsReq = req
+
+	return TaxBatchJobResultsRes{
+		Status: jobStatus.Running,
+		Taxes: []calculator.TaxCalucalorMultipleDetailRes{
+			{Line: 2, TotalIncome: money.FromBaht(500000), Tax: money.FromBaht(29000)},
+		},
+		Errors:   []calculator.TaxCSVRowErrorRes{},
+		LastLine: 2,
+	}, m.err
+}
+
+func (m *mockTaxBatchJobUseCase) CancelJob(id string) (TaxBatchJobRes, error) {
+	res := mockJobRes()
+	res.Status = jobStatus.Cancelled
+
+	return res, m.err
+}
+
+func (m *mockTaxBatchJobUseCase) StartWorkers(ctx context.Context, workers int) {
+}
+
+func mockCreateJobHttpReq(query string, csvData string, taxYear string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	var buf bytes.Buffer
+	multipartWriter := multipart.NewWriter(&buf)
+
+	filePart, _ := multipartWriter.CreateFormFile("taxFile", "taxes.csv")
+	filePart.Write([]byte(csvData))
+
+	if taxYear != "" {
+		multipartWriter.WriteField("taxYear", taxYear)
+	}
+
+	multipartWriter.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/tax/batch-jobs?"+query, &buf)
+	req.Header.Set(echo.HeaderContentType, multipartWriter.FormDataContentType())
+	rec := httptest.NewRecorder()
+
+	return e.NewContext(req, rec), rec
+}
+
+func mockJobHttpReq(method string, target string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	req := httptest.NewRequest(method, target, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("0123456789abcdef0123456789abcdef")
+
+	return c, rec
+}
+
+// CreateJob
+
+func TestCreateJobHandler_ShouldGetAccepted_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	usecase := &mockTaxBatchJobUseCase{}
+	handler := NewTaxBatchJobHttpHandler(usecase)
+	c, rec := mockCreateJobHttpReq("mode=lenient", "totalIncome,wht,donation\n500000,0,0\n", "2567")
+
+	// Act
+	err := handler.CreateJob(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, "/tax/batch-jobs/0123456789abcdef0123456789abcdef", rec.Header().Get(echo.HeaderLocation))
+	assert.JSONEq(t, `{
+		"id": "0123456789abcdef0123456789abcdef",
+		"status": "running",
+		"taxYear": 2567,
+		"mode": "strict",
+		"fileName": "taxes.csv",
+		"totalRows": 4,
+		"processedRows": 1,
+		"progress": 25,
+		"createdAt": "2024-05-01T10:00:00Z",
+		"startedAt": null,
+		"finishedAt": null
+	}`, rec.Body.String())
+	assert.Equal(t, CreateTaxBatchJobReq{
+		TaxYear:  2567,
+		Mode:     calculator.CSVModeLenient,
+		FileName: "taxes.csv",
+	}, usecase.createdReq)
+	assert.Equal(t, "totalIncome,wht,donation\n500000,0,0\n", usecase.createdFile)
+}
+
+func TestCreateJobHandler_ShouldGetBadRequest_WhenInvalidInput(t *testing.T) {
+	// Arrange
+	handler := NewTaxBatchJobHttpHandler(&mockTaxBatchJobUseCase{})
+
+	testCases := []struct {
+		name    string
+		query   string
+		taxYear string
+	}{
+		{"Test case 1", "", "abc"},
+		{"Test case 2", "", "67"},
+		{"Test case 3", "mode=loose", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := mockCreateJobHttpReq(tc.query, "totalIncome,wht\n500000,0\n", tc.taxYear)
+
+			// Act
+			err := handler.CreateJob(c)
+
+			// Assert
+			he, ok := err.(*echo.HTTPError)
+
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+func TestCreateJobHandler_ShouldGetRequestEntityTooLarge_WhenFileTooLarge(t *testing.T) {
+	// Arrange
+	usecase := &mockTaxBatchJobUseCase{}
+	handler := NewTaxBatchJobHttpHandler(usecase).(*taxBatchJobHttpHandler)
+	handler.maxFileSize = 16
+	c, _ := mockCreateJobHttpReq("", "totalIncome,wht\n500000,0\n", "")
+
+	// Act
+	err := handler.CreateJob(c)
+
+	// Assert
+	he, ok := err.(*echo.HTTPError)
+
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, he.Code)
+	assert.Nil(t, usecase.createdReq.File)
+	assert.Empty(t, usecase.createdFile)
+}
+
+func TestCreateJobHandler_ShouldGetError_WhenUseCaseError(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode int
+		expectedMsg  any
+	}{
+		{"Test case 1", &calculator.TaxCSVHeaderError{RowErr: calculator.TaxCSVRowErrorRes{Line: 1, Column: "bonus", Value: "bonus", Message: "unknown column"}}, http.StatusBadRequest, calculator.TaxCSVRowErrorRes{Line: 1, Column: "bonus", Value: "bonus", Message: "unknown column"}},
+		{"Test case 2", fmt.Errorf("%w: %v", calculator.ErrUnreadableTaxCSV, io.ErrUnexpectedEOF), http.StatusBadRequest, "Bad request"},
+		{"Test case 3", calculator.ErrTaxYearNotSupported, http.StatusBadRequest, "Tax year not supported"},
+		{"Test case 4", errors.New("error on create"), http.StatusInternalServerError, "Something went wrong"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler := NewTaxBatchJobHttpHandler(&mockTaxBatchJobUseCase{err: tc.err})
+			c, _ := mockCreateJobHttpReq("", "totalIncome,wht\n500000,0\n", "")
+
+			// Act
+			err := handler.CreateJob(c)
+
+			// Assert
+			he, ok := err.(*echo.HTTPError)
+
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCode, he.Code)
+			assert.Equal(t, tc.expectedMsg, he.Message)
+		})
+	}
+}
+
+// GetJob
+
+func TestGetJobHandler_ShouldGetJob_WhenJobExists(t *testing.T) {
+	// Arrange
+	handler := NewTaxBatchJobHttpHandler(&mockTaxBatchJobUseCase{})
+	c, rec := mockJobHttpReq(http.MethodGet, "/tax/batch-jobs/0123456789abcdef0123456789abcdef")
+
+	// Act
+	err := handler.GetJob(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"progress":25`)
+}
+
+func TestJobHandlers_ShouldGetError_WhenUseCaseError(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{"Test case 1", ErrJobNotFound, http.StatusNotFound},
+		{"Test case 2", errors.New("error on get"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		handler := NewTaxBatchJobHttpHandler(&mockTaxBatchJobUseCase{err: tc.err})
+
+		for name, handle := range map[string]echo.HandlerFunc{
+			"GetJob":        handler.GetJob,
+			"GetJobResults": handler.GetJobResults,
+			"CancelJob":     handler.CancelJob,
+		} {
+			t.Run(tc.name+" "+name, func(t *testing.T) {
+				c, _ := mockJobHttpReq(http.MethodGet, "/tax/batch-jobs/0123456789abcdef0123456789abcdef")
+
+				// Act
+				err := handle(c)
+
+				// Assert
+				he, ok := err.(*echo.HTTPError)
+
+				assert.True(t, ok)
+				assert.Equal(t, tc.expectedCode, he.Code)
+			})
+		}
+	}
+}
+
+// GetJobResults
+
+func TestGetJobResultsHandler_ShouldGetResults_WhenCorrectInput(t *testing.T) {
+	testCases := []struct {
+		name        string
+		query       string
+		expectedReq TaxBatchJobResultsReq
+	}{
+		{"Test case 1", "", TaxBatchJobResultsReq{AfterLine: 0, Limit: 1000}},
+		{"Test case 2", "?afterLine=100&limit=50", TaxBatchJobResultsReq{AfterLine: 100, Limit: 50}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			usecase := &mockTaxBatchJobUseCase{}
+			handler := NewTaxBatchJobHttpHandler(usecase)
+			c, rec := mockJobHttpReq(http.MethodGet, "/tax/batch-jobs/0123456789abcdef0123456789abcdef/results"+tc.query)
+
+			// Act
+			err := handler.GetJobResults(c)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.expectedReq, usecase.resultsReq)
+			assert.JSONEq(t, `{
+				"status": "running",
+				"taxes": [
+					{"line": 2, "totalIncome": 500000, "tax": 29000, "taxRefund": 0, "effectiveTaxRate": 0, "effectiveNetTaxRate": 0, "marginalTaxRate": 0, "nextBracketDistance": null}
+				],
+				"errors": [],
+				"lastLine": 2,
+				"hasMore": false
+			}`, rec.Body.String())
+		})
+	}
+}
+
+func TestGetJobResultsHandler_ShouldGetBadRequest_WhenInvalidQuery(t *testing.T) {
+	// Arrange
+	handler := NewTaxBatchJobHttpHandler(&mockTaxBatchJobUseCase{})
+
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{"Test case 1", "?afterLine=abc"},
+		{"Test case 2", "?afterLine=-1"},
+		{"Test case 3", "?limit=0"},
+		{"Test case 4", "?limit=10001"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := mockJobHttpReq(http.MethodGet, "/tax/batch-jobs/0123456789abcdef0123456789abcdef/results"+tc.query)
+
+			// Act
+			err := handler.GetJobResults(c)
+
+			// Assert
+			he, ok := err.(*echo.HTTPError)
+
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+// CancelJob
+
+func TestCancelJobHandler_ShouldGetCancelledJob_WhenJobNotFinished(t *testing.T) {
+	// Arrange
+	handler := NewTaxBatchJobHttpHandler(&mockTaxBatchJobUseCase{})
+	c, rec := mockJobHttpReq(http.MethodPost, "/tax/batch-jobs/0123456789abcdef0123456789abcdef/cancel")
+
+	// Act
+	err := handler.CancelJob(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"cancelled"`)
+}
+
+func TestCancelJobHandler_ShouldGetConflict_WhenJobFinished(t *testing.T) {
+	// Arrange
+	handler := NewTaxBatchJobHttpHandler(&mockTaxBatchJobUseCase{err: ErrJobFinished})
+	c, _ := mockJobHttpReq(http.MethodPost, "/tax/batch-jobs/0123456789abcdef0123456789abcdef/cancel")
+
+	// Act
+	err := handler.CancelJob(c)
+
+	// Assert
+	he, ok := err.(*echo.HTTPError)
+
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusConflict, he.Code)
+	assert.Equal(t, "Job already finished", he.Message)
+}
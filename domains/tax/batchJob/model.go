@@ -0,0 +1,74 @@
+package batchJob
+
+import (
+	"io"
+	"time"
+
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/money"
+)
+
+// TaxBatchJob is a tax csv calculated in the background, LastLine is the line of the last row saved
+// and is where a resumed job goes on from. Attempt counts the claims of the job, only the worker
+// holding the latest claim can save the job.
+type TaxBatchJob struct {
+	ID            string
+	Status        string
+	TaxYear       int
+	Mode          string
+	FileName      string
+	TotalRows     int
+	ProcessedRows int
+	LastLine      int
+	Attempt       int
+	ErrorMessage  *string
+	CreatedAt     time.Time
+	StartedAt     *time.Time
+	FinishedAt    *time.Time
+}
+
+// TaxBatchJobResult is the result of a row, either Result or Error is set
+type TaxBatchJobResult struct {
+	Line   int
+	Result *calculator.TaxCalucalorMultipleDetailRes
+	Error  *calculator.TaxCSVRowErrorRes
+}
+
+type CreateTaxBatchJobReq struct {
+	TaxYear  int    `validate:"omitempty,gte=2500"`
+	Mode     string `validate:"required,oneof=strict lenient"`
+	FileName string
+	File     io.ReadSeeker
+}
+
+type TaxBatchJobResultsReq struct {
+	AfterLine int `query:"afterLine" validate:"gte=0"`
+	Limit     int `query:"limit" validate:"gte=1,lte=10000"`
+}
+
+// TaxBatchJobRes is the status of a job, Progress is the percentage of rows processed
+type TaxBatchJobRes struct {
+	ID            string     `json:"id"`
+	Status        string     `json:"status"`
+	TaxYear       int        `json:"taxYear"`
+	Mode          string     `json:"mode"`
+	FileName      string     `json:"fileName"`
+	TotalRows     int        `json:"totalRows"`
+	ProcessedRows int        `json:"processedRows"`
+	Progress      money.Rate `json:"progress"`
+	Error         string     `json:"error,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	StartedAt     *time.Time `json:"startedAt"`
+	FinishedAt    *time.Time `json:"finishedAt"`
+}
+
+// TaxBatchJobResultsRes is a page of results after a line, results are there as soon as they are
+// saved so a running job can be read while it goes. The next page starts after LastLine and there
+// may be one while HasMore is true or the job is not finished.
+type TaxBatchJobResultsRes struct {
+	Status   string                                     `json:"status"`
+	Taxes    []calculator.TaxCalucalorMultipleDetailRes `json:"taxes"`
+	Errors   []calculator.TaxCSVRowErrorRes             `json:"errors"`
+	LastLine int                                        `json:"lastLine"`
+	HasMore  bool                                       `json:"hasMore"`
+}
@@ -0,0 +1,370 @@
+package batchJob
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/larb26656/assessment-tax/constant/jobStatus"
+)
+
+var ErrJobNotFound = errors.New("job not found")
+
+// ErrJobNotRunning is returned when a worker saves a job that was cancelled or claimed by another worker
+var ErrJobNotRunning = errors.New("job not running")
+
+type TaxBatchJobRepository interface {
+	CreateJob(job TaxBatchJob, file io.Reader) error
+	GetJob(id string) (TaxBatchJob, error)
+	OpenJobFile(id string) io.Reader
+	ClaimJob(lease time.Duration) (TaxBatchJob, bool, error)
+	SaveProgress(id string, attempt int, results []TaxBatchJobResult, processedRows int, lastLine int) error
+	FinishJob(id string, attempt int, status string, errorMessage *string) error
+	CancelJob(id string) (bool, error)
+	GetResults(id string, afterLine int, limit int) ([]TaxBatchJobResult, error)
+}
+
+type taxBatchJobRepository struct {
+	db *sql.DB
+}
+
+func NewTaxBatchJobRepository(db *sql.DB) TaxBatchJobRepository {
+	return &taxBatchJobRepository{
+		db: db,
+	}
+}
+
+// jobFileChunkSize is the size of every stored piece of a job file, the file is never held in memory whole
+const jobFileChunkSize = 1 << 20
+
+const taxBatchJobColumns = `id, status, tax_year, mode, file_name, total_rows, processed_rows, last_line, attempt, error_message, created_at, started_at, finished_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanJob(row rowScanner) (TaxBatchJob, error) {
+	var job TaxBatchJob
+
+	err := row.Scan(&job.ID, &job.Status, &job.TaxYear, &job.Mode, &job.FileName, &job.TotalRows, &job.ProcessedRows, &job.LastLine, &job.Attempt, &job.ErrorMessage, &job.CreatedAt, &job.StartedAt, &job.FinishedAt)
+
+	return job, err
+}
+
+// CreateJob saves the job with its file in one transaction, the file is streamed in chunks of jobFileChunkSize
+func (r *taxBatchJobRepository) CreateJob(job TaxBatchJob, file io.Reader) error {
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	// rollback is no-op after commit
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO tax_batch_job (id, status, tax_year, mode, file_name, total_rows) VALUES ($1, $2, $3, $4, $5, $6)`, job.ID, job.Status, job.TaxYear, job.Mode, job.FileName, job.TotalRows)
+
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO tax_batch_job_file (job_id, chunk, data) VALUES ($1, $2, $3)`)
+
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	data := make([]byte, jobFileChunkSize)
+
+	for chunk := 0; ; chunk++ {
+		n, err := io.ReadFull(file, data)
+
+		if n > 0 {
+			if _, err := stmt.Exec(job.ID, chunk, data[:n]); err != nil {
+				return err
+			}
+		}
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *taxBatchJobRepository) GetJob(id string) (TaxBatchJob, error) {
+	stmt, err := r.db.Prepare(`SELECT ` + taxBatchJobColumns + ` FROM tax_batch_job WHERE id = $1`)
+
+	if err != nil {
+		return TaxBatchJob{}, err
+	}
+
+	defer stmt.Close()
+
+	job, err := scanJob(stmt.QueryRow(id))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return TaxBatchJob{}, ErrJobNotFound
+	}
+
+	if err != nil {
+		return TaxBatchJob{}, err
+	}
+
+	return job, nil
+}
+
+// OpenJobFile reads the file of a job as a stream, one chunk is loaded at a time
+func (r *taxBatchJobRepository) OpenJobFile(id string) io.Reader {
+	return &jobFileReader{
+		db: r.db,
+		id: id,
+	}
+}
+
+// jobFileReader loads the next chunk of a job file once the one before is read
+type jobFileReader struct {
+	db    *sql.DB
+	id    string
+	chunk int
+	data  []byte
+	done  bool
+}
+
+func (r *jobFileReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		err := r.db.QueryRow(`SELECT data FROM tax_batch_job_file WHERE job_id = $1 AND chunk = $2`, r.id, r.chunk).Scan(&r.data)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			r.done = true
+			continue
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		r.chunk++
+	}
+
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+
+	return n, nil
+}
+
+// ClaimJob marks the oldest pending job as running, a running job not saved within lease is claimed
+// again so the job of a stopped worker resumes. Locked jobs are skipped so workers never share a job,
+// and every claim bumps the attempt so a worker that lost its claim can no longer save the job.
+func (r *taxBatchJobRepository) ClaimJob(lease time.Duration) (TaxBatchJob, bool, error) {
+	stmt, err := r.db.Prepare(`UPDATE tax_batch_job SET status = $1, attempt = attempt + 1, started_at = COALESCE(started_at, now()), updated_at = now() ` +
+		`WHERE id = (SELECT id FROM tax_batch_job WHERE status = $2 OR (status = $1 AND updated_at < now() - make_interval(secs => $3)) ` +
+		`ORDER BY created_at LIMIT 1 FOR UPDATE SKIP LOCKED) ` +
+		`RETURNING ` + taxBatchJobColumns)
+
+	if err != nil {
+		return TaxBatchJob{}, false, err
+	}
+
+	defer stmt.Close()
+
+	job, err := scanJob(stmt.QueryRow(jobStatus.Running, jobStatus.Pending, lease.Seconds()))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return TaxBatchJob{}, false, nil
+	}
+
+	if err != nil {
+		return TaxBatchJob{}, false, err
+	}
+
+	return job, true, nil
+}
+
+// SaveProgress saves the results with the progress they reach in one transaction, so a resumed job
+// goes on from the last saved row. It also renews the lease of the job.
+func (r *taxBatchJobRepository) SaveProgress(id string, attempt int, results []TaxBatchJobResult, processedRows int, lastLine int) error {
+	tx, err := r.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	// rollback is no-op after commit
+	defer tx.Rollback()
+
+	updated, err := tx.Exec(`UPDATE tax_batch_job SET processed_rows = $2, last_line = $3, updated_at = now() WHERE id = $1 AND status = $4 AND attempt = $5`, id, processedRows, lastLine, jobStatus.Running, attempt)
+
+	if err != nil {
+		return err
+	}
+
+	count, err := updated.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return ErrJobNotRunning
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO tax_batch_job_result (job_id, line, result, error) VALUES ($1, $2, $3, $4) ON CONFLICT (job_id, line) DO NOTHING`)
+
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	for _, result := range results {
+		resultJSON, err := marshalNullable(result.Result)
+
+		if err != nil {
+			return err
+		}
+
+		errorJSON, err := marshalNullable(result.Error)
+
+		if err != nil {
+			return err
+		}
+
+		if _, err = stmt.Exec(id, result.Line, resultJSON, errorJSON); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// FinishJob ends a running job, it returns ErrJobNotRunning when the job was cancelled or claimed again meanwhile
+func (r *taxBatchJobRepository) FinishJob(id string, attempt int, status string, errorMessage *string) error {
+	stmt, err := r.db.Prepare(`UPDATE tax_batch_job SET status = $2, error_message = $3, finished_at = now(), updated_at = now() WHERE id = $1 AND status = $4 AND attempt = $5`)
+
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	updated, err := stmt.Exec(id, status, errorMessage, jobStatus.Running, attempt)
+
+	if err != nil {
+		return err
+	}
+
+	count, err := updated.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return ErrJobNotRunning
+	}
+
+	return nil
+}
+
+// CancelJob cancels a pending or running job, it returns false when there is no such job to cancel
+func (r *taxBatchJobRepository) CancelJob(id string) (bool, error) {
+	stmt, err := r.db.Prepare(`UPDATE tax_batch_job SET status = $2, finished_at = now(), updated_at = now() WHERE id = $1 AND status IN ($3, $4)`)
+
+	if err != nil {
+		return false, err
+	}
+
+	defer stmt.Close()
+
+	updated, err := stmt.Exec(id, jobStatus.Cancelled, jobStatus.Pending, jobStatus.Running)
+
+	if err != nil {
+		return false, err
+	}
+
+	count, err := updated.RowsAffected()
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *taxBatchJobRepository) GetResults(id string, afterLine int, limit int) ([]TaxBatchJobResult, error) {
+	stmt, err := r.db.Prepare(`SELECT line, result, error FROM tax_batch_job_result WHERE job_id = $1 AND line > $2 ORDER BY line LIMIT $3`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer stmt.Close()
+
+	rows, err := stmt.Query(id, afterLine, limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	results := []TaxBatchJobResult{}
+
+	for rows.Next() {
+		var result TaxBatchJobResult
+		var resultJSON, errorJSON []byte
+
+		if err = rows.Scan(&result.Line, &resultJSON, &errorJSON); err != nil {
+			return nil, err
+		}
+
+		if resultJSON != nil {
+			if err = json.Unmarshal(resultJSON, &result.Result); err != nil {
+				return nil, err
+			}
+		}
+
+		if errorJSON != nil {
+			if err = json.Unmarshal(errorJSON, &result.Error); err != nil {
+				return nil, err
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// marshalNullable returns the json of value as text or nil for a NULL column
+func marshalNullable[T any](value *T) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
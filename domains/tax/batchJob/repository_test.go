@@ -0,0 +1,364 @@
+package batchJob
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/larb26656/assessment-tax/constant/jobStatus"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockJobRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "status", "tax_year", "mode", "file_name", "total_rows", "processed_rows", "last_line", "attempt", "error_message", "created_at", "started_at", "finished_at"})
+}
+
+// GetJob
+
+func TestGetJob_ShouldReturnErrJobNotFound_WhenNoRows(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBatchJobRepository(db)
+	mock.ExpectPrepare(`SELECT id, status, (.+) FROM tax_batch_job WHERE id = \$1`).ExpectQuery().WithArgs("job-1").
+		WillReturnRows(mockJobRows())
+
+	// Act
+	_, err = repo.GetJob("job-1")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestGetJob_ShouldReturnJob_WhenJobExists(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	message := "row 3 is invalid"
+
+	repo := NewTaxBatchJobRepository(db)
+	mock.ExpectPrepare(`SELECT id, status, (.+) FROM tax_batch_job WHERE id = \$1`).ExpectQuery().WithArgs("job-1").
+		WillReturnRows(mockJobRows().AddRow("job-1", jobStatus.Failed, 2567, "strict", "taxes.csv", 4, 0, 0, 1, message, createdAt, createdAt, nil))
+
+	// Act
+	job, err := repo.GetJob("job-1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, TaxBatchJob{
+		ID:           "job-1",
+		Status:       jobStatus.Failed,
+		TaxYear:      2567,
+		Mode:         "strict",
+		FileName:     "taxes.csv",
+		TotalRows:    4,
+		Attempt:      1,
+		ErrorMessage: &message,
+		CreatedAt:    createdAt,
+		StartedAt:    &createdAt,
+	}, job)
+}
+
+// CreateJob
+
+func TestCreateJob_ShouldSaveFileInChunks_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	file := strings.Repeat("a", jobFileChunkSize) + "totalIncome"
+
+	repo := NewTaxBatchJobRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO tax_batch_job \(id, status, tax_year, mode, file_name, total_rows\)`).
+		WithArgs("job-1", jobStatus.Pending, 2567, "strict", "taxes.csv", 4).WillReturnResult(sqlmock.NewResult(0, 1))
+	insert := mock.ExpectPrepare(`INSERT INTO tax_batch_job_file`)
+	insert.ExpectExec().WithArgs("job-1", 0, []byte(file[:jobFileChunkSize])).WillReturnResult(sqlmock.NewResult(0, 1))
+	insert.ExpectExec().WithArgs("job-1", 1, []byte("totalIncome")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err = repo.CreateJob(TaxBatchJob{ID: "job-1", Status: jobStatus.Pending, TaxYear: 2567, Mode: "strict", FileName: "taxes.csv", TotalRows: 4}, strings.NewReader(file))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateJob_ShouldRollback_WhenErrorOnSaveFile(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBatchJobRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO tax_batch_job `).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(`INSERT INTO tax_batch_job_file`).ExpectExec().WillReturnError(errors.New("error on insert"))
+	mock.ExpectRollback()
+
+	// Act
+	err = repo.CreateJob(TaxBatchJob{ID: "job-1"}, strings.NewReader("totalIncome,wht\n"))
+
+	// Assert
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// OpenJobFile
+
+func TestOpenJobFile_ShouldReadEveryChunk_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBatchJobRepository(db)
+	query := `SELECT data FROM tax_batch_job_file WHERE job_id = \$1 AND chunk = \$2`
+	mock.ExpectQuery(query).WithArgs("job-1", 0).WillReturnRows(sqlmock.NewRows([]string{"data"}).AddRow([]byte("totalIncome,wht\n")))
+	mock.ExpectQuery(query).WithArgs("job-1", 1).WillReturnRows(sqlmock.NewRows([]string{"data"}).AddRow([]byte("500000,0\n")))
+	mock.ExpectQuery(query).WithArgs("job-1", 2).WillReturnRows(sqlmock.NewRows([]string{"data"}))
+
+	// Act
+	file, err := io.ReadAll(repo.OpenJobFile("job-1"))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "totalIncome,wht\n500000,0\n", string(file))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOpenJobFile_ShouldReturnError_WhenErrorOnQuery(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBatchJobRepository(db)
+	mock.ExpectQuery(`SELECT data FROM tax_batch_job_file`).WillReturnError(errors.New("error on query"))
+
+	// Act
+	_, err = io.ReadAll(repo.OpenJobFile("job-1"))
+
+	// Assert
+	assert.Error(t, err)
+}
+
+// ClaimJob
+
+func TestClaimJob_ShouldReturnNotFound_WhenNoJobToClaim(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBatchJobRepository(db)
+	mock.ExpectPrepare(`UPDATE tax_batch_job SET status = \$1, attempt = attempt \+ 1(.+)FOR UPDATE SKIP LOCKED\) RETURNING`).ExpectQuery().
+		WithArgs(jobStatus.Running, jobStatus.Pending, float64(60)).
+		WillReturnRows(mockJobRows())
+
+	// Act
+	_, found, err := repo.ClaimJob(time.Minute)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+// SaveProgress
+
+func TestSaveProgress_ShouldReturnErrJobNotRunning_WhenJobCancelled(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBatchJobRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE tax_batch_job SET processed_rows`).WithArgs("job-1", 1, 2, jobStatus.Running, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	// Act
+	err = repo.SaveProgress("job-1", 1, []TaxBatchJobResult{{Line: 2, Error: &calculator.TaxCSVRowErrorRes{Line: 2}}}, 1, 2)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrJobNotRunning)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveProgress_ShouldRollback_WhenErrorOnInsertResult(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBatchJobRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE tax_batch_job SET processed_rows`).WithArgs("job-1", 1, 2, jobStatus.Running, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(`INSERT INTO tax_batch_job_result`).ExpectExec().WillReturnError(errors.New("error on insert"))
+	mock.ExpectRollback()
+
+	// Act
+	err = repo.SaveProgress("job-1", 1, []TaxBatchJobResult{{Line: 2, Result: &calculator.TaxCalucalorMultipleDetailRes{Line: 2}}}, 1, 2)
+
+	// Assert
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveProgress_ShouldCommit_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBatchJobRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE tax_batch_job SET processed_rows`).WithArgs("job-1", 2, 3, jobStatus.Running, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	insert := mock.ExpectPrepare(`INSERT INTO tax_batch_job_result`)
+	insert.ExpectExec().WithArgs("job-1", 2, `{"line":2,"totalIncome":500000.00,"tax":29000.00,"taxRefund":0.00,"effectiveTaxRate":0.00,"effectiveNetTaxRate":0.00,"marginalTaxRate":0.00,"nextBracketDistance":null}`, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	insert.ExpectExec().WithArgs("job-1", 3, nil, `{"line":3,"column":"wht","value":"b","message":"invalid amount: \"b\""}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Act
+	err = repo.SaveProgress("job-1", 1, []TaxBatchJobResult{
+		{Line: 2, Result: &calculator.TaxCalucalorMultipleDetailRes{Line: 2, TotalIncome: money.FromBaht(500000), Tax: money.FromBaht(29000)}},
+		{Line: 3, Error: &calculator.TaxCSVRowErrorRes{Line: 3, Column: "wht", Value: "b", Message: `invalid amount: "b"`}},
+	}, 2, 3)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// FinishJob
+
+func TestFinishJob_ShouldReturnErrJobNotRunning_WhenJobClaimedAgain(t *testing.T) {
+	testCases := []struct {
+		name     string
+		affected int64
+		expected error
+	}{
+		{"Test case 1", 1, nil},
+		{"Test case 2", 0, ErrJobNotRunning},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			db, mock, err := sqlmock.New()
+
+			if err != nil {
+				t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+			}
+
+			repo := NewTaxBatchJobRepository(db)
+			mock.ExpectPrepare(`UPDATE tax_batch_job SET status = \$2(.+)AND attempt = \$5`).ExpectExec().
+				WithArgs("job-1", jobStatus.Completed, nil, jobStatus.Running, 2).
+				WillReturnResult(sqlmock.NewResult(0, tc.affected))
+
+			// Act
+			err = repo.FinishJob("job-1", 2, jobStatus.Completed, nil)
+
+			// Assert
+			assert.Equal(t, tc.expected, err)
+		})
+	}
+}
+
+// CancelJob
+
+func TestCancelJob_ShouldReturnCancelled_WhenJobUpdated(t *testing.T) {
+	testCases := []struct {
+		name     string
+		affected int64
+		expected bool
+	}{
+		{"Test case 1", 1, true},
+		{"Test case 2", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			db, mock, err := sqlmock.New()
+
+			if err != nil {
+				t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+			}
+
+			repo := NewTaxBatchJobRepository(db)
+			mock.ExpectPrepare(`UPDATE tax_batch_job SET status = \$2`).ExpectExec().
+				WithArgs("job-1", jobStatus.Cancelled, jobStatus.Pending, jobStatus.Running).
+				WillReturnResult(sqlmock.NewResult(0, tc.affected))
+
+			// Act
+			cancelled, err := repo.CancelJob("job-1")
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, cancelled)
+		})
+	}
+}
+
+// GetResults
+
+func TestGetResults_ShouldReturnResultsAndErrors_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewTaxBatchJobRepository(db)
+	rows := sqlmock.NewRows([]string{"line", "result", "error"}).
+		AddRow(2, []byte(`{"line":2,"totalIncome":500000,"tax":29000}`), nil).
+		AddRow(3, nil, []byte(`{"line":3,"column":"wht","value":"b","message":"invalid amount"}`))
+	mock.ExpectPrepare(`SELECT line, result, error FROM tax_batch_job_result`).ExpectQuery().WithArgs("job-1", 1, 10).
+		WillReturnRows(rows)
+
+	// Act
+	results, err := repo.GetResults("job-1", 1, 10)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []TaxBatchJobResult{
+		{Line: 2, Result: &calculator.TaxCalucalorMultipleDetailRes{Line: 2, TotalIncome: money.FromBaht(500000), Tax: money.FromBaht(29000)}},
+		{Line: 3, Error: &calculator.TaxCSVRowErrorRes{Line: 3, Column: "wht", Value: "b", Message: "invalid amount"}},
+	}, results)
+}
@@ -0,0 +1,432 @@
+package batchJob
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/larb26656/assessment-tax/constant/jobStatus"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/money"
+)
+
+var ErrJobFinished = errors.New("job already finished")
+
+// DefaultWorkers is the number of jobs processed at the same time by a server
+const DefaultWorkers = 2
+
+const (
+	// jobLease is how long a running job may go without saving before another worker resumes it
+	jobLease = time.Minute
+	// jobLeaseRenewal is how long a worker goes at most without saving, well within jobLease
+	jobLeaseRenewal = jobLease / 4
+	// jobPollInterval is how often an idle worker looks for a job it was not woken for
+	jobPollInterval = 2 * time.Second
+	// jobChunkSize is the number of rows saved together with the progress they reach
+	jobChunkSize = 100
+)
+
+type TaxBatchJobUseCase interface {
+	CreateJob(req CreateTaxBatchJobReq) (TaxBatchJobRes, error)
+	GetJob(id string) (TaxBatchJobRes, error)
+	GetJobResults(id string, req TaxBatchJobResultsReq) (TaxBatchJobResultsRes, error)
+	CancelJob(id string) (TaxBatchJobRes, error)
+	StartWorkers(ctx context.Context, workers int)
+}
+
+type taxBatchJobUseCase struct {
+	taxBatchJobRepository TaxBatchJobRepository
+	taxCalculatorUseCase  calculator.TaxCalculatorUseCase
+	validate              func(i interface{}) error
+	wake                  chan struct{}
+	now                   func() time.Time
+}
+
+// NewTaxBatchJobUseCase creates the use case, validate checks every row the same way a request is checked
+func NewTaxBatchJobUseCase(taxBatchJobRepository TaxBatchJobRepository, taxCalculatorUseCase calculator.TaxCalculatorUseCase, validate func(i interface{}) error) TaxBatchJobUseCase {
+	return &taxBatchJobUseCase{
+		taxBatchJobRepository: taxBatchJobRepository,
+		taxCalculatorUseCase:  taxCalculatorUseCase,
+		validate:              validate,
+		wake:                  make(chan struct{}, 1),
+		now:                   time.Now,
+	}
+}
+
+func newJobID() (string, error) {
+	id := make([]byte, 16)
+
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+func toJobRes(job TaxBatchJob) TaxBatchJobRes {
+	res := TaxBatchJobRes{
+		ID:            job.ID,
+		Status:        job.Status,
+		TaxYear:       job.TaxYear,
+		Mode:          job.Mode,
+		FileName:      job.FileName,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		CreatedAt:     job.CreatedAt,
+		StartedAt:     job.StartedAt,
+		FinishedAt:    job.FinishedAt,
+	}
+
+	if job.TotalRows > 0 {
+		res.Progress = money.Rate(int64(job.ProcessedRows) * int64(money.FromPercent(100)) / int64(job.TotalRows))
+	} else if job.Status == jobStatus.Completed {
+		res.Progress = money.FromPercent(100)
+	}
+
+	if job.ErrorMessage != nil {
+		res.Error = *job.ErrorMessage
+	}
+
+	return res
+}
+
+// CreateJob saves the file as a pending job and wakes a worker. The header is checked and the rows are
+// counted here so a bad file fails at once, the rows themselves are checked by the worker. The file is
+// read as a stream, once to count and once to save.
+func (t *taxBatchJobUseCase) CreateJob(req CreateTaxBatchJobReq) (TaxBatchJobRes, error) {
+	totalRows := 0
+
+	countRow := func(line int, row []string, req calculator.TaxCalculatorReq) error {
+		totalRows++
+		return nil
+	}
+
+	countRowError := func(row []string, rowErr calculator.TaxCSVRowErrorRes) error {
+		totalRows++
+		return nil
+	}
+
+	skipValidate := func(i interface{}) error {
+		return nil
+	}
+
+	err := calculator.ReadTaxCSV(req.File, req.TaxYear, skipValidate, countRow, countRowError)
+
+	if err != nil {
+		return TaxBatchJobRes{}, err
+	}
+
+	if _, err = req.File.Seek(0, io.SeekStart); err != nil {
+		return TaxBatchJobRes{}, err
+	}
+
+	year := taxYear.Resolve(req.TaxYear)
+
+	if _, err = t.taxCalculatorUseCase.GetTaxSetting(year); err != nil {
+		return TaxBatchJobRes{}, err
+	}
+
+	id, err := newJobID()
+
+	if err != nil {
+		return TaxBatchJobRes{}, err
+	}
+
+	job := TaxBatchJob{
+		ID:        id,
+		Status:    jobStatus.Pending,
+		TaxYear:   year,
+		Mode:      req.Mode,
+		FileName:  req.FileName,
+		TotalRows: totalRows,
+	}
+
+	if err = t.taxBatchJobRepository.CreateJob(job, req.File); err != nil {
+		return TaxBatchJobRes{}, err
+	}
+
+	// a worker already woken will pick the job up anyway
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+
+	return t.GetJob(id)
+}
+
+func (t *taxBatchJobUseCase) GetJob(id string) (TaxBatchJobRes, error) {
+	job, err := t.taxBatchJobRepository.GetJob(id)
+
+	if err != nil {
+		return TaxBatchJobRes{}, err
+	}
+
+	return toJobRes(job), nil
+}
+
+func (t *taxBatchJobUseCase) GetJobResults(id string, req TaxBatchJobResultsReq) (TaxBatchJobResultsRes, error) {
+	job, err := t.taxBatchJobRepository.GetJob(id)
+
+	if err != nil {
+		return TaxBatchJobResultsRes{}, err
+	}
+
+	// one more result tells if there is a next page
+	results, err := t.taxBatchJobRepository.GetResults(id, req.AfterLine, req.Limit+1)
+
+	if err != nil {
+		return TaxBatchJobResultsRes{}, err
+	}
+
+	res := TaxBatchJobResultsRes{
+		Status:   job.Status,
+		Taxes:    []calculator.TaxCalucalorMultipleDetailRes{},
+		Errors:   []calculator.TaxCSVRowErrorRes{},
+		LastLine: req.AfterLine,
+		HasMore:  len(results) > req.Limit,
+	}
+
+	if res.HasMore {
+		results = results[:req.Limit]
+	}
+
+	for _, result := range results {
+		if result.Result != nil {
+			res.Taxes = append(res.Taxes, *result.Result)
+		}
+
+		if result.Error != nil {
+			res.Errors = append(res.Errors, *result.Error)
+		}
+
+		res.LastLine = result.Line
+	}
+
+	return res, nil
+}
+
+// CancelJob stops a pending or running job, the results saved before are kept
+func (t *taxBatchJobUseCase) CancelJob(id string) (TaxBatchJobRes, error) {
+	cancelled, err := t.taxBatchJobRepository.CancelJob(id)
+
+	if err != nil {
+		return TaxBatchJobRes{}, err
+	}
+
+	if !cancelled {
+		if _, err = t.taxBatchJobRepository.GetJob(id); err != nil {
+			return TaxBatchJobRes{}, err
+		}
+
+		return TaxBatchJobRes{}, ErrJobFinished
+	}
+
+	return t.GetJob(id)
+}
+
+// StartWorkers processes jobs in the background until ctx is done. Jobs left running by a stopped
+// process are resumed once their lease runs out.
+func (t *taxBatchJobUseCase) StartWorkers(ctx context.Context, workers int) {
+	for i := 0; i < workers; i++ {
+		go t.work(ctx)
+	}
+}
+
+func (t *taxBatchJobUseCase) work(ctx context.Context) {
+	for {
+		processed, err := t.processNextJob(ctx)
+
+		if err != nil {
+			fmt.Println("Error processing tax batch job:", err)
+		}
+
+		// keep going while there are jobs, otherwise wait for a new one
+		if processed && err == nil && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.wake:
+		case <-time.After(jobPollInterval):
+		}
+	}
+}
+
+// processNextJob claims a job and processes it, it returns false when there was no job
+func (t *taxBatchJobUseCase) processNextJob(ctx context.Context) (bool, error) {
+	job, found, err := t.taxBatchJobRepository.ClaimJob(jobLease)
+
+	if err != nil || !found {
+		return false, err
+	}
+
+	err = t.processJob(ctx, job)
+
+	// a cancelled job is already finished, a stopped one is resumed after a restart
+	if errors.Is(err, ErrJobNotRunning) || errors.Is(err, context.Canceled) {
+		return true, nil
+	}
+
+	if err != nil {
+		fmt.Println("Error on tax batch job:", job.ID, err)
+
+		message := "Something went wrong"
+
+		if errors.Is(err, calculator.ErrTaxYearNotSupported) {
+			message = "Tax year not supported"
+		}
+
+		return true, t.failJob(job, message)
+	}
+
+	return true, nil
+}
+
+func (t *taxBatchJobUseCase) failJob(job TaxBatchJob, message string) error {
+	err := t.taxBatchJobRepository.FinishJob(job.ID, job.Attempt, jobStatus.Failed, &message)
+
+	if errors.Is(err, ErrJobNotRunning) {
+		return nil
+	}
+
+	return err
+}
+
+// rowError stops reading the file at a bad row in strict mode
+type rowError struct {
+	rowErr calculator.TaxCSVRowErrorRes
+}
+
+func (e *rowError) Error() string {
+	return fmt.Sprintf("row %d is invalid", e.rowErr.Line)
+}
+
+// processJob calculates the rows after the last saved line and saves them a chunk at a time, or sooner
+// when the lease is due. In strict mode a job that has not started yet is checked first, renewing its
+// lease as it goes, and fails with the first bad row as its result.
+func (t *taxBatchJobUseCase) processJob(ctx context.Context, job TaxBatchJob) error {
+	setting, err := t.taxCalculatorUseCase.GetTaxSetting(job.TaxYear)
+
+	if err != nil {
+		return err
+	}
+
+	// saving renews the lease, a long file is saved before the lease runs out even between chunks
+	savedAt := t.now()
+
+	leaseDue := func() bool {
+		return t.now().Sub(savedAt) >= jobLeaseRenewal
+	}
+
+	renewLease := func(line int, row []string, req calculator.TaxCalculatorReq) error {
+		if !leaseDue() {
+			return nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		savedAt = t.now()
+
+		return t.taxBatchJobRepository.SaveProgress(job.ID, job.Attempt, nil, 0, 0)
+	}
+
+	if job.Mode == calculator.CSVModeStrict && job.LastLine == 0 {
+		err = calculator.ReadTaxCSV(t.taxBatchJobRepository.OpenJobFile(job.ID), job.TaxYear, t.validate, renewLease, func(row []string, rowErr calculator.TaxCSVRowErrorRes) error {
+			return &rowError{rowErr: rowErr}
+		})
+
+		var badRow *rowError
+
+		if errors.As(err, &badRow) {
+			if err = t.taxBatchJobRepository.SaveProgress(job.ID, job.Attempt, []TaxBatchJobResult{{Line: badRow.rowErr.Line, Error: &badRow.rowErr}}, 0, 0); err != nil {
+				return err
+			}
+
+			return t.failJob(job, badRow.Error())
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	processedRows := job.ProcessedRows
+	lastLine := job.LastLine
+	chunk := make([]TaxBatchJobResult, 0, jobChunkSize)
+
+	save := func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := t.taxBatchJobRepository.SaveProgress(job.ID, job.Attempt, chunk, processedRows, lastLine); err != nil {
+			return err
+		}
+
+		savedAt = t.now()
+		chunk = chunk[:0]
+
+		return nil
+	}
+
+	add := func(result TaxBatchJobResult) error {
+		processedRows++
+		lastLine = result.Line
+		chunk = append(chunk, result)
+
+		if len(chunk) < jobChunkSize && !leaseDue() {
+			return nil
+		}
+
+		return save()
+	}
+
+	err = calculator.ReadTaxCSV(t.taxBatchJobRepository.OpenJobFile(job.ID), job.TaxYear, t.validate, func(line int, row []string, req calculator.TaxCalculatorReq) error {
+		if line <= job.LastLine {
+			return nil
+		}
+
+		res, err := t.taxCalculatorUseCase.CalculateWithSetting(req, setting)
+
+		// a row too large to calculate is reported like a bad row so the rest of the file is still calculated
+		if errors.Is(err, money.ErrOverflow) {
+			return add(TaxBatchJobResult{Line: line, Error: &calculator.TaxCSVRowErrorRes{Line: line, Message: "amount too large"}})
+		}
+
+		if err != nil {
+			return err
+		}
+
+		detail := calculator.ToMultipleDetailRes(req, res)
+		detail.Line = line
+
+		return add(TaxBatchJobResult{Line: line, Result: &detail})
+	}, func(row []string, rowErr calculator.TaxCSVRowErrorRes) error {
+		if rowErr.Line <= job.LastLine {
+			return nil
+		}
+
+		return add(TaxBatchJobResult{Line: rowErr.Line, Error: &rowErr})
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(chunk) > 0 {
+		if err = save(); err != nil {
+			return err
+		}
+	}
+
+	return t.taxBatchJobRepository.FinishJob(job.ID, job.Attempt, jobStatus.Completed, nil)
+}
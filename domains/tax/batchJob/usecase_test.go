@@ -0,0 +1,675 @@
+package batchJob
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/larb26656/assessment-tax/constant/jobStatus"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/mock"
+	"github.com/larb26656/assessment-tax/money"
+	myValidator "github.com/larb26656/assessment-tax/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockTaxBatchJobRepository keeps jobs in memory, onSave runs before every save of progress
+type mockTaxBatchJobRepository struct {
+	mu      sync.Mutex
+	jobs    map[string]*TaxBatchJob
+	files   map[string][]byte
+	results map[string]map[int]TaxBatchJobResult
+	saves   int
+	onSave  func(m *mockTaxBatchJobRepository, id string)
+}
+
+func newMockTaxBatchJobRepository() *mockTaxBatchJobRepository {
+	return &mockTaxBatchJobRepository{
+		jobs:    map[string]*TaxBatchJob{},
+		files:   map[string][]byte{},
+		results: map[string]map[int]TaxBatchJobResult{},
+	}
+}
+
+func (m *mockTaxBatchJobRepository) addJob(job TaxBatchJob, file string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.jobs[job.ID] = &job
+	m.files[job.ID] = []byte(file)
+	m.results[job.ID] = map[int]TaxBatchJobResult{}
+}
+
+func (m *mockTaxBatchJobRepository) job(id string) TaxBatchJob {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return *m.jobs[id]
+}
+
+func (m *mockTaxBatchJobRepository) CreateJob(job TaxBatchJob, file io.Reader) error {
+	data, err := io.ReadAll(file)
+
+	if err != nil {
+		return err
+	}
+
+	job.CreatedAt = time.Now()
+	m.addJob(job, string(data))
+
+	return nil
+}
+
+func (m *mockTaxBatchJobRepository) GetJob(id string) (TaxBatchJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+
+	if !ok {
+		return TaxBatchJob{}, ErrJobNotFound
+	}
+
+	return *job, nil
+}
+
+func (m *mockTaxBatchJobRepository) OpenJobFile(id string) io.Reader {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return bytes.NewReader(m.files[id])
+}
+
+func (m *mockTaxBatchJobRepository) ClaimJob(lease time.Duration) (TaxBatchJob, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, job := range m.jobs {
+		if job.Status == jobStatus.Pending {
+			job.Status = jobStatus.Running
+			job.Attempt++
+			return *job, true, nil
+		}
+	}
+
+	return TaxBatchJob{}, false, nil
+}
+
+func (m *mockTaxBatchJobRepository) SaveProgress(id string, attempt int, results []TaxBatchJobResult, processedRows int, lastLine int) error {
+	if m.onSave != nil {
+		m.onSave(m, id)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.jobs[id].Status != jobStatus.Running || m.jobs[id].Attempt != attempt {
+		return ErrJobNotRunning
+	}
+
+	m.saves++
+	m.jobs[id].ProcessedRows = processedRows
+	m.jobs[id].LastLine = lastLine
+
+	for _, result := range results {
+		m.results[id][result.Line] = result
+	}
+
+	return nil
+}
+
+func (m *mockTaxBatchJobRepository) FinishJob(id string, attempt int, status string, errorMessage *string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.jobs[id].Status != jobStatus.Running || m.jobs[id].Attempt != attempt {
+		return ErrJobNotRunning
+	}
+
+	m.jobs[id].Status = status
+	m.jobs[id].ErrorMessage = errorMessage
+
+	return nil
+}
+
+func (m *mockTaxBatchJobRepository) CancelJob(id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+
+	if !ok || (job.Status != jobStatus.Pending && job.Status != jobStatus.Running) {
+		return false, nil
+	}
+
+	job.Status = jobStatus.Cancelled
+
+	return true, nil
+}
+
+func (m *mockTaxBatchJobRepository) GetResults(id string, afterLine int, limit int) ([]TaxBatchJobResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	results := []TaxBatchJobResult{}
+
+	for line, result := range m.results[id] {
+		if line > afterLine {
+			results = append(results, result)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Line < results[j].Line
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+type mockTaxCalculatorUseCaseCaseTaxYearNotSupported struct {
+	calculator.TaxCalculatorUseCase
+}
+
+func (m *mockTaxCalculatorUseCaseCaseTaxYearNotSupported) GetTaxSetting(year int) (calculator.TaxSetting, error) {
+	return calculator.TaxSetting{}, calculator.ErrTaxYearNotSupported
+}
+
+type mockTaxCalculatorUseCaseCaseOverflow struct {
+	calculator.TaxCalculatorUseCase
+}
+
+func (m *mockTaxCalculatorUseCaseCaseOverflow) CalculateWithSetting(req calculator.TaxCalculatorReq, setting calculator.TaxSetting) (calculator.TaxCalculatorRes, error) {
+	return calculator.TaxCalculatorRes{}, money.ErrOverflow
+}
+
+func mockValidate() func(i interface{}) error {
+	return myValidator.NewStructValidator(validator.New()).Validate
+}
+
+func mockTaxCSV(rows int) string {
+	return "totalIncome,wht,donation\n" + strings.Repeat("500000,0,0\n", rows)
+}
+
+func mockPendingJob(id string, mode string, totalRows int) TaxBatchJob {
+	return TaxBatchJob{
+		ID:        id,
+		Status:    jobStatus.Pending,
+		TaxYear:   2567,
+		Mode:      mode,
+		FileName:  "taxes.csv",
+		TotalRows: totalRows,
+	}
+}
+
+// CreateJob
+
+func TestCreateJob_ShouldCreatePendingJob_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	repo := newMockTaxBatchJobRepository()
+	usecase := NewTaxBatchJobUseCase(repo, mock.NewMockTaxCalculatorUseCase(), mockValidate())
+
+	// Act
+	res, err := usecase.CreateJob(CreateTaxBatchJobReq{
+		Mode:     calculator.CSVModeLenient,
+		FileName: "taxes.csv",
+		File:     strings.NewReader("totalIncome,wht,donation\n500000,0,0\na,0,0\n600000,0\n"),
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, res.ID, 32)
+	assert.Equal(t, jobStatus.Pending, res.Status)
+	assert.Equal(t, 2567, res.TaxYear)
+	assert.Equal(t, calculator.CSVModeLenient, res.Mode)
+	assert.Equal(t, "taxes.csv", res.FileName)
+	assert.Equal(t, 3, res.TotalRows)
+	assert.Equal(t, money.Rate(0), res.Progress)
+	assert.Equal(t, "totalIncome,wht,donation\n500000,0,0\na,0,0\n600000,0\n", string(repo.files[res.ID]))
+}
+
+func TestCreateJob_ShouldReturnTaxCSVHeaderError_WhenInvalidHeader(t *testing.T) {
+	// Arrange
+	repo := newMockTaxBatchJobRepository()
+	usecase := NewTaxBatchJobUseCase(repo, mock.NewMockTaxCalculatorUseCase(), mockValidate())
+
+	// Act
+	_, err := usecase.CreateJob(CreateTaxBatchJobReq{
+		Mode: calculator.CSVModeStrict,
+		File: strings.NewReader("totalIncome,bonus\n500000,0\n"),
+	})
+
+	// Assert
+	var headerErr *calculator.TaxCSVHeaderError
+
+	assert.ErrorAs(t, err, &headerErr)
+	assert.Equal(t, calculator.TaxCSVRowErrorRes{Line: 1, Column: "bonus", Value: "bonus", Message: "unknown column"}, headerErr.RowErr)
+	assert.Empty(t, repo.jobs)
+}
+
+func TestCreateJob_ShouldReturnErrTaxYearNotSupported_WhenGetTaxSettingError(t *testing.T) {
+	// Arrange
+	repo := newMockTaxBatchJobRepository()
+	usecase := NewTaxBatchJobUseCase(repo, &mockTaxCalculatorUseCaseCaseTaxYearNotSupported{}, mockValidate())
+
+	// Act
+	_, err := usecase.CreateJob(CreateTaxBatchJobReq{
+		TaxYear: 2500,
+		Mode:    calculator.CSVModeStrict,
+		File:    strings.NewReader(mockTaxCSV(1)),
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, calculator.ErrTaxYearNotSupported)
+	assert.Empty(t, repo.jobs)
+}
+
+// processNextJob
+
+func TestProcessNextJob_ShouldReturnFalse_WhenNoJob(t *testing.T) {
+	// Arrange
+	usecase := NewTaxBatchJobUseCase(newMockTaxBatchJobRepository(), mock.NewMockTaxCalculatorUseCase(), mockValidate()).(*taxBatchJobUseCase)
+
+	// Act
+	processed, err := usecase.processNextJob(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, processed)
+}
+
+func TestProcessNextJob_ShouldCalculateEveryRowAndReportBadRows_WhenLenientMode(t *testing.T) {
+	// Arrange
+	repo := newMockTaxBatchJobRepository()
+	repo.addJob(mockPendingJob("job-1", calculator.CSVModeLenient, 4), "totalIncome,wht,donation\n500000,0,0\na,0,0\n500000,0,-1\n600000,40000,20000\n")
+	usecase := NewTaxBatchJobUseCase(repo, mock.NewMockTaxCalculatorUseCase(), mockValidate()).(*taxBatchJobUseCase)
+
+	// Act
+	processed, err := usecase.processNextJob(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, processed)
+
+	job := repo.job("job-1")
+
+	assert.Equal(t, jobStatus.Completed, job.Status)
+	assert.Equal(t, 4, job.ProcessedRows)
+	assert.Equal(t, 5, job.LastLine)
+
+	res, err := usecase.GetJobResults("job-1", TaxBatchJobResultsReq{Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, jobStatus.Completed, res.Status)
+	assert.Equal(t, []int{2, 5}, []int{res.Taxes[0].Line, res.Taxes[1].Line})
+	assert.Equal(t, money.FromBaht(29000), res.Taxes[0].Tax)
+	assert.Equal(t, money.FromBaht(2000), res.Taxes[1].TaxRefund)
+	assert.Equal(t, []calculator.TaxCSVRowErrorRes{
+		{Line: 3, Column: "totalIncome", Value: "a", Message: `invalid amount: "a"`},
+		{Line: 4, Column: "donation", Value: "-1", Message: "failed on the 'gte=0' rule"},
+	}, res.Errors)
+	assert.Equal(t, 5, res.LastLine)
+	assert.False(t, res.HasMore)
+}
+
+func TestProcessNextJob_ShouldReportRowAsBadRow_WhenAmountTooLarge(t *testing.T) {
+	// Arrange
+	repo := newMockTaxBatchJobRepository()
+	repo.addJob(mockPendingJob("job-1", calculator.CSVModeLenient, 1), mockTaxCSV(1))
+	usecase := NewTaxBatchJobUseCase(repo, &mockTaxCalculatorUseCaseCaseOverflow{mock.NewMockTaxCalculatorUseCase()}, mockValidate()).(*taxBatchJobUseCase)
+
+	// Act
+	processed, err := usecase.processNextJob(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	assert.Equal(t, jobStatus.Completed, repo.job("job-1").Status)
+
+	res, err := usecase.GetJobResults("job-1", TaxBatchJobResultsReq{Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Empty(t, res.Taxes)
+	assert.Equal(t, []calculator.TaxCSVRowErrorRes{{Line: 2, Message: "amount too large"}}, res.Errors)
+}
+
+func TestProcessNextJob_ShouldFailJobWithBadRow_WhenStrictModeHasBadRow(t *testing.T) {
+	// Arrange
+	repo := newMockTaxBatchJobRepository()
+	repo.addJob(mockPendingJob("job-1", calculator.CSVModeStrict, 3), "totalIncome,wht,donation\n500000,0,0\n500000,b,0\n600000,40000,20000\n")
+	usecase := NewTaxBatchJobUseCase(repo, mock.NewMockTaxCalculatorUseCase(), mockValidate()).(*taxBatchJobUseCase)
+
+	// Act
+	processed, err := usecase.processNextJob(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, processed)
+
+	res, err := usecase.GetJob("job-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, jobStatus.Failed, res.Status)
+	assert.Equal(t, "row 3 is invalid", res.Error)
+	assert.Equal(t, 0, res.ProcessedRows)
+
+	results, err := usecase.GetJobResults("job-1", TaxBatchJobResultsReq{Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Empty(t, results.Taxes)
+	assert.Equal(t, []calculator.TaxCSVRowErrorRes{
+		{Line: 3, Column: "wht", Value: "b", Message: `invalid amount: "b"`},
+	}, results.Errors)
+}
+
+func TestProcessNextJob_ShouldFailJob_WhenTaxYearNotSupported(t *testing.T) {
+	// Arrange
+	repo := newMockTaxBatchJobRepository()
+	repo.addJob(mockPendingJob("job-1", calculator.CSVModeStrict, 1), mockTaxCSV(1))
+	usecase := NewTaxBatchJobUseCase(repo, &mockTaxCalculatorUseCaseCaseTaxYearNotSupported{}, mockValidate()).(*taxBatchJobUseCase)
+
+	// Act
+	processed, err := usecase.processNextJob(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	assert.Equal(t, jobStatus.Failed, repo.job("job-1").Status)
+	assert.Equal(t, "Tax year not supported", *repo.job("job-1").ErrorMessage)
+}
+
+func TestProcessNextJob_ShouldSaveProgressByChunk_WhenFileHasManyRows(t *testing.T) {
+	// Arrange
+	rows := jobChunkSize*2 + 50
+	repo := newMockTaxBatchJobRepository()
+	repo.addJob(mockPendingJob("job-1", calculator.CSVModeStrict, rows), mockTaxCSV(rows))
+	usecase := NewTaxBatchJobUseCase(repo, mock.NewMockTaxCalculatorUseCase(), mockValidate()).(*taxBatchJobUseCase)
+
+	// Act
+	_, err := usecase.processNextJob(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 3, repo.saves)
+
+	res, err := usecase.GetJob("job-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, jobStatus.Completed, res.Status)
+	assert.Equal(t, rows, res.ProcessedRows)
+	assert.Equal(t, money.FromPercent(100), res.Progress)
+	assert.Len(t, repo.results["job-1"], rows)
+}
+
+func TestProcessNextJob_ShouldRenewLease_WhenFileTakesLongerThanLease(t *testing.T) {
+	// Arrange
+	rows := jobChunkSize * 3
+	repo := newMockTaxBatchJobRepository()
+	repo.addJob(mockPendingJob("job-1", calculator.CSVModeStrict, rows), mockTaxCSV(rows))
+
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	clock := start
+	savedAt := start
+	var longestGap time.Duration
+
+	repo.onSave = func(m *mockTaxBatchJobRepository, id string) {
+		longestGap = max(longestGap, clock.Sub(savedAt))
+		savedAt = clock
+	}
+
+	validate := mockValidate()
+
+	// every row takes a second to check, so each pass over the file outlasts the lease
+	usecase := NewTaxBatchJobUseCase(repo, mock.NewMockTaxCalculatorUseCase(), func(i interface{}) error {
+		clock = clock.Add(time.Second)
+		return validate(i)
+	}).(*taxBatchJobUseCase)
+	usecase.now = func() time.Time {
+		return clock
+	}
+
+	// Act
+	processed, err := usecase.processNextJob(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	assert.Greater(t, clock.Sub(start), 2*jobLease)
+	assert.Less(t, longestGap, jobLease)
+	assert.Equal(t, jobStatus.Completed, repo.job("job-1").Status)
+	assert.Equal(t, rows, repo.job("job-1").ProcessedRows)
+}
+
+func TestProcessNextJob_ShouldResumeAfterLastLine_WhenJobWasStopped(t *testing.T) {
+	// Arrange
+	repo := newMockTaxBatchJobRepository()
+	job := mockPendingJob("job-1", calculator.CSVModeStrict, 4)
+	job.ProcessedRows = 2
+	job.LastLine = 3
+	repo.addJob(job, "totalIncome,wht,donation\n500000,0,0\n500000,0,0\n600000,40000,20000\n750000,50000,15000\n")
+	usecase := NewTaxBatchJobUseCase(repo, mock.NewMockTaxCalculatorUseCase(), mockValidate()).(*taxBatchJobUseCase)
+
+	// Act
+	_, err := usecase.processNextJob(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+
+	res, err := usecase.GetJobResults("job-1", TaxBatchJobResultsReq{Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, jobStatus.Completed, res.Status)
+	assert.Equal(t, []int{4, 5}, []int{res.Taxes[0].Line, res.Taxes[1].Line})
+	assert.Len(t, res.Taxes, 2)
+	assert.Equal(t, 4, repo.job("job-1").ProcessedRows)
+}
+
+func TestProcessNextJob_ShouldStopAndKeepSavedRows_WhenJobCancelled(t *testing.T) {
+	// Arrange
+	rows := jobChunkSize * 3
+	repo := newMockTaxBatchJobRepository()
+	repo.addJob(mockPendingJob("job-1", calculator.CSVModeLenient, rows), mockTaxCSV(rows))
+	repo.onSave = func(m *mockTaxBatchJobRepository, id string) {
+		if m.saves == 1 {
+			m.CancelJob(id)
+		}
+	}
+	usecase := NewTaxBatchJobUseCase(repo, mock.NewMockTaxCalculatorUseCase(), mockValidate()).(*taxBatchJobUseCase)
+
+	// Act
+	processed, err := usecase.processNextJob(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	assert.Equal(t, jobStatus.Cancelled, repo.job("job-1").Status)
+	assert.Equal(t, jobChunkSize, repo.job("job-1").ProcessedRows)
+	assert.Len(t, repo.results["job-1"], jobChunkSize)
+}
+
+func TestProcessNextJob_ShouldStopWithoutSaving_WhenJobClaimedByAnotherWorker(t *testing.T) {
+	// Arrange
+	rows := jobChunkSize * 3
+	repo := newMockTaxBatchJobRepository()
+	repo.addJob(mockPendingJob("job-1", calculator.CSVModeLenient, rows), mockTaxCSV(rows))
+	repo.onSave = func(m *mockTaxBatchJobRepository, id string) {
+		if m.saves == 1 {
+			m.mu.Lock()
+			m.jobs[id].Attempt++
+			m.mu.Unlock()
+		}
+	}
+	usecase := NewTaxBatchJobUseCase(repo, mock.NewMockTaxCalculatorUseCase(), mockValidate()).(*taxBatchJobUseCase)
+
+	// Act
+	processed, err := usecase.processNextJob(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	assert.Equal(t, jobStatus.Running, repo.job("job-1").Status)
+	assert.Equal(t, jobChunkSize, repo.job("job-1").ProcessedRows)
+	assert.Len(t, repo.results["job-1"], jobChunkSize)
+}
+
+func TestProcessNextJob_ShouldLeaveJobRunning_WhenContextCancelled(t *testing.T) {
+	// Arrange
+	repo := newMockTaxBatchJobRepository()
+	repo.addJob(mockPendingJob("job-1", calculator.CSVModeLenient, 1), mockTaxCSV(1))
+	usecase := NewTaxBatchJobUseCase(repo, mock.NewMockTaxCalculatorUseCase(), mockValidate()).(*taxBatchJobUseCase)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	processed, err := usecase.processNextJob(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	assert.Equal(t, jobStatus.Running, repo.job("job-1").Status)
+	assert.Equal(t, 0, repo.saves)
+}
+
+// StartWorkers
+
+func TestStartWorkers_ShouldCompleteJob_WhenJobCreated(t *testing.T) {
+	// Arrange
+	repo := newMockTaxBatchJobRepository()
+	usecase := NewTaxBatchJobUseCase(repo, mock.NewMockTaxCalculatorUseCase(), mockValidate())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	usecase.StartWorkers(ctx, DefaultWorkers)
+
+	// Act
+	res, err := usecase.CreateJob(CreateTaxBatchJobReq{
+		Mode: calculator.CSVModeStrict,
+		File: strings.NewReader(mockTaxCSV(10)),
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return repo.job(res.ID).Status == jobStatus.Completed
+	}, time.Second, 10*time.Millisecond)
+}
+
+// GetJobResults
+
+func TestGetJobResults_ShouldReturnPage_WhenMoreResultsThanLimit(t *testing.T) {
+	// Arrange
+	repo := newMockTaxBatchJobRepository()
+	repo.addJob(mockPendingJob("job-1", calculator.CSVModeStrict, 5), mockTaxCSV(5))
+	usecase := NewTaxBatchJobUseCase(repo, mock.NewMockTaxCalculatorUseCase(), mockValidate()).(*taxBatchJobUseCase)
+	_, err := usecase.processNextJob(context.Background())
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name             string
+		req              TaxBatchJobResultsReq
+		expectedLines    []int
+		expectedLastLine int
+		expectedHasMore  bool
+	}{
+		{"Test case 1", TaxBatchJobResultsReq{AfterLine: 0, Limit: 2}, []int{2, 3}, 3, true},
+		{"Test case 2", TaxBatchJobResultsReq{AfterLine: 3, Limit: 2}, []int{4, 5}, 5, true},
+		{"Test case 3", TaxBatchJobResultsReq{AfterLine: 5, Limit: 2}, []int{6}, 6, false},
+		{"Test case 4", TaxBatchJobResultsReq{AfterLine: 6, Limit: 2}, []int{}, 6, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			res, err := usecase.GetJobResults("job-1", tc.req)
+
+			// Assert
+			assert.NoError(t, err)
+
+			lines := []int{}
+
+			for _, tax := range res.Taxes {
+				lines = append(lines, tax.Line)
+			}
+
+			assert.Equal(t, tc.expectedLines, lines)
+			assert.Equal(t, tc.expectedLastLine, res.LastLine)
+			assert.Equal(t, tc.expectedHasMore, res.HasMore)
+		})
+	}
+}
+
+func TestGetJobResults_ShouldReturnErrJobNotFound_WhenJobNotExists(t *testing.T) {
+	// Arrange
+	usecase := NewTaxBatchJobUseCase(newMockTaxBatchJobRepository(), mock.NewMockTaxCalculatorUseCase(), mockValidate())
+
+	// Act
+	_, err := usecase.GetJobResults("job-1", TaxBatchJobResultsReq{Limit: 10})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+// CancelJob
+
+func TestCancelJob_ShouldCancel_WhenJobNotFinished(t *testing.T) {
+	for _, status := range []string{jobStatus.Pending, jobStatus.Running} {
+		t.Run(status, func(t *testing.T) {
+			// Arrange
+			repo := newMockTaxBatchJobRepository()
+			job := mockPendingJob("job-1", calculator.CSVModeStrict, 1)
+			job.Status = status
+			repo.addJob(job, mockTaxCSV(1))
+			usecase := NewTaxBatchJobUseCase(repo, mock.NewMockTaxCalculatorUseCase(), mockValidate())
+
+			// Act
+			res, err := usecase.CancelJob("job-1")
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, jobStatus.Cancelled, res.Status)
+		})
+	}
+}
+
+func TestCancelJob_ShouldReturnErr_WhenJobFinishedOrNotExists(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   string
+		id       string
+		expected error
+	}{
+		{"Test case 1", jobStatus.Completed, "job-1", ErrJobFinished},
+		{"Test case 2", jobStatus.Failed, "job-1", ErrJobFinished},
+		{"Test case 3", jobStatus.Cancelled, "job-1", ErrJobFinished},
+		{"Test case 4", jobStatus.Pending, "job-2", ErrJobNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			repo := newMockTaxBatchJobRepository()
+			job := mockPendingJob("job-1", calculator.CSVModeStrict, 1)
+			job.Status = tc.status
+			repo.addJob(job, mockTaxCSV(1))
+			usecase := NewTaxBatchJobUseCase(repo, mock.NewMockTaxCalculatorUseCase(), mockValidate())
+
+			// Act
+			_, err := usecase.CancelJob(tc.id)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.expected)
+			assert.Equal(t, tc.status, repo.job("job-1").Status)
+		})
+	}
+}
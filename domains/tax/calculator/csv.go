@@ -0,0 +1,259 @@
+package calculator
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/money"
+)
+
+// ErrUnreadableTaxCSV is returned when the tax csv cannot be read as csv at all
+var ErrUnreadableTaxCSV = errors.New("unreadable tax csv")
+
+// TaxCSVHeaderError is returned when a column of the header row is unknown, duplicated or missing
+type TaxCSVHeaderError struct {
+	RowErr TaxCSVRowErrorRes
+}
+
+func (e *TaxCSVHeaderError) Error() string {
+	return fmt.Sprintf("%s: %s", e.RowErr.Message, e.RowErr.Column)
+}
+
+// TaxCSVRowErrorRes is why a row of the tax csv was not calculated, Line counts the header as line 1
+// and Column is empty when the row itself could not be read
+type TaxCSVRowErrorRes struct {
+	Line    int    `json:"line"`
+	Column  string `json:"column"`
+	Value   string `json:"value"`
+	Message string `json:"message"`
+}
+
+const (
+	taxCSVColumnTotalIncome = "totalIncome"
+	taxCSVColumnWHT         = "wht"
+)
+
+// taxCSVLayout is where every column of the tax csv is, an allowance column is named by its allowance type
+type taxCSVLayout struct {
+	columns     []string
+	totalIncome int
+	wht         int
+	allowances  []int
+}
+
+// readTaxCSVLayout reads the header row, columns may come in any order but every one must be known,
+// appear once and totalIncome and wht must be there
+func readTaxCSVLayout(reader *csv.Reader) (taxCSVLayout, error) {
+	header, err := reader.Read()
+
+	if err != nil {
+		return taxCSVLayout{}, fmt.Errorf("%w: %v", ErrUnreadableTaxCSV, err)
+	}
+
+	layout := taxCSVLayout{
+		columns:     make([]string, len(header)),
+		totalIncome: -1,
+		wht:         -1,
+	}
+
+	for i, column := range header {
+		// spreadsheet exports may start the file with a byte order mark
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+
+		if slices.Contains(layout.columns[:i], column) {
+			return taxCSVLayout{}, &TaxCSVHeaderError{RowErr: TaxCSVRowErrorRes{Line: 1, Column: column, Value: column, Message: "duplicate column"}}
+		}
+
+		layout.columns[i] = column
+
+		switch _, isAllowance := allowanceType.Get(column); {
+		case column == taxCSVColumnTotalIncome:
+			layout.totalIncome = i
+		case column == taxCSVColumnWHT:
+			layout.wht = i
+		case isAllowance:
+			layout.allowances = append(layout.allowances, i)
+		default:
+			return taxCSVLayout{}, &TaxCSVHeaderError{RowErr: TaxCSVRowErrorRes{Line: 1, Column: column, Value: column, Message: "unknown column"}}
+		}
+	}
+
+	for _, required := range []struct {
+		column string
+		index  int
+	}{
+		{taxCSVColumnTotalIncome, layout.totalIncome},
+		{taxCSVColumnWHT, layout.wht},
+	} {
+		if required.index < 0 {
+			return taxCSVLayout{}, &TaxCSVHeaderError{RowErr: TaxCSVRowErrorRes{Line: 1, Column: required.column, Message: "missing column"}}
+		}
+	}
+
+	return layout, nil
+}
+
+// toTaxReq builds the request of a row, a blank allowance cell means the allowance is not claimed.
+// It also returns the column every allowance of the request was read from.
+func (l taxCSVLayout) toTaxReq(line int, row []string, year int) (TaxCalculatorReq, []int, *TaxCSVRowErrorRes) {
+	parse := func(i int) (money.Money, *TaxCSVRowErrorRes) {
+		amount, err := money.Parse(row[i])
+
+		if err != nil {
+			return 0, &TaxCSVRowErrorRes{Line: line, Column: l.columns[i], Value: row[i], Message: err.Error()}
+		}
+
+		return amount, nil
+	}
+
+	totalIncome, rowErr := parse(l.totalIncome)
+
+	if rowErr != nil {
+		return TaxCalculatorReq{}, nil, rowErr
+	}
+
+	wht, rowErr := parse(l.wht)
+
+	if rowErr != nil {
+		return TaxCalculatorReq{}, nil, rowErr
+	}
+
+	taxReq := TaxCalculatorReq{
+		TaxYear:     year,
+		TotalIncome: totalIncome,
+		WHT:         wht,
+		Allowances:  []AllowanceReq{},
+	}
+
+	var allowanceColumns []int
+
+	for _, i := range l.allowances {
+		if strings.TrimSpace(row[i]) == "" {
+			continue
+		}
+
+		amount, rowErr := parse(i)
+
+		if rowErr != nil {
+			return TaxCalculatorReq{}, nil, rowErr
+		}
+
+		taxReq.Allowances = append(taxReq.Allowances, AllowanceReq{
+			AllowanceType: l.columns[i],
+			Amount:        amount,
+		})
+		allowanceColumns = append(allowanceColumns, i)
+	}
+
+	return taxReq, allowanceColumns, nil
+}
+
+// toRowError tells which column of the row failed validation
+func (l taxCSVLayout) toRowError(line int, row []string, year int, allowanceColumns []int, err error) TaxCSVRowErrorRes {
+	rowErr := TaxCSVRowErrorRes{Line: line, Message: err.Error()}
+
+	var validationErrs validator.ValidationErrors
+
+	if !errors.As(err, &validationErrs) || len(validationErrs) == 0 {
+		return rowErr
+	}
+
+	fieldErr := validationErrs[0]
+	rule := fieldErr.Tag()
+
+	if fieldErr.Param() != "" {
+		rule += "=" + fieldErr.Param()
+	}
+
+	rowErr.Message = fmt.Sprintf("failed on the '%s' rule", rule)
+
+	column := -1
+	var allowance int
+
+	switch namespace := fieldErr.StructNamespace(); {
+	case namespace == "TaxCalculatorReq.TaxYear":
+		rowErr.Column = "taxYear"
+		rowErr.Value = strconv.Itoa(year)
+	case namespace == "TaxCalculatorReq.TotalIncome":
+		column = l.totalIncome
+	case namespace == "TaxCalculatorReq.WHT":
+		column = l.wht
+	default:
+		if _, err := fmt.Sscanf(namespace, "TaxCalculatorReq.Allowances[%d]", &allowance); err == nil && allowance < len(allowanceColumns) {
+			column = allowanceColumns[allowance]
+		}
+	}
+
+	if column >= 0 {
+		rowErr.Column = l.columns[column]
+		rowErr.Value = row[column]
+	}
+
+	return rowErr
+}
+
+// ReadTaxCSV reads the tax csv one row at a time, every valid request goes to handle and every bad row
+// to handleError with the row as it was read. The record is reused so memory does not grow with the file.
+func ReadTaxCSV(src io.Reader, year int, validate func(i interface{}) error, handle func(line int, row []string, req TaxCalculatorReq) error, handleError func(row []string, rowErr TaxCSVRowErrorRes) error) error {
+	reader := csv.NewReader(src)
+	reader.ReuseRecord = true
+
+	layout, err := readTaxCSVLayout(reader)
+
+	if err != nil {
+		return err
+	}
+
+	for {
+		row, err := reader.Read()
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		var parseErr *csv.ParseError
+
+		if errors.As(err, &parseErr) {
+			if err = handleError(row, TaxCSVRowErrorRes{Line: parseErr.StartLine, Message: parseErr.Err.Error()}); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrUnreadableTaxCSV, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		taxReq, allowanceColumns, rowErr := layout.toTaxReq(line, row, year)
+
+		if rowErr != nil {
+			if err = handleError(row, *rowErr); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err = validate(taxReq); err != nil {
+			if err = handleError(row, layout.toRowError(line, row, year, allowanceColumns, err)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err = handle(line, row, taxReq); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package calculator
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/larb26656/assessment-tax/money"
+	myValidator "github.com/larb26656/assessment-tax/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockValidate() func(i interface{}) error {
+	return myValidator.NewStructValidator(validator.New()).Validate
+}
+
+func skipTaxCSVRow(line int, row []string, req TaxCalculatorReq) error {
+	return nil
+}
+
+func skipTaxCSVRowError(row []string, rowErr TaxCSVRowErrorRes) error {
+	return nil
+}
+
+func TestReadTaxCSV_ShouldReturnTaxCSVHeaderError_WhenInvalidHeader(t *testing.T) {
+	testCases := []struct {
+		name     string
+		csv      string
+		expected TaxCSVRowErrorRes
+	}{
+		{"Test case 1", "totalIncome,wht,bonus\n500000,0,0\n", TaxCSVRowErrorRes{Line: 1, Column: "bonus", Value: "bonus", Message: "unknown column"}},
+		{"Test case 2", "totalIncome,wht,wht\n500000,0,0\n", TaxCSVRowErrorRes{Line: 1, Column: "wht", Value: "wht", Message: "duplicate column"}},
+		{"Test case 3", "totalIncome,donation\n500000,0\n", TaxCSVRowErrorRes{Line: 1, Column: "wht", Message: "missing column"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			err := ReadTaxCSV(strings.NewReader(tc.csv), 0, mockValidate(), skipTaxCSVRow, skipTaxCSVRowError)
+
+			// Assert
+			var headerErr *TaxCSVHeaderError
+
+			assert.ErrorAs(t, err, &headerErr)
+			assert.Equal(t, tc.expected, headerErr.RowErr)
+		})
+	}
+}
+
+func TestReadTaxCSV_ShouldReturnErrUnreadableTaxCSV_WhenFileEmpty(t *testing.T) {
+	// Act
+	err := ReadTaxCSV(strings.NewReader(""), 0, mockValidate(), skipTaxCSVRow, skipTaxCSVRowError)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrUnreadableTaxCSV)
+}
+
+func TestReadTaxCSV_ShouldReturnErrUnreadableTaxCSV_WhenErrorOnReadRow(t *testing.T) {
+	// Arrange
+	src := io.MultiReader(strings.NewReader("totalIncome,wht\n"), iotest.ErrReader(errors.New("error on read")))
+
+	// Act
+	err := ReadTaxCSV(src, 0, mockValidate(), skipTaxCSVRow, skipTaxCSVRowError)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrUnreadableTaxCSV)
+}
+
+func TestReadTaxCSV_ShouldHandleEveryRow_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	var reqs []TaxCalculatorReq
+	var rowErrs []TaxCSVRowErrorRes
+
+	// Act
+	err := ReadTaxCSV(strings.NewReader("totalIncome,wht,donation\n500000,0,\n600000,b,0\n"), 2567, mockValidate(), func(line int, row []string, req TaxCalculatorReq) error {
+		reqs = append(reqs, req)
+		return nil
+	}, func(row []string, rowErr TaxCSVRowErrorRes) error {
+		rowErrs = append(rowErrs, rowErr)
+		return nil
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []TaxCalculatorReq{{TaxYear: 2567, TotalIncome: money.FromBaht(500000), Allowances: []AllowanceReq{}}}, reqs)
+	assert.Len(t, rowErrs, 1)
+	assert.Equal(t, "wht", rowErrs[0].Column)
+}
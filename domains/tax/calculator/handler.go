@@ -2,12 +2,21 @@ package calculator
 
 import (
 	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
-	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/money"
+	"github.com/larb26656/assessment-tax/xlsx"
 )
 
 type TaxCalculatorHttpHandler interface {
@@ -39,16 +48,362 @@ func (t *taxCalculatorHttpHandler) CalculateTax(c echo.Context) error {
 		return err
 	}
 
+	explain := false
+
+	err = echo.QueryParamsBinder(c).Bool("explain", &explain).BindError()
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
 	res, err := t.taxCalculatorUseCase.Calculate(req)
 
+	if errors.Is(err, ErrTaxYearNotSupported) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Tax year not supported")
+	}
+
+	if errors.Is(err, money.ErrOverflow) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Amount too large")
+	}
+
 	if err != nil {
 		fmt.Println(err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
 	}
 
+	// the trace is only returned on request to keep the default response small
+	if !explain {
+		res.Explanation = nil
+	}
+
 	return c.JSON(http.StatusOK, res)
 }
 
+const (
+	resultFormatJSON = "json"
+	resultFormatCSV  = "csv"
+	resultFormatXLSX = "xlsx"
+)
+
+const (
+	// CSVModeStrict fails the whole file on the first bad row
+	CSVModeStrict = "strict"
+	// CSVModeLenient calculates every valid row and reports every bad row
+	CSVModeLenient = "lenient"
+)
+
+// flushEvery is the number of results written before the response is flushed to the client
+const flushEvery = 1000
+
+// taxResultColumns are added after the uploaded columns of a csv or xlsx result, followed by
+// the taxable income and tax of every bracket
+var taxResultColumns = []string{"tax", "taxRefund", "netIncome", "effectiveTaxRate", "effectiveNetTaxRate", "marginalTaxRate", "nextBracketDistance"}
+
+// taxErrorColumns are added to a csv or xlsx result in lenient mode, they are empty on a calculated row
+var taxErrorColumns = []string{"errorColumn", "errorValue", "errorMessage"}
+
+// tableWriter writes the rows of a csv or xlsx result
+type tableWriter interface {
+	Write(record []string) error
+	Flush() error
+	Close() error
+}
+
+type csvTableWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvTableWriter) Write(record []string) error {
+	return w.writer.Write(record)
+}
+
+func (w *csvTableWriter) Flush() error {
+	w.writer.Flush()
+
+	return w.writer.Error()
+}
+
+func (w *csvTableWriter) Close() error {
+	return w.Flush()
+}
+
+// taxResultWriter streams the result of every row as soon as it is calculated. A json result has the
+// result fields only, in lenient mode results carry their line and the bad rows follow them. A csv or
+// xlsx result is the uploaded file with the result columns added, in lenient mode a bad row stays in
+// place with the error columns filled.
+type taxResultWriter struct {
+	res      *echo.Response
+	format   string
+	mode     string
+	columns  []string
+	brackets []string
+	table    tableWriter
+	rows     int
+	items    int
+}
+
+func newTaxResultWriter(res *echo.Response, format string, mode string, columns []string, setting TaxSetting) *taxResultWriter {
+	brackets := make([]string, len(setting.TaxBrackets))
+
+	for i, bracket := range setting.TaxBrackets {
+		brackets[i] = bracket.Label()
+	}
+
+	return &taxResultWriter{
+		res:      res,
+		format:   format,
+		mode:     mode,
+		columns:  columns,
+		brackets: brackets,
+	}
+}
+
+// inlineErrors tells if bad rows are written in place, a json result writes them after the results
+func (w *taxResultWriter) inlineErrors() bool {
+	return w.format != resultFormatJSON
+}
+
+// begin writes the header, a csv or xlsx result is sent as an attachment named after the uploaded file
+func (w *taxResultWriter) begin(filename string) error {
+	if w.format == resultFormatJSON {
+		w.res.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		w.res.WriteHeader(http.StatusOK)
+
+		_, err := w.res.Write([]byte(`{"taxes":[`))
+
+		return err
+	}
+
+	contentType := "text/csv; charset=UTF-8"
+	filename = strings.TrimSuffix(filename, filepath.Ext(filename)) + "-taxes." + w.format
+
+	if w.format == resultFormatXLSX {
+		contentType = xlsx.MIMEType
+	}
+
+	w.res.Header().Set(echo.HeaderContentType, contentType)
+	w.res.Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.res.WriteHeader(http.StatusOK)
+
+	if w.format == resultFormatXLSX {
+		table, err := xlsx.NewWriter(w.res, "Taxes")
+
+		if err != nil {
+			return err
+		}
+
+		w.table = table
+	} else {
+		w.table = &csvTableWriter{writer: csv.NewWriter(w.res)}
+	}
+
+	header := w.uploadedCells("line", w.columns)
+	header = append(header, taxResultColumns...)
+
+	for _, bracket := range w.brackets {
+		header = append(header, "taxableIncome "+bracket, "bracketTax "+bracket)
+	}
+
+	if w.mode == CSVModeLenient {
+		header = append(header, taxErrorColumns...)
+	}
+
+	return w.writeTable(header)
+}
+
+// uploadedCells starts a csv or xlsx record with the uploaded row, a short row is padded to the header
+func (w *taxResultWriter) uploadedCells(line string, row []string) []string {
+	record := make([]string, 0, len(w.columns)+len(taxResultColumns)+len(w.brackets)*2+len(taxErrorColumns)+1)
+
+	if w.mode == CSVModeLenient {
+		record = append(record, line)
+	}
+
+	uploaded := make([]string, len(w.columns))
+	copy(uploaded, row)
+
+	return append(record, uploaded...)
+}
+
+func (w *taxResultWriter) write(line int, row []string, req TaxCalculatorReq, res TaxCalculatorRes) error {
+	if w.format == resultFormatJSON {
+		detail := ToMultipleDetailRes(req, res)
+
+		if w.mode == CSVModeLenient {
+			detail.Line = line
+		}
+
+		return w.writeJSON(detail)
+	}
+
+	netIncome := ""
+
+	if res.Explanation != nil {
+		netIncome = res.Explanation.NetIncome.String()
+	}
+
+	nextBracketDistance := ""
+
+	if res.NextBracketDistance != nil {
+		nextBracketDistance = res.NextBracketDistance.String()
+	}
+
+	record := append(w.uploadedCells(strconv.Itoa(line), row),
+		res.Tax.String(),
+		res.TaxRefund.String(),
+		netIncome,
+		res.EffectiveTaxRate.String(),
+		res.EffectiveNetTaxRate.String(),
+		res.MarginalTaxRate.String(),
+		nextBracketDistance,
+	)
+
+	for i := range w.brackets {
+		if i >= len(res.TaxLevel) {
+			record = append(record, "", "")
+			continue
+		}
+
+		record = append(record, res.TaxLevel[i].TaxableIncome.String(), res.TaxLevel[i].BracketTax.String())
+	}
+
+	if w.mode == CSVModeLenient {
+		record = append(record, "", "", "")
+	}
+
+	return w.writeTable(record)
+}
+
+// beginErrors closes the results of a json result, the bad rows are written after it
+func (w *taxResultWriter) beginErrors() error {
+	if w.inlineErrors() {
+		return nil
+	}
+
+	w.items = 0
+
+	_, err := w.res.Write([]byte(`],"errors":[`))
+
+	return err
+}
+
+func (w *taxResultWriter) writeError(row []string, rowErr TaxCSVRowErrorRes) error {
+	if w.format == resultFormatJSON {
+		return w.writeJSON(rowErr)
+	}
+
+	record := w.uploadedCells(strconv.Itoa(rowErr.Line), row)
+	record = append(record, make([]string, len(taxResultColumns)+len(w.brackets)*2)...)
+
+	return w.writeTable(append(record, rowErr.Column, rowErr.Value, rowErr.Message))
+}
+
+func (w *taxResultWriter) writeTable(record []string) error {
+	if err := w.table.Write(record); err != nil {
+		return err
+	}
+
+	return w.wrote()
+}
+
+func (w *taxResultWriter) writeJSON(item any) error {
+	data, err := json.Marshal(item)
+
+	if err != nil {
+		return err
+	}
+
+	if w.items > 0 {
+		data = append([]byte(","), data...)
+	}
+
+	if _, err = w.res.Write(data); err != nil {
+		return err
+	}
+
+	w.items++
+
+	return w.wrote()
+}
+
+func (w *taxResultWriter) wrote() error {
+	w.rows++
+
+	if w.rows%flushEvery == 0 {
+		return w.flush()
+	}
+
+	return nil
+}
+
+func (w *taxResultWriter) end() error {
+	if w.format == resultFormatJSON {
+		if _, err := w.res.Write([]byte(`]}`)); err != nil {
+			return err
+		}
+	} else if err := w.table.Close(); err != nil {
+		return err
+	}
+
+	return w.flush()
+}
+
+func (w *taxResultWriter) flush() error {
+	if w.table != nil {
+		if err := w.table.Flush(); err != nil {
+			return err
+		}
+	}
+
+	w.res.Flush()
+
+	return nil
+}
+
+// resultFormatOf picks the format of the result, the format query wins over the Accept header
+// and json is the default
+func resultFormatOf(c echo.Context, format string) string {
+	if format != "" {
+		return format
+	}
+
+	accept := c.Request().Header.Get(echo.HeaderAccept)
+
+	switch {
+	case strings.Contains(accept, xlsx.MIMEType):
+		return resultFormatXLSX
+	case strings.Contains(accept, "text/csv"):
+		return resultFormatCSV
+	default:
+		return resultFormatJSON
+	}
+}
+
+// taxCSVHTTPError answers a tax csv that cannot be read or calculated with 400, a bad header tells which column is wrong
+func taxCSVHTTPError(err error) error {
+	var headerErr *TaxCSVHeaderError
+
+	if errors.As(err, &headerErr) {
+		return echo.NewHTTPError(http.StatusBadRequest, headerErr.RowErr)
+	}
+
+	if errors.Is(err, ErrUnreadableTaxCSV) {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if errors.Is(err, money.ErrOverflow) {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Amount too large")
+	}
+
+	return err
+}
+
+// CalculateTaxWithCSV streams the tax of every row. In strict mode rows are checked in a first pass so
+// a bad row still fails the whole file before anything is written. In lenient mode every valid row is
+// calculated, a json result reports the bad rows after them from a second pass.
 func (t *taxCalculatorHttpHandler) CalculateTaxWithCSV(c echo.Context) error {
 	// Read form file
 	file, err := c.FormFile("taxFile")
@@ -58,6 +413,32 @@ func (t *taxCalculatorHttpHandler) CalculateTaxWithCSV(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
 	}
 
+	year := 0
+
+	if taxYearValue := c.FormValue("taxYear"); taxYearValue != "" {
+		year, err = strconv.Atoi(taxYearValue)
+
+		if err != nil {
+			fmt.Println("Error converting TaxYear:", err)
+			return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+		}
+	}
+
+	format := ""
+	mode := CSVModeStrict
+
+	err = echo.QueryParamsBinder(c).
+		String("format", &format).
+		String("mode", &mode).
+		BindError()
+
+	format = resultFormatOf(c, format)
+
+	if err != nil || !slices.Contains([]string{resultFormatJSON, resultFormatCSV, resultFormatXLSX}, format) || (mode != CSVModeStrict && mode != CSVModeLenient) {
+		fmt.Println("Error converting format or mode:", format, mode, err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
 	src, err := file.Open()
 
 	if err != nil {
@@ -65,64 +446,94 @@ func (t *taxCalculatorHttpHandler) CalculateTaxWithCSV(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
 	}
 
-	reader := csv.NewReader(src)
-	records, err := reader.ReadAll()
+	defer src.Close()
+
+	// a bad header fails the file in both modes, before anything is written
+	layout, err := readTaxCSVLayout(csv.NewReader(src))
 
 	if err != nil {
-		fmt.Println("Error reading CSV:", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+		return taxCSVHTTPError(err)
 	}
 
-	var taxReqs []TaxCalculatorReq
+	if _, err = src.Seek(0, io.SeekStart); err != nil {
+		fmt.Println("Error rewinding file:", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
 
-	for i, row := range records {
-		if i == 0 {
-			continue
-		}
+	skipRow := func(line int, row []string, req TaxCalculatorReq) error {
+		return nil
+	}
 
-		totalIncome, err := strconv.ParseFloat(row[0], 64)
-		if err != nil {
-			fmt.Println("Error converting TotalIncome:", err)
-			return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
-		}
+	skipRowError := func(row []string, rowErr TaxCSVRowErrorRes) error {
+		return nil
+	}
 
-		wht, err := strconv.ParseFloat(row[1], 64)
-		if err != nil {
-			fmt.Println("Error converting WHT:", err)
-			return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
-		}
+	if mode == CSVModeStrict {
+		err = ReadTaxCSV(src, year, c.Validate, skipRow, func(row []string, rowErr TaxCSVRowErrorRes) error {
+			fmt.Println("Error on CSV row:", rowErr)
+			return echo.NewHTTPError(http.StatusBadRequest, rowErr)
+		})
 
-		donation, err := strconv.ParseFloat(row[2], 64)
 		if err != nil {
-			fmt.Println("Error converting Donation:", err)
-			return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+			return taxCSVHTTPError(err)
 		}
+	}
 
-		taxReq := TaxCalculatorReq{
-			TotalIncome: totalIncome,
-			WHT:         wht,
-			Allowances: []AllowanceReq{
-				{
-					AllowanceType: allowanceType.Donation,
-					Amount:        donation,
-				},
-			},
-		}
+	setting, err := t.taxCalculatorUseCase.GetTaxSetting(taxYear.Resolve(year))
+
+	if errors.Is(err, ErrTaxYearNotSupported) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Tax year not supported")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	if _, err = src.Seek(0, io.SeekStart); err != nil {
+		fmt.Println("Error rewinding file:", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	writer := newTaxResultWriter(c.Response(), format, mode, layout.columns, setting)
+
+	if err = writer.begin(file.Filename); err != nil {
+		return err
+	}
+
+	handleError := skipRowError
+
+	if writer.inlineErrors() {
+		handleError = writer.writeError
+	}
 
-		if err = c.Validate(taxReq); err != nil {
-			fmt.Println("Error validate req:", err)
+	err = ReadTaxCSV(src, year, c.Validate, func(line int, row []string, req TaxCalculatorReq) error {
+		res, err := t.taxCalculatorUseCase.CalculateWithSetting(req, setting)
+
+		if err != nil {
 			return err
 		}
 
-		taxReqs = append(taxReqs, taxReq)
+		return writer.write(line, row, req, res)
+	}, handleError)
+
+	if err != nil {
+		return taxCSVHTTPError(err)
 	}
 
-	result, err := t.taxCalculatorUseCase.CalculateMultiRequest(taxReqs)
+	if mode == CSVModeLenient && !writer.inlineErrors() {
+		if _, err = src.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
 
-	if err != nil {
-		fmt.Println(err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+		if err = writer.beginErrors(); err != nil {
+			return err
+		}
+
+		if err = ReadTaxCSV(src, year, c.Validate, skipRow, writer.writeError); err != nil {
+			return taxCSVHTTPError(err)
+		}
 	}
 
-	return c.JSON(http.StatusOK, result)
+	return writer.end()
 }
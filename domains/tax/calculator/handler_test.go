@@ -1,7 +1,10 @@
 package calculator
 
 import (
+	"archive/zip"
 	"bytes"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"mime/multipart"
 	"net/http"
@@ -11,62 +14,107 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/constant/taxMethod"
+	"github.com/larb26656/assessment-tax/domains/admin/taxBracket"
+	"github.com/larb26656/assessment-tax/money"
 	myValidator "github.com/larb26656/assessment-tax/validator"
+	"github.com/larb26656/assessment-tax/xlsx"
 	"github.com/stretchr/testify/assert"
 )
 
 type mockTaxCalculatorUsecase struct {
 }
 
-func (m *mockTaxCalculatorUsecase) CalculateAllowances(allowances []AllowanceReq, maxDonation float64, maxKReceipt float64) float64 {
-	return 0.0
+func (m *mockTaxCalculatorUsecase) CalculateTaxDeduction(personalDeduction, totalAllowances money.Money) money.Money {
+	return money.FromBaht(0)
 }
 
-func (m *mockTaxCalculatorUsecase) CalculateTaxDeduction(personalDeduction, totalAllowances float64) float64 {
-	return 0.0
+func (m *mockTaxCalculatorUsecase) CalculateNetIncome(income, taxDeduction money.Money) money.Money {
+	return money.FromBaht(0)
 }
 
-func (m *mockTaxCalculatorUsecase) CalculateNetIncome(income, taxDeduction float64) float64 {
-	return 0.0
+func (m *mockTaxCalculatorUsecase) CalculateTax(netIncome money.Money, wht money.Money, taxBrackets []taxBracket.TaxBracket) (money.Money, money.Money, []TaxLevelRes, error) {
+	return money.FromBaht(0), money.FromBaht(0), []TaxLevelRes{}, nil
 }
 
-func (m *mockTaxCalculatorUsecase) CalculateTax(netIncome float64, wht float64) (float64, float64, []TaxLevelRes) {
-	return 0.0, 0.0, []TaxLevelRes{}
+func (m *mockTaxCalculatorUsecase) CalculateMinimumTax(incomes []IncomeReq) (money.Money, error) {
+	return money.FromBaht(0), nil
+}
+
+func (m *mockTaxCalculatorUsecase) GetTaxSetting(year int) (TaxSetting, error) {
+	return TaxSetting{}, nil
+}
+
+func (m *mockTaxCalculatorUsecase) CalculateWithSetting(req TaxCalculatorReq, setting TaxSetting) (TaxCalculatorRes, error) {
+	return TaxCalculatorRes{}, nil
 }
 
 func (m *mockTaxCalculatorUsecase) Calculate(req TaxCalculatorReq) (TaxCalculatorRes, error) {
 	return TaxCalculatorRes{
-		Tax:       14000.0,
-		TaxRefund: 0.0,
+		Tax:       money.FromBaht(14000),
+		TaxRefund: money.FromBaht(0),
 		TaxLevel: []TaxLevelRes{
 			{
-				Level: "0-150,000",
-				Tax:   0.0,
+				Level:         "0-150,000",
+				MinIncome:     money.FromBaht(0),
+				MaxIncome:     mockMaxIncome(150000),
+				Rate:          money.FromPercent(0),
+				TaxableIncome: money.FromBaht(150000),
+				BracketTax:    money.FromBaht(0),
+				CumulativeTax: money.FromBaht(0),
+				Tax:           money.FromBaht(0),
 			},
 			{
-				Level: "150,001-500,000",
-				Tax:   14000.0,
+				Level:         "150,001-500,000",
+				MinIncome:     money.FromBaht(150000),
+				MaxIncome:     mockMaxIncome(500000),
+				Rate:          money.FromPercent(10),
+				TaxableIncome: money.FromBaht(140000),
+				BracketTax:    money.FromBaht(14000),
+				CumulativeTax: money.FromBaht(14000),
+				Tax:           money.FromBaht(14000),
 			},
 			{
-				Level: "500,001-1,000,000",
-				Tax:   0.0,
+				Level:         "500,001-1,000,000",
+				MinIncome:     money.FromBaht(500000),
+				MaxIncome:     mockMaxIncome(1000000),
+				Rate:          money.FromPercent(15),
+				TaxableIncome: money.FromBaht(0),
+				BracketTax:    money.FromBaht(0),
+				CumulativeTax: money.FromBaht(14000),
+				Tax:           money.FromBaht(0),
 			},
 			{
-				Level: "1,000,001-2,000,000",
-				Tax:   0.0,
+				Level:         "1,000,001-2,000,000",
+				MinIncome:     money.FromBaht(1000000),
+				MaxIncome:     mockMaxIncome(2000000),
+				Rate:          money.FromPercent(20),
+				TaxableIncome: money.FromBaht(0),
+				BracketTax:    money.FromBaht(0),
+				CumulativeTax: money.FromBaht(14000),
+				Tax:           money.FromBaht(0),
 			},
 			{
-				Level: "2,000,001 ขึ้นไป",
-				Tax:   0.0,
+				Level:         "2,000,001 ขึ้นไป",
+				MinIncome:     money.FromBaht(2000000),
+				MaxIncome:     nil,
+				Rate:          money.FromPercent(35),
+				TaxableIncome: money.FromBaht(0),
+				BracketTax:    money.FromBaht(0),
+				CumulativeTax: money.FromBaht(14000),
+				Tax:           money.FromBaht(0),
 			},
 		},
+		EffectiveTaxRate:    money.Rate(280),
+		EffectiveNetTaxRate: money.Rate(482),
+		MarginalTaxRate:     money.FromPercent(10),
+		NextBracketDistance: mockNextBracketDistance(210000),
+		TaxMethod:           taxMethod.Progressive,
+		ProgressiveTax:      money.FromBaht(14000),
+		MinimumTax:          money.FromBaht(0),
 	}, nil
 }
 
-func (m *mockTaxCalculatorUsecase) CalculateMultiRequest(reqs []TaxCalculatorReq) (TaxCalucalorMultipleRes, error) {
-	return TaxCalucalorMultipleRes{}, nil
-}
-
 func mockCalculateTaxHttpReq(reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
 	e := echo.New()
 
@@ -146,6 +194,15 @@ func TestCalculateTaxHandler_ShouldGetBadRequest_WhenInvalidInput(t *testing.T)
 			"Test case 3",
 			``,
 		},
+		{
+			"Test case 4",
+			`{
+				"taxYear": 67,
+				"totalIncome": 500000.0,
+				"wht": 0.0,
+				"allowances": []
+			}`,
+		},
 		{
 			"Test case 3",
 			`{
@@ -162,6 +219,94 @@ func TestCalculateTaxHandler_ShouldGetBadRequest_WhenInvalidInput(t *testing.T)
 				]
 			}`,
 		},
+		{
+			"Test case 5",
+			`{
+				"totalIncome": 500000.0,
+				"wht": 0.0,
+				"allowances": [],
+				"dependents": {
+					"children": [{"birthYear": 0}]
+				}
+			}`,
+		},
+		{
+			"Test case 6",
+			`{
+				"totalIncome": 500000.0,
+				"wht": 0.0,
+				"allowances": [],
+				"dependents": {
+					"parents": [{"age": 60}, {"age": 61}, {"age": 62}, {"age": 63}, {"age": 64}]
+				}
+			}`,
+		},
+		{
+			"Test case 7",
+			`{
+				"totalIncome": 500000.0,
+				"wht": 0.0,
+				"allowances": [],
+				"dependents": {
+					"spouse": {"income": -1},
+					"disabledDependents": -1
+				}
+			}`,
+		},
+		{
+			"Test case 8",
+			`{
+				"totalIncome": 0.0,
+				"wht": 0.0,
+				"allowances": [],
+				"incomes": [{"incomeType": "40(9)", "amount": 100000.0}]
+			}`,
+		},
+		{
+			"Test case 9",
+			`{
+				"totalIncome": 0.0,
+				"wht": 0.0,
+				"allowances": [],
+				"incomes": [{"incomeType": "40(1)", "amount": 100000.0, "actualExpense": 10000.0}]
+			}`,
+		},
+		{
+			"Test case 10",
+			`{
+				"totalIncome": 0.0,
+				"wht": 0.0,
+				"allowances": [],
+				"incomes": [{"incomeType": "40(5)", "amount": -1}]
+			}`,
+		},
+		{
+			"Test case 11",
+			`{
+				"totalIncome": 0.0,
+				"wht": 0.0,
+				"allowances": [],
+				"interest": {"amount": 100000.0, "taxOption": "split"}
+			}`,
+		},
+		{
+			"Test case 12",
+			`{
+				"totalIncome": 0.0,
+				"wht": 0.0,
+				"allowances": [],
+				"dividend": {"amount": 100000.0, "taxOption": "include", "corporateTaxRate": 100}
+			}`,
+		},
+		{
+			"Test case 13",
+			`{
+				"totalIncome": 500000.0,
+				"wht": 0.0,
+				"allowances": [],
+				"dependents": {"disabledDependents": 101}
+			}`,
+		},
 	}
 
 	// Act
@@ -180,30 +325,39 @@ func TestCalculateTaxHandler_ShouldGetBadRequest_WhenInvalidInput(t *testing.T)
 }
 
 type mockTaxCalculatorUsecaseCaseErrorOnCalculate struct {
+	err error
 }
 
-func (m *mockTaxCalculatorUsecaseCaseErrorOnCalculate) CalculateAllowances(allowances []AllowanceReq, maxDonation float64, maxKReceipt float64) float64 {
-	return 0.0
+func (m *mockTaxCalculatorUsecaseCaseErrorOnCalculate) CalculateTaxDeduction(personalDeduction, totalAllowances money.Money) money.Money {
+	return money.FromBaht(0)
 }
 
-func (m *mockTaxCalculatorUsecaseCaseErrorOnCalculate) CalculateTaxDeduction(personalDeduction, totalAllowances float64) float64 {
-	return 0.0
+func (m *mockTaxCalculatorUsecaseCaseErrorOnCalculate) CalculateNetIncome(income, taxDeduction money.Money) money.Money {
+	return money.FromBaht(0)
 }
 
-func (m *mockTaxCalculatorUsecaseCaseErrorOnCalculate) CalculateNetIncome(income, taxDeduction float64) float64 {
-	return 0.0
+func (m *mockTaxCalculatorUsecaseCaseErrorOnCalculate) CalculateTax(netIncome money.Money, wht money.Money, taxBrackets []taxBracket.TaxBracket) (money.Money, money.Money, []TaxLevelRes, error) {
+	return money.FromBaht(0), money.FromBaht(0), []TaxLevelRes{}, nil
 }
 
-func (m *mockTaxCalculatorUsecaseCaseErrorOnCalculate) CalculateTax(netIncome float64, wht float64) (float64, float64, []TaxLevelRes) {
-	return 0.0, 0.0, []TaxLevelRes{}
+func (m *mockTaxCalculatorUsecaseCaseErrorOnCalculate) CalculateMinimumTax(incomes []IncomeReq) (money.Money, error) {
+	return money.FromBaht(0), nil
 }
 
-func (m *mockTaxCalculatorUsecaseCaseErrorOnCalculate) Calculate(req TaxCalculatorReq) (TaxCalculatorRes, error) {
-	return TaxCalculatorRes{}, errors.New("error on calculaate")
+func (m *mockTaxCalculatorUsecaseCaseErrorOnCalculate) GetTaxSetting(year int) (TaxSetting, error) {
+	return TaxSetting{}, nil
 }
 
-func (m *mockTaxCalculatorUsecaseCaseErrorOnCalculate) CalculateMultiRequest(reqs []TaxCalculatorReq) (TaxCalucalorMultipleRes, error) {
-	return TaxCalucalorMultipleRes{}, nil
+func (m *mockTaxCalculatorUsecaseCaseErrorOnCalculate) CalculateWithSetting(req TaxCalculatorReq, setting TaxSetting) (TaxCalculatorRes, error) {
+	return TaxCalculatorRes{}, nil
+}
+
+func (m *mockTaxCalculatorUsecaseCaseErrorOnCalculate) Calculate(req TaxCalculatorReq) (TaxCalculatorRes, error) {
+	if m.err != nil {
+		return TaxCalculatorRes{}, m.err
+	}
+
+	return TaxCalculatorRes{}, errors.New("error on calculaate")
 }
 
 func TestCalculateTaxHandler_ShouldGetInternalServerError_WhenInvalidInput(t *testing.T) {
@@ -251,6 +405,61 @@ func TestCalculateTaxHandler_ShouldGetInternalServerError_WhenInvalidInput(t *te
 	}
 }
 
+func TestCalculateTaxHandler_ShouldGetBadRequest_WhenTaxYearNotSupported(t *testing.T) {
+	// Arrange
+	usecase := &mockTaxCalculatorUsecaseCaseErrorOnCalculate{
+		err: ErrTaxYearNotSupported,
+	}
+	handler := NewTaxCalculatorHttpHandler(
+		usecase,
+	)
+
+	reqBody := `{
+		"taxYear": 2500,
+		"totalIncome": 500000.0,
+		"wht": 0.0,
+		"allowances": []
+	}`
+
+	_, c, _ := mockCalculateTaxHttpReq(reqBody)
+
+	// Act
+	err := handler.CalculateTax(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+}
+
+func TestCalculateTaxHandler_ShouldGetBadRequest_WhenAmountTooLarge(t *testing.T) {
+	// Arrange
+	usecase := &mockTaxCalculatorUsecaseCaseErrorOnCalculate{
+		err: money.ErrOverflow,
+	}
+	handler := NewTaxCalculatorHttpHandler(
+		usecase,
+	)
+
+	reqBody := `{
+		"totalIncome": 500000.0,
+		"wht": 0.0,
+		"allowances": []
+	}`
+
+	_, c, _ := mockCalculateTaxHttpReq(reqBody)
+
+	// Act
+	err := handler.CalculateTax(c)
+
+	// Assert
+	assert.Error(t, err)
+	he, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+}
+
 func TestCalculateTaxHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
 	// Arrange
 	usecase := &mockTaxCalculatorUsecase{}
@@ -282,25 +491,62 @@ func TestCalculateTaxHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
 			`{
 				"tax": 14000,
 				"taxRefund": 0,
+				"effectiveTaxRate": 2.80,
+				"effectiveNetTaxRate": 4.82,
+				"marginalTaxRate": 10,
+				"nextBracketDistance": 210000,
+				"taxMethod": "progressive",
+				"progressiveTax": 14000,
+				"minimumTax": 0,
 				"taxLevel": [
 					{
 						"level": "0-150,000",
+						"minIncome": 0,
+						"maxIncome": 150000,
+						"rate": 0,
+						"taxableIncome": 150000,
+						"bracketTax": 0,
+						"cumulativeTax": 0,
 						"tax": 0
 					},
 					{
 						"level": "150,001-500,000",
+						"minIncome": 150000,
+						"maxIncome": 500000,
+						"rate": 10,
+						"taxableIncome": 140000,
+						"bracketTax": 14000,
+						"cumulativeTax": 14000,
 						"tax": 14000
 					},
 					{
 						"level": "500,001-1,000,000",
+						"minIncome": 500000,
+						"maxIncome": 1000000,
+						"rate": 15,
+						"taxableIncome": 0,
+						"bracketTax": 0,
+						"cumulativeTax": 14000,
 						"tax": 0
 					},
 					{
 						"level": "1,000,001-2,000,000",
+						"minIncome": 1000000,
+						"maxIncome": 2000000,
+						"rate": 20,
+						"taxableIncome": 0,
+						"bracketTax": 0,
+						"cumulativeTax": 14000,
 						"tax": 0
 					},
 					{
 						"level": "2,000,001 ขึ้นไป",
+						"minIncome": 2000000,
+						"maxIncome": null,
+						"rate": 35,
+						"taxableIncome": 0,
+						"bracketTax": 0,
+						"cumulativeTax": 14000,
 						"tax": 0
 					}
 				]
@@ -322,51 +568,296 @@ func TestCalculateTaxHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
 	}
 }
 
-type mockTaxCalculatorMultiRequestUsecase struct {
+type mockTaxCalculatorUsecaseCaseExplanation struct {
 }
 
-func (m *mockTaxCalculatorMultiRequestUsecase) CalculateAllowances(allowances []AllowanceReq, maxDonation float64, maxKReceipt float64) float64 {
-	return 0.0
+func (m *mockTaxCalculatorUsecaseCaseExplanation) CalculateTaxDeduction(personalDeduction, totalAllowances money.Money) money.Money {
+	return money.FromBaht(0)
 }
 
-func (m *mockTaxCalculatorMultiRequestUsecase) CalculateTaxDeduction(personalDeduction, totalAllowances float64) float64 {
-	return 0.0
+func (m *mockTaxCalculatorUsecaseCaseExplanation) CalculateNetIncome(income, taxDeduction money.Money) money.Money {
+	return money.FromBaht(0)
 }
 
-func (m *mockTaxCalculatorMultiRequestUsecase) CalculateNetIncome(income, taxDeduction float64) float64 {
-	return 0.0
+func (m *mockTaxCalculatorUsecaseCaseExplanation) CalculateTax(netIncome money.Money, wht money.Money, taxBrackets []taxBracket.TaxBracket) (money.Money, money.Money, []TaxLevelRes, error) {
+	return money.FromBaht(0), money.FromBaht(0), []TaxLevelRes{}, nil
 }
 
-func (m *mockTaxCalculatorMultiRequestUsecase) CalculateTax(netIncome float64, wht float64) (float64, float64, []TaxLevelRes) {
-	return 0.0, 0.0, []TaxLevelRes{}
+func (m *mockTaxCalculatorUsecaseCaseExplanation) CalculateMinimumTax(incomes []IncomeReq) (money.Money, error) {
+	return money.FromBaht(0), nil
 }
 
-func (m *mockTaxCalculatorMultiRequestUsecase) Calculate(req TaxCalculatorReq) (TaxCalculatorRes, error) {
+func (m *mockTaxCalculatorUsecaseCaseExplanation) GetTaxSetting(year int) (TaxSetting, error) {
+	return TaxSetting{}, nil
+}
+
+func (m *mockTaxCalculatorUsecaseCaseExplanation) CalculateWithSetting(req TaxCalculatorReq, setting TaxSetting) (TaxCalculatorRes, error) {
 	return TaxCalculatorRes{}, nil
 }
 
-func (m *mockTaxCalculatorMultiRequestUsecase) CalculateMultiRequest(reqs []TaxCalculatorReq) (TaxCalucalorMultipleRes, error) {
-	return TaxCalucalorMultipleRes{
-		Taxes: []TaxCalucalorMultipleDetailRes{
+func (m *mockTaxCalculatorUsecaseCaseExplanation) Calculate(req TaxCalculatorReq) (TaxCalculatorRes, error) {
+	return TaxCalculatorRes{
+		Tax:       money.FromBaht(0),
+		TaxRefund: money.FromBaht(0),
+		TaxLevel: []TaxLevelRes{
 			{
-				TotalIncome: 500000.0,
-				Tax:         29000.0,
-				TaxRefund:   0.0,
+				Level:         "0-150,000",
+				MinIncome:     money.FromBaht(0),
+				MaxIncome:     mockMaxIncome(150000),
+				Rate:          money.FromPercent(0),
+				TaxableIncome: money.FromBaht(0),
+				BracketTax:    money.FromBaht(0),
+				CumulativeTax: money.FromBaht(0),
+				Tax:           money.FromBaht(0),
 			},
-			{
-				TotalIncome: 600000.0,
-				Tax:         0.0,
-				TaxRefund:   2000.0,
+		},
+		EffectiveTaxRate:    money.Rate(0),
+		EffectiveNetTaxRate: money.Rate(0),
+		MarginalTaxRate:     money.FromPercent(0),
+		NextBracketDistance: mockNextBracketDistance(150000),
+		TaxMethod:           taxMethod.Progressive,
+		ProgressiveTax:      money.FromBaht(0),
+		MinimumTax:          money.FromBaht(0),
+		Explanation: &TaxExplanationRes{
+			TotalIncome:   money.FromBaht(100000),
+			Incomes:       []IncomeExplanationRes{},
+			TotalExpenses: money.FromBaht(0),
+			Allowances: []AllowanceExplanationRes{
+				{
+					AllowanceType: "donation",
+					Amount:        money.FromBaht(150000),
+					MaxDeduction:  money.FromBaht(100000),
+					Deduction:     money.FromBaht(100000),
+					CutOff:        money.FromBaht(50000),
+				},
 			},
-			{
-				TotalIncome: 750000.0,
-				Tax:         11250.0,
-				TaxRefund:   0.0,
+			AllowanceGroups:   []AllowanceGroupExplanationRes{},
+			TotalAllowances:   money.FromBaht(100000),
+			PersonalDeduction: money.FromBaht(60000),
+			Dependents:        []DependentExplanationRes{},
+			TotalDeduction:    money.FromBaht(160000),
+			NetIncome:         money.FromBaht(0),
+			TaxLevel: []TaxLevelRes{
+				{
+					Level:         "0-150,000",
+					MinIncome:     money.FromBaht(0),
+					MaxIncome:     mockMaxIncome(150000),
+					Rate:          money.FromPercent(0),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(0),
+					Tax:           money.FromBaht(0),
+				},
 			},
+			MarginalLevel:   "0-150,000",
+			NonSalaryIncome: money.FromBaht(0),
+			TotalTax:        money.FromBaht(0),
+			WHT:             money.FromBaht(0),
+			TaxCredits:      money.FromBaht(0),
+			Tax:             money.FromBaht(0),
+			TaxRefund:       money.FromBaht(0),
+		},
+	}, nil
+}
+
+func mockCalculateTaxWithQueryHttpReq(query string, reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	req := httptest.NewRequest(http.MethodPost, "/tax/calculations?"+query, strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+func TestCalculateTaxHandler_ShouldReturnExplanation_WhenExplainIsTrue(t *testing.T) {
+	// Arrange
+	handler := NewTaxCalculatorHttpHandler(&mockTaxCalculatorUsecaseCaseExplanation{})
+	reqBody := `{"totalIncome": 100000.0, "wht": 0.0, "allowances": [{"allowanceType": "donation", "amount": 150000.0}]}`
+
+	testCases := []struct {
+		name             string
+		query            string
+		expectedResponse string
+	}{
+		{
+			"Test case 1",
+			"explain=true",
+			`{
+				"tax": 0,
+				"taxRefund": 0,
+				"taxLevel": [{"level": "0-150,000", "minIncome": 0, "maxIncome": 150000, "rate": 0, "taxableIncome": 0, "bracketTax": 0, "cumulativeTax": 0, "tax": 0}],
+				"effectiveTaxRate": 0,
+				"effectiveNetTaxRate": 0,
+				"marginalTaxRate": 0,
+				"nextBracketDistance": 150000,
+				"taxMethod": "progressive",
+				"progressiveTax": 0,
+				"minimumTax": 0,
+				"explanation": {
+					"totalIncome": 100000,
+					"incomes": [],
+					"totalExpenses": 0,
+					"allowances": [
+						{"allowanceType": "donation", "amount": 150000, "maxDeduction": 100000, "deduction": 100000, "cutOff": 50000}
+					],
+					"allowanceGroups": [],
+					"totalAllowances": 100000,
+					"personalDeduction": 60000,
+					"dependents": [],
+					"totalDependents": 0,
+					"totalDeduction": 160000,
+					"netIncome": 0,
+					"taxLevel": [
+						{"level": "0-150,000", "minIncome": 0, "maxIncome": 150000, "rate": 0, "taxableIncome": 0, "bracketTax": 0, "cumulativeTax": 0, "tax": 0}
+					],
+					"marginalLevel": "0-150,000",
+					"nonSalaryIncome": 0,
+					"totalTax": 0,
+					"wht": 0,
+					"taxCredits": 0,
+					"tax": 0,
+					"taxRefund": 0
+				}
+			}`,
+		},
+		{
+			"Test case 2",
+			"",
+			`{"tax": 0, "taxRefund": 0, "taxLevel": [{"level": "0-150,000", "minIncome": 0, "maxIncome": 150000, "rate": 0, "taxableIncome": 0, "bracketTax": 0, "cumulativeTax": 0, "tax": 0}], "effectiveTaxRate": 0, "effectiveNetTaxRate": 0, "marginalTaxRate": 0, "nextBracketDistance": 150000, "taxMethod": "progressive", "progressiveTax": 0, "minimumTax": 0}`,
+		},
+		{
+			"Test case 3",
+			"explain=false",
+			`{"tax": 0, "taxRefund": 0, "taxLevel": [{"level": "0-150,000", "minIncome": 0, "maxIncome": 150000, "rate": 0, "taxableIncome": 0, "bracketTax": 0, "cumulativeTax": 0, "tax": 0}], "effectiveTaxRate": 0, "effectiveNetTaxRate": 0, "marginalTaxRate": 0, "nextBracketDistance": 150000, "taxMethod": "progressive", "progressiveTax": 0, "minimumTax": 0}`,
+		},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, rec := mockCalculateTaxWithQueryHttpReq(tc.query, reqBody)
+			err := handler.CalculateTax(c)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+			assert.JSONEq(t, tc.expectedResponse, rec.Body.String())
+		})
+	}
+}
+
+func TestCalculateTaxHandler_ShouldGetBadRequest_WhenInvalidExplain(t *testing.T) {
+	// Arrange
+	handler := NewTaxCalculatorHttpHandler(&mockTaxCalculatorUsecaseCaseExplanation{})
+	reqBody := `{"totalIncome": 100000.0, "wht": 0.0, "allowances": [{"allowanceType": "donation", "amount": 150000.0}]}`
+	_, c, _ := mockCalculateTaxWithQueryHttpReq("explain=abc", reqBody)
+
+	// Act
+	err := handler.CalculateTax(c)
+
+	// Assert
+	he, ok := err.(*echo.HTTPError)
+
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, he.Code)
+}
+
+type mockTaxCalculatorMultiRequestUsecase struct {
+	calculatedReqs []TaxCalculatorReq
+}
+
+func (m *mockTaxCalculatorMultiRequestUsecase) CalculateTaxDeduction(personalDeduction, totalAllowances money.Money) money.Money {
+	return money.FromBaht(0)
+}
+
+func (m *mockTaxCalculatorMultiRequestUsecase) CalculateNetIncome(income, taxDeduction money.Money) money.Money {
+	return money.FromBaht(0)
+}
+
+func (m *mockTaxCalculatorMultiRequestUsecase) CalculateTax(netIncome money.Money, wht money.Money, taxBrackets []taxBracket.TaxBracket) (money.Money, money.Money, []TaxLevelRes, error) {
+	return money.FromBaht(0), money.FromBaht(0), []TaxLevelRes{}, nil
+}
+
+func (m *mockTaxCalculatorMultiRequestUsecase) CalculateMinimumTax(incomes []IncomeReq) (money.Money, error) {
+	return money.FromBaht(0), nil
+}
+
+func (m *mockTaxCalculatorMultiRequestUsecase) GetTaxSetting(year int) (TaxSetting, error) {
+	return TaxSetting{
+		PersonalDeduction: money.FromBaht(60000),
+		TaxBrackets: []taxBracket.TaxBracket{
+			{Level: 1, MinIncome: money.FromBaht(0), MaxIncome: mockNextBracketDistance(150000), Rate: money.FromPercent(0)},
+			{Level: 2, MinIncome: money.FromBaht(150000), Rate: money.FromPercent(10)},
 		},
 	}, nil
 }
 
+func (m *mockTaxCalculatorMultiRequestUsecase) CalculateWithSetting(req TaxCalculatorReq, setting TaxSetting) (TaxCalculatorRes, error) {
+	m.calculatedReqs = append(m.calculatedReqs, req)
+
+	for _, detail := range mockTaxCalculatorMultiRequestTaxes {
+		if detail.TotalIncome == req.TotalIncome {
+			netIncome := req.TotalIncome - setting.PersonalDeduction
+			upperIncome := money.Max(netIncome-money.FromBaht(150000), 0)
+
+			return TaxCalculatorRes{
+				Tax:       detail.Tax,
+				TaxRefund: detail.TaxRefund,
+				TaxLevel: []TaxLevelRes{
+					{TaxableIncome: money.Min(netIncome, money.FromBaht(150000))},
+					{TaxableIncome: upperIncome, BracketTax: upperIncome / 10},
+				},
+				EffectiveTaxRate:    detail.EffectiveTaxRate,
+				EffectiveNetTaxRate: detail.EffectiveNetTaxRate,
+				MarginalTaxRate:     detail.MarginalTaxRate,
+				NextBracketDistance: detail.NextBracketDistance,
+				Explanation:         &TaxExplanationRes{NetIncome: netIncome},
+			}, nil
+		}
+	}
+
+	return TaxCalculatorRes{}, nil
+}
+
+func (m *mockTaxCalculatorMultiRequestUsecase) Calculate(req TaxCalculatorReq) (TaxCalculatorRes, error) {
+	return TaxCalculatorRes{}, nil
+}
+
+// mockTaxCalculatorMultiRequestTaxes are the results CalculateWithSetting gives by total income
+var mockTaxCalculatorMultiRequestTaxes = []TaxCalucalorMultipleDetailRes{
+	{
+		TotalIncome:         money.FromBaht(500000),
+		Tax:                 money.FromBaht(29000),
+		TaxRefund:           money.FromBaht(0),
+		EffectiveTaxRate:    money.Rate(580),
+		EffectiveNetTaxRate: money.Rate(659),
+		MarginalTaxRate:     money.FromPercent(10),
+		NextBracketDistance: mockNextBracketDistance(60000),
+	},
+	{
+		TotalIncome:         money.FromBaht(600000),
+		Tax:                 money.FromBaht(0),
+		TaxRefund:           money.FromBaht(2000),
+		EffectiveTaxRate:    money.Rate(633),
+		EffectiveNetTaxRate: money.Rate(730),
+		MarginalTaxRate:     money.FromPercent(15),
+		NextBracketDistance: mockNextBracketDistance(480000),
+	},
+	{
+		TotalIncome:         money.FromBaht(750000),
+		Tax:                 money.FromBaht(11250),
+		TaxRefund:           money.FromBaht(0),
+		EffectiveTaxRate:    money.Rate(816),
+		EffectiveNetTaxRate: money.Rate(907),
+		MarginalTaxRate:     money.FromPercent(15),
+		NextBracketDistance: mockNextBracketDistance(325000),
+	},
+}
+
 // Test CalculateTaxWithCSV method
 
 func mockCalculateTaxWithCSVHttpReq(csvData string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
@@ -394,6 +885,30 @@ func mockCalculateTaxWithCSVHttpReq(csvData string) (*echo.Echo, echo.Context, *
 	return e, c, rec
 }
 
+func mockCalculateTaxWithCSVAndTaxYearHttpReq(csvData string, taxYear string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	var buf bytes.Buffer
+	multipartWriter := multipart.NewWriter(&buf)
+
+	contentType := multipartWriter.FormDataContentType()
+	req := httptest.NewRequest(http.MethodPost, "/calculate-tax", &buf)
+	req.Header.Set(echo.HeaderContentType, contentType)
+
+	filePart, _ := multipartWriter.CreateFormFile("taxFile", "taxFile.txt")
+	filePart.Write([]byte(csvData))
+	multipartWriter.WriteField("taxYear", taxYear)
+
+	multipartWriter.Close()
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
 func mockCalculateTaxWithCSVFormFileErrorHttpReq(csvData string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
 	e := echo.New()
 
@@ -510,31 +1025,66 @@ a,40000,20000
 	}
 }
 
+func TestCalculateTaxWithCSV_ShouldGetBadRequest_WhenInvalidTaxYear(t *testing.T) {
+	// Arrange
+	usecase := &mockTaxCalculatorMultiRequestUsecase{}
+	handler := NewTaxCalculatorHttpHandler(
+		usecase,
+	)
+
+	testCases := []struct {
+		name    string
+		taxYear string
+	}{
+		{"Test case 1", "abc"},
+		{"Test case 2", "67"},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, _ := mockCalculateTaxWithCSVAndTaxYearHttpReq(`totalIncome,wht,donation
+500000,0,0`, tc.taxYear)
+			err := handler.CalculateTaxWithCSV(c)
+
+			// Assert
+			assert.Error(t, err)
+			he, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
 type mockTaxCalculatorMultiRequestUsecaseCaseErrorOnCalculate struct {
 }
 
-func (m *mockTaxCalculatorMultiRequestUsecaseCaseErrorOnCalculate) CalculateAllowances(allowances []AllowanceReq, maxDonation float64, maxKReceipt float64) float64 {
-	return 0.0
+func (m *mockTaxCalculatorMultiRequestUsecaseCaseErrorOnCalculate) CalculateTaxDeduction(personalDeduction, totalAllowances money.Money) money.Money {
+	return money.FromBaht(0)
 }
 
-func (m *mockTaxCalculatorMultiRequestUsecaseCaseErrorOnCalculate) CalculateTaxDeduction(personalDeduction, totalAllowances float64) float64 {
-	return 0.0
+func (m *mockTaxCalculatorMultiRequestUsecaseCaseErrorOnCalculate) CalculateNetIncome(income, taxDeduction money.Money) money.Money {
+	return money.FromBaht(0)
 }
 
-func (m *mockTaxCalculatorMultiRequestUsecaseCaseErrorOnCalculate) CalculateNetIncome(income, taxDeduction float64) float64 {
-	return 0.0
+func (m *mockTaxCalculatorMultiRequestUsecaseCaseErrorOnCalculate) CalculateTax(netIncome money.Money, wht money.Money, taxBrackets []taxBracket.TaxBracket) (money.Money, money.Money, []TaxLevelRes, error) {
+	return money.FromBaht(0), money.FromBaht(0), []TaxLevelRes{}, nil
 }
 
-func (m *mockTaxCalculatorMultiRequestUsecaseCaseErrorOnCalculate) CalculateTax(netIncome float64, wht float64) (float64, float64, []TaxLevelRes) {
-	return 0.0, 0.0, []TaxLevelRes{}
+func (m *mockTaxCalculatorMultiRequestUsecaseCaseErrorOnCalculate) CalculateMinimumTax(incomes []IncomeReq) (money.Money, error) {
+	return money.FromBaht(0), nil
 }
 
-func (m *mockTaxCalculatorMultiRequestUsecaseCaseErrorOnCalculate) Calculate(req TaxCalculatorReq) (TaxCalculatorRes, error) {
+func (m *mockTaxCalculatorMultiRequestUsecaseCaseErrorOnCalculate) GetTaxSetting(year int) (TaxSetting, error) {
+	return TaxSetting{}, errors.New("Error on get tax setting")
+}
+
+func (m *mockTaxCalculatorMultiRequestUsecaseCaseErrorOnCalculate) CalculateWithSetting(req TaxCalculatorReq, setting TaxSetting) (TaxCalculatorRes, error) {
 	return TaxCalculatorRes{}, nil
 }
 
-func (m *mockTaxCalculatorMultiRequestUsecaseCaseErrorOnCalculate) CalculateMultiRequest(reqs []TaxCalculatorReq) (TaxCalucalorMultipleRes, error) {
-	return TaxCalucalorMultipleRes{}, errors.New("Error on calculate multi request")
+func (m *mockTaxCalculatorMultiRequestUsecaseCaseErrorOnCalculate) Calculate(req TaxCalculatorReq) (TaxCalculatorRes, error) {
+	return TaxCalculatorRes{}, nil
 }
 
 func TestCalculateTaxWithCSV_ShouldGetInternalServerError_WhenInvalidInput(t *testing.T) {
@@ -595,17 +1145,29 @@ func TestCalculateTaxWithCSV_ShouldSuccess_WhenCorrectInput(t *testing.T) {
 				{
 					"totalIncome": 500000,
 					"tax": 29000,
-					"taxRefund": 0
+					"taxRefund": 0,
+					"effectiveTaxRate": 5.80,
+					"effectiveNetTaxRate": 6.59,
+					"marginalTaxRate": 10,
+					"nextBracketDistance": 60000
 				},
 				{
 					"totalIncome": 600000,
 					"tax": 0,
-					"taxRefund": 2000
+					"taxRefund": 2000,
+					"effectiveTaxRate": 6.33,
+					"effectiveNetTaxRate": 7.30,
+					"marginalTaxRate": 15,
+					"nextBracketDistance": 480000
 				},
 				{
 					"totalIncome": 750000,
 					"tax": 11250,
-					"taxRefund": 0
+					"taxRefund": 0,
+					"effectiveTaxRate": 8.16,
+					"effectiveNetTaxRate": 9.07,
+					"marginalTaxRate": 15,
+					"nextBracketDistance": 325000
 				}
 				]
 			}`,
@@ -625,3 +1187,413 @@ func TestCalculateTaxWithCSV_ShouldSuccess_WhenCorrectInput(t *testing.T) {
 		})
 	}
 }
+
+func mockCalculateTaxWithCSVAndQueryHttpReq(query string, csvData string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	var buf bytes.Buffer
+	multipartWriter := multipart.NewWriter(&buf)
+
+	contentType := multipartWriter.FormDataContentType()
+	req := httptest.NewRequest(http.MethodPost, "/calculate-tax?"+query, &buf)
+	req.Header.Set(echo.HeaderContentType, contentType)
+
+	filePart, _ := multipartWriter.CreateFormFile("taxFile", "taxFile.txt")
+	filePart.Write([]byte(csvData))
+
+	multipartWriter.Close()
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+func TestCalculateTaxWithCSV_ShouldReturnCSV_WhenFormatIsCSV(t *testing.T) {
+	// Arrange
+	handler := NewTaxCalculatorHttpHandler(&mockTaxCalculatorMultiRequestUsecase{})
+	_, c, rec := mockCalculateTaxWithCSVAndQueryHttpReq("format=csv", `totalIncome,wht,donation
+500000,0,0
+600000,40000,20000
+750000,50000,15000`)
+
+	// Act
+	err := handler.CalculateTaxWithCSV(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.Equal(t, "text/csv; charset=UTF-8", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `attachment; filename=taxFile-taxes.csv`, rec.Header().Get(echo.HeaderContentDisposition))
+	assert.Equal(t, `totalIncome,wht,donation,tax,taxRefund,netIncome,effectiveTaxRate,effectiveNetTaxRate,marginalTaxRate,nextBracketDistance,"taxableIncome 0-150,000","bracketTax 0-150,000","taxableIncome 150,001 ขึ้นไป","bracketTax 150,001 ขึ้นไป"
+500000,0,0,29000.00,0.00,440000.00,5.80,6.59,10.00,60000.00,150000.00,0.00,290000.00,29000.00
+600000,40000,20000,0.00,2000.00,540000.00,6.33,7.30,15.00,480000.00,150000.00,0.00,390000.00,39000.00
+750000,50000,15000,11250.00,0.00,690000.00,8.16,9.07,15.00,325000.00,150000.00,0.00,540000.00,54000.00
+`, rec.Body.String())
+}
+
+func TestCalculateTaxWithCSV_ShouldGetBadRequest_WhenInvalidFormatOrMode(t *testing.T) {
+	// Arrange
+	handler := NewTaxCalculatorHttpHandler(&mockTaxCalculatorMultiRequestUsecase{})
+
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{"Test case 1", "format=pdf"},
+		{"Test case 2", "mode=loose"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, rec := mockCalculateTaxWithCSVAndQueryHttpReq(tc.query, `totalIncome,wht,donation
+500000,0,0`)
+
+			// Act
+			err := handler.CalculateTaxWithCSV(c)
+
+			// Assert
+			he, ok := err.(*echo.HTTPError)
+
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+			assert.Empty(t, rec.Body.String())
+		})
+	}
+}
+
+func TestCalculateTaxWithCSV_ShouldStreamEveryRow_WhenFileHasManyRows(t *testing.T) {
+	// Arrange
+	handler := NewTaxCalculatorHttpHandler(&mockTaxCalculatorMultiRequestUsecase{})
+	rows := 2500
+	csvData := "totalIncome,wht,donation\n" + strings.Repeat("500000,0,0\n", rows)
+	_, c, rec := mockCalculateTaxWithCSVHttpReq(csvData)
+
+	// Act
+	err := handler.CalculateTaxWithCSV(c)
+
+	// Assert
+	var res TaxCalucalorMultipleRes
+
+	assert.NoError(t, err)
+	assert.True(t, rec.Flushed)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Len(t, res.Taxes, rows)
+	assert.Equal(t, money.FromBaht(29000), res.Taxes[rows-1].Tax)
+}
+
+func TestCalculateTaxWithCSV_ShouldReturnEmptyTaxes_WhenFileHasOnlyHeader(t *testing.T) {
+	// Arrange
+	handler := NewTaxCalculatorHttpHandler(&mockTaxCalculatorMultiRequestUsecase{})
+	_, c, rec := mockCalculateTaxWithCSVHttpReq("totalIncome,wht,donation\n")
+
+	// Act
+	err := handler.CalculateTaxWithCSV(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"taxes": []}`, rec.Body.String())
+}
+
+func TestCalculateTaxWithCSV_ShouldReportRowError_WhenStrictModeHasBadRow(t *testing.T) {
+	// Arrange
+	handler := NewTaxCalculatorHttpHandler(&mockTaxCalculatorMultiRequestUsecase{})
+
+	testCases := []struct {
+		name     string
+		query    string
+		csv      string
+		expected TaxCSVRowErrorRes
+	}{
+		{"Test case 1", "", "totalIncome,wht,donation\n500000,0,0\n600000,b,20000\n", TaxCSVRowErrorRes{Line: 3, Column: "wht", Value: "b", Message: `invalid amount: "b"`}},
+		{"Test case 2", "mode=strict", "totalIncome,wht,donation\n-1,0,0\n", TaxCSVRowErrorRes{Line: 2, Column: "totalIncome", Value: "-1", Message: "failed on the 'gte=0' rule"}},
+		{"Test case 3", "mode=strict", "totalIncome,wht,donation\n500000,0,0\n600000,0\n", TaxCSVRowErrorRes{Line: 3, Message: "wrong number of fields"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, rec := mockCalculateTaxWithCSVAndQueryHttpReq(tc.query, tc.csv)
+
+			// Act
+			err := handler.CalculateTaxWithCSV(c)
+
+			// Assert
+			he, ok := err.(*echo.HTTPError)
+
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+			assert.Equal(t, tc.expected, he.Message)
+			assert.Empty(t, rec.Body.String())
+		})
+	}
+}
+
+func TestCalculateTaxWithCSV_ShouldCalculateValidRowsAndReportBadRows_WhenLenientMode(t *testing.T) {
+	// Arrange
+	handler := NewTaxCalculatorHttpHandler(&mockTaxCalculatorMultiRequestUsecase{})
+	csvData := `totalIncome,wht,donation
+500000,0,0
+a,40000,20000
+600000,40000,20000
+600000,40000
+750000,50000,-5
+750000,50000,15000`
+
+	testCases := []struct {
+		name             string
+		query            string
+		expectedResponse string
+	}{
+		{
+			"Test case 1",
+			"mode=lenient",
+			`{
+				"taxes": [
+					{"line": 2, "totalIncome": 500000, "tax": 29000, "taxRefund": 0, "effectiveTaxRate": 5.80, "effectiveNetTaxRate": 6.59, "marginalTaxRate": 10, "nextBracketDistance": 60000},
+					{"line": 4, "totalIncome": 600000, "tax": 0, "taxRefund": 2000, "effectiveTaxRate": 6.33, "effectiveNetTaxRate": 7.30, "marginalTaxRate": 15, "nextBracketDistance": 480000},
+					{"line": 7, "totalIncome": 750000, "tax": 11250, "taxRefund": 0, "effectiveTaxRate": 8.16, "effectiveNetTaxRate": 9.07, "marginalTaxRate": 15, "nextBracketDistance": 325000}
+				],
+				"errors": [
+					{"line": 3, "column": "totalIncome", "value": "a", "message": "invalid amount: \"a\""},
+					{"line": 5, "column": "", "value": "", "message": "wrong number of fields"},
+					{"line": 6, "column": "donation", "value": "-5", "message": "failed on the 'gte=0' rule"}
+				]
+			}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, rec := mockCalculateTaxWithCSVAndQueryHttpReq(tc.query, csvData)
+
+			// Act
+			err := handler.CalculateTaxWithCSV(c)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+			assert.JSONEq(t, tc.expectedResponse, rec.Body.String())
+		})
+	}
+}
+
+func TestCalculateTaxWithCSV_ShouldReturnLineAndErrorColumns_WhenLenientModeAsCSV(t *testing.T) {
+	// Arrange
+	handler := NewTaxCalculatorHttpHandler(&mockTaxCalculatorMultiRequestUsecase{})
+	_, c, rec := mockCalculateTaxWithCSVAndQueryHttpReq("mode=lenient&format=csv", `totalIncome,wht,donation
+500000,0,0
+a,40000,20000
+600000,40000`)
+
+	// Act
+	err := handler.CalculateTaxWithCSV(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, `line,totalIncome,wht,donation,tax,taxRefund,netIncome,effectiveTaxRate,effectiveNetTaxRate,marginalTaxRate,nextBracketDistance,"taxableIncome 0-150,000","bracketTax 0-150,000","taxableIncome 150,001 ขึ้นไป","bracketTax 150,001 ขึ้นไป",errorColumn,errorValue,errorMessage
+2,500000,0,0,29000.00,0.00,440000.00,5.80,6.59,10.00,60000.00,150000.00,0.00,290000.00,29000.00,,,
+3,a,40000,20000,,,,,,,,,,,,totalIncome,a,"invalid amount: ""a"""
+4,600000,40000,,,,,,,,,,,,,,,wrong number of fields
+`, rec.Body.String())
+}
+
+func TestCalculateTaxWithCSV_ShouldPickFormat_WhenAcceptHeader(t *testing.T) {
+	// Arrange
+	handler := NewTaxCalculatorHttpHandler(&mockTaxCalculatorMultiRequestUsecase{})
+
+	testCases := []struct {
+		name                string
+		query               string
+		accept              string
+		expectedContentType string
+	}{
+		{"Test case 1", "", "text/csv", "text/csv; charset=UTF-8"},
+		{"Test case 2", "", "text/csv, application/json;q=0.9", "text/csv; charset=UTF-8"},
+		{"Test case 3", "", xlsx.MIMEType, xlsx.MIMEType},
+		{"Test case 4", "", "application/json", echo.MIMEApplicationJSONCharsetUTF8},
+		{"Test case 5", "", "", echo.MIMEApplicationJSONCharsetUTF8},
+		{"Test case 6", "format=json", "text/csv", echo.MIMEApplicationJSONCharsetUTF8},
+		{"Test case 7", "format=xlsx", "text/csv", xlsx.MIMEType},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, rec := mockCalculateTaxWithCSVAndQueryHttpReq(tc.query, "totalIncome,wht,donation\n500000,0,0\n")
+			c.Request().Header.Set(echo.HeaderAccept, tc.accept)
+
+			// Act
+			err := handler.CalculateTaxWithCSV(c)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+			assert.Equal(t, tc.expectedContentType, rec.Header().Get(echo.HeaderContentType))
+		})
+	}
+}
+
+type mockSheetXML struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// mockReadSheet returns the cells of every row of the sheet by their reference
+func mockReadSheet(t *testing.T, data []byte) []map[string]string {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+
+	part, err := reader.Open("xl/worksheets/sheet1.xml")
+	assert.NoError(t, err)
+
+	defer part.Close()
+
+	var sheet mockSheetXML
+
+	assert.NoError(t, xml.NewDecoder(part).Decode(&sheet))
+
+	rows := []map[string]string{}
+
+	for _, row := range sheet.Rows {
+		cells := map[string]string{}
+
+		for _, cell := range row.Cells {
+			cells[cell.Ref] = cell.Value + cell.Inline
+		}
+
+		rows = append(rows, cells)
+	}
+
+	return rows
+}
+
+func TestCalculateTaxWithCSV_ShouldReturnXLSX_WhenFormatIsXLSX(t *testing.T) {
+	// Arrange
+	handler := NewTaxCalculatorHttpHandler(&mockTaxCalculatorMultiRequestUsecase{})
+	_, c, rec := mockCalculateTaxWithCSVAndQueryHttpReq("format=xlsx&mode=lenient", `totalIncome,wht,donation
+500000,0,0
+a,40000,20000`)
+
+	// Act
+	err := handler.CalculateTaxWithCSV(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.Equal(t, xlsx.MIMEType, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `attachment; filename=taxFile-taxes.xlsx`, rec.Header().Get(echo.HeaderContentDisposition))
+
+	rows := mockReadSheet(t, rec.Body.Bytes())
+
+	assert.Len(t, rows, 3)
+	assert.Equal(t, map[string]string{
+		"A1": "line", "B1": "totalIncome", "C1": "wht", "D1": "donation",
+		"E1": "tax", "F1": "taxRefund", "G1": "netIncome",
+		"H1": "effectiveTaxRate", "I1": "effectiveNetTaxRate", "J1": "marginalTaxRate", "K1": "nextBracketDistance",
+		"L1": "taxableIncome 0-150,000", "M1": "bracketTax 0-150,000",
+		"N1": "taxableIncome 150,001 ขึ้นไป", "O1": "bracketTax 150,001 ขึ้นไป",
+		"P1": "errorColumn", "Q1": "errorValue", "R1": "errorMessage",
+	}, rows[0])
+	assert.Equal(t, map[string]string{
+		"A2": "2", "B2": "500000", "C2": "0", "D2": "0",
+		"E2": "29000.00", "F2": "0.00", "G2": "440000.00",
+		"H2": "5.80", "I2": "6.59", "J2": "10.00", "K2": "60000.00",
+		"L2": "150000.00", "M2": "0.00", "N2": "290000.00", "O2": "29000.00",
+	}, rows[1])
+	assert.Equal(t, map[string]string{
+		"A3": "3", "B3": "a", "C3": "40000", "D3": "20000",
+		"P3": "totalIncome", "Q3": "a", "R3": `invalid amount: "a"`,
+	}, rows[2])
+}
+
+func TestCalculateTaxWithCSV_ShouldMapColumnsByHeader_WhenColumnsInAnyOrder(t *testing.T) {
+	// Arrange
+	usecase := &mockTaxCalculatorMultiRequestUsecase{}
+	handler := NewTaxCalculatorHttpHandler(usecase)
+	_, c, rec := mockCalculateTaxWithCSVHttpReq("\ufeffwht, k-receipt ,totalIncome,donation\n0,50000,500000,\n40000,,600000,20000\n")
+
+	expectedReqs := []TaxCalculatorReq{
+		{
+			TotalIncome: money.FromBaht(500000),
+			WHT:         money.FromBaht(0),
+			Allowances: []AllowanceReq{
+				{AllowanceType: "k-receipt", Amount: money.FromBaht(50000)},
+			},
+		},
+		{
+			TotalIncome: money.FromBaht(600000),
+			WHT:         money.FromBaht(40000),
+			Allowances: []AllowanceReq{
+				{AllowanceType: "donation", Amount: money.FromBaht(20000)},
+			},
+		},
+	}
+
+	// Act
+	err := handler.CalculateTaxWithCSV(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.Equal(t, expectedReqs, usecase.calculatedReqs)
+}
+
+func TestCalculateTaxWithCSV_ShouldGetBadRequest_WhenInvalidHeader(t *testing.T) {
+	// Arrange
+	handler := NewTaxCalculatorHttpHandler(&mockTaxCalculatorMultiRequestUsecase{})
+
+	testCases := []struct {
+		name     string
+		csv      string
+		expected TaxCSVRowErrorRes
+	}{
+		{"Test case 1", "totalIncome,wht,bonus\n500000,0,0\n", TaxCSVRowErrorRes{Line: 1, Column: "bonus", Value: "bonus", Message: "unknown column"}},
+		{"Test case 2", "totalIncome,donation\n500000,0\n", TaxCSVRowErrorRes{Line: 1, Column: "wht", Message: "missing column"}},
+		{"Test case 3", "wht,donation\n0,0\n", TaxCSVRowErrorRes{Line: 1, Column: "totalIncome", Message: "missing column"}},
+		{"Test case 4", "totalIncome,wht,donation,donation\n500000,0,0,0\n", TaxCSVRowErrorRes{Line: 1, Column: "donation", Value: "donation", Message: "duplicate column"}},
+	}
+
+	for _, tc := range testCases {
+		for _, mode := range []string{"strict", "lenient"} {
+			t.Run(tc.name+" "+mode, func(t *testing.T) {
+				_, c, rec := mockCalculateTaxWithCSVAndQueryHttpReq("mode="+mode, tc.csv)
+
+				// Act
+				err := handler.CalculateTaxWithCSV(c)
+
+				// Assert
+				he, ok := err.(*echo.HTTPError)
+
+				assert.True(t, ok)
+				assert.Equal(t, http.StatusBadRequest, he.Code)
+				assert.Equal(t, tc.expected, he.Message)
+				assert.Empty(t, rec.Body.String())
+			})
+		}
+	}
+}
+
+func TestCalculateTaxWithCSV_ShouldReportAllowanceColumn_WhenAllowanceIsInvalid(t *testing.T) {
+	// Arrange
+	handler := NewTaxCalculatorHttpHandler(&mockTaxCalculatorMultiRequestUsecase{})
+	_, c, rec := mockCalculateTaxWithCSVAndQueryHttpReq("mode=lenient", "totalIncome,wht,donation,k-receipt\n500000,0,,-1\n500000,0,x,0\n")
+
+	// Act
+	err := handler.CalculateTaxWithCSV(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"taxes": [],
+		"errors": [
+			{"line": 2, "column": "k-receipt", "value": "-1", "message": "failed on the 'gte=0' rule"},
+			{"line": 3, "column": "donation", "value": "x", "message": "invalid amount: \"x\""}
+		]
+	}`, rec.Body.String())
+}
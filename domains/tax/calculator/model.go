@@ -1,32 +1,251 @@
 package calculator
 
+import (
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/domains/admin/allowanceGroup"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/dependent"
+	"github.com/larb26656/assessment-tax/domains/admin/taxBracket"
+	"github.com/larb26656/assessment-tax/money"
+)
+
 type AllowanceReq struct {
-	AllowanceType string  `json:"allowanceType" validate:"required,oneof='donation' 'k-receipt'"`
-	Amount        float64 `json:"amount" validate:"gte=0"`
+	AllowanceType string      `json:"allowanceType" validate:"required,allowance_type"`
+	Amount        money.Money `json:"amount" validate:"gte=0"`
+}
+
+// IncomeReq is an income of a section 40 category, ActualExpense replaces the standard expense
+// of a category that allows it
+type IncomeReq struct {
+	IncomeType    string       `json:"incomeType" validate:"required,income_type"`
+	Amount        money.Money  `json:"amount" validate:"gte=0"`
+	ActualExpense *money.Money `json:"actualExpense" validate:"omitempty,gte=0,actual_expense"`
+}
+
+type SpouseReq struct {
+	Income money.Money `json:"income" validate:"gte=0"`
+}
+
+type ChildReq struct {
+	BirthYear int `json:"birthYear" validate:"required,gte=2400"`
+}
+
+type ParentReq struct {
+	Age    int         `json:"age" validate:"gte=0"`
+	Income money.Money `json:"income" validate:"gte=0"`
+}
+
+// DependentsReq is the household of the tax payer, Parents includes the parents of the spouse
+type DependentsReq struct {
+	Spouse             *SpouseReq  `json:"spouse"`
+	Children           []ChildReq  `json:"children" validate:"dive"`
+	Parents            []ParentReq `json:"parents" validate:"max=4,dive"`
+	DisabledDependents int         `json:"disabledDependents" validate:"gte=0,lte=100"`
+}
+
+// InterestReq is the interest before withholding tax, TaxOption is final, include or auto
+type InterestReq struct {
+	Amount    money.Money `json:"amount" validate:"gte=0"`
+	TaxOption string      `json:"taxOption" validate:"required,oneof=final include auto"`
+}
+
+// DividendReq is the dividend before withholding tax, CorporateTaxRate is the tax the company paid
+// on the profit and is DefaultCorporateTaxRate when not given
+type DividendReq struct {
+	Amount           money.Money `json:"amount" validate:"gte=0"`
+	TaxOption        string      `json:"taxOption" validate:"required,oneof=final include auto"`
+	CorporateTaxRate *money.Rate `json:"corporateTaxRate" validate:"omitempty,gte=0,lt=100"`
 }
 
 type TaxCalculatorReq struct {
-	TotalIncome float64        `json:"totalIncome" validate:"gte=0"`
-	WHT         float64        `json:"wht" validate:"gte=0"`
+	TaxYear     int            `json:"taxYear" validate:"omitempty,gte=2500"`
+	TotalIncome money.Money    `json:"totalIncome" validate:"gte=0"`
+	WHT         money.Money    `json:"wht" validate:"gte=0"`
 	Allowances  []AllowanceReq `json:"allowances" validate:"required,dive"`
+	Dependents  DependentsReq  `json:"dependents"`
+
+	// Incomes are added to TotalIncome, TotalIncome has no expense deducted
+	Incomes []IncomeReq `json:"incomes" validate:"dive"`
+
+	// Interest and Dividend have tax withheld at source, an included one is added to Incomes as 40(4)
+	Interest *InterestReq `json:"interest"`
+	Dividend *DividendReq `json:"dividend"`
 }
 
 type TaxLevelRes struct {
-	Level string  `json:"level"`
-	Tax   float64 `json:"tax"`
+	Level         string       `json:"level"`
+	MinIncome     money.Money  `json:"minIncome"`
+	MaxIncome     *money.Money `json:"maxIncome"`
+	Rate          money.Rate   `json:"rate"`
+	TaxableIncome money.Money  `json:"taxableIncome"`
+	BracketTax    money.Money  `json:"bracketTax"`
+	CumulativeTax money.Money  `json:"cumulativeTax"`
+
+	// Tax is kept for compatibility, it is the cumulative tax of a level reached by net income and 0 otherwise
+	Tax money.Money `json:"tax"`
 }
 type TaxCalculatorRes struct {
-	Tax       float64       `json:"tax"`
-	TaxRefund float64       `json:"taxRefund"`
+	TaxYear   int           `json:"taxYear,omitempty"`
+	Tax       money.Money   `json:"tax"`
+	TaxRefund money.Money   `json:"taxRefund"`
 	TaxLevel  []TaxLevelRes `json:"taxLevel"`
+
+	EffectiveTaxRate    money.Rate   `json:"effectiveTaxRate"`
+	EffectiveNetTaxRate money.Rate   `json:"effectiveNetTaxRate"`
+	MarginalTaxRate     money.Rate   `json:"marginalTaxRate"`
+	NextBracketDistance *money.Money `json:"nextBracketDistance"`
+
+	// TaxMethod is the method of the tax paid, the higher of ProgressiveTax and MinimumTax
+	TaxMethod      string      `json:"taxMethod"`
+	ProgressiveTax money.Money `json:"progressiveTax"`
+	MinimumTax     money.Money `json:"minimumTax"`
+
+	InvestmentIncomes []InvestmentIncomeRes `json:"investmentIncomes,omitempty"`
+
+	Explanation *TaxExplanationRes `json:"explanation,omitempty"`
+}
+
+// AllowanceExplanationRes shows the cap applied to an allowance type, MaxDeduction is the lower of
+// the max deduction setting and the percentage cap
+type AllowanceExplanationRes struct {
+	AllowanceType string       `json:"allowanceType"`
+	Amount        money.Money  `json:"amount"`
+	CapRate       money.Rate   `json:"capRate,omitempty"`
+	CapBase       string       `json:"capBase,omitempty"`
+	CapBaseAmount *money.Money `json:"capBaseAmount,omitempty"`
+	MaxDeduction  money.Money  `json:"maxDeduction"`
+	GroupCutOff   money.Money  `json:"groupCutOff,omitempty"`
+	Deduction     money.Money  `json:"deduction"`
+	CutOff        money.Money  `json:"cutOff"`
+}
+
+// AllowanceGroupExplanationRes shows the combined cap of a group, Amount is the deduction
+// of its allowance types after their own caps
+type AllowanceGroupExplanationRes struct {
+	AllowanceGroup string      `json:"allowanceGroup"`
+	AllowanceTypes []string    `json:"allowanceTypes"`
+	Amount         money.Money `json:"amount"`
+	MaxDeduction   money.Money `json:"maxDeduction"`
+	Deduction      money.Money `json:"deduction"`
+	CutOff         money.Money `json:"cutOff"`
+}
+
+// IncomeExplanationRes shows the expense deducted from an income, ExpenseCutOff is the standard
+// expense over what is left of the cap shared by its expense group
+type IncomeExplanationRes struct {
+	IncomeType    string      `json:"incomeType"`
+	Amount        money.Money `json:"amount"`
+	ExpenseMethod string      `json:"expenseMethod"`
+	ExpenseRate   money.Rate  `json:"expenseRate,omitempty"`
+	ExpenseCutOff money.Money `json:"expenseCutOff,omitempty"`
+	Expense       money.Money `json:"expense"`
+	NetIncome     money.Money `json:"netIncome"`
+}
+
+// DependentExplanationRes shows the fixed deduction of a dependent, a dependent failing its
+// income or age test is not eligible and deducts nothing
+type DependentExplanationRes struct {
+	DependentType string      `json:"dependentType"`
+	BirthYear     int         `json:"birthYear,omitempty"`
+	Age           int         `json:"age,omitempty"`
+	Eligible      bool        `json:"eligible"`
+	Extra         money.Money `json:"extra,omitempty"`
+	Deduction     money.Money `json:"deduction"`
+}
+
+// InvestmentIncomeRes shows the tax option used for an interest or dividend income. An included income adds
+// AssessableIncome to the progressive tax and credits WithholdingTax and TaxCredit. TaxIfFinal and TaxIfIncluded
+// are the tax to pay with either option, negative is a refund, and are shown when the option was picked automatically
+type InvestmentIncomeRes struct {
+	IncomeType       string       `json:"incomeType"`
+	Amount           money.Money  `json:"amount"`
+	RequestedOption  string       `json:"requestedOption"`
+	TaxOption        string       `json:"taxOption"`
+	WithholdingRate  money.Rate   `json:"withholdingRate"`
+	WithholdingTax   money.Money  `json:"withholdingTax"`
+	TaxCredit        money.Money  `json:"taxCredit"`
+	AssessableIncome money.Money  `json:"assessableIncome"`
+	TaxIfFinal       *money.Money `json:"taxIfFinal,omitempty"`
+	TaxIfIncluded    *money.Money `json:"taxIfIncluded,omitempty"`
+}
+
+// TaxExplanationRes is the step-by-step trace of how the tax was calculated
+type TaxExplanationRes struct {
+	TotalIncome       money.Money                    `json:"totalIncome"`
+	Incomes           []IncomeExplanationRes         `json:"incomes"`
+	TotalExpenses     money.Money                    `json:"totalExpenses"`
+	Allowances        []AllowanceExplanationRes      `json:"allowances"`
+	AllowanceGroups   []AllowanceGroupExplanationRes `json:"allowanceGroups"`
+	TotalAllowances   money.Money                    `json:"totalAllowances"`
+	PersonalDeduction money.Money                    `json:"personalDeduction"`
+	Dependents        []DependentExplanationRes      `json:"dependents"`
+	TotalDependents   money.Money                    `json:"totalDependents"`
+	TotalDeduction    money.Money                    `json:"totalDeduction"`
+	NetIncome         money.Money                    `json:"netIncome"`
+	TaxLevel          []TaxLevelRes                  `json:"taxLevel"`
+	MarginalLevel     string                         `json:"marginalLevel"`
+	NonSalaryIncome   money.Money                    `json:"nonSalaryIncome"`
+	TotalTax          money.Money                    `json:"totalTax"`
+	WHT               money.Money                    `json:"wht"`
+	TaxCredits        money.Money                    `json:"taxCredits"`
+	Tax               money.Money                    `json:"tax"`
+	TaxRefund         money.Money                    `json:"taxRefund"`
 }
 
 type TaxCalucalorMultipleDetailRes struct {
-	TotalIncome float64 `json:"totalIncome"`
-	Tax         float64 `json:"tax"`
-	TaxRefund   float64 `json:"taxRefund"`
+	// Line is the line of the row in the csv, set in lenient mode only
+	Line        int         `json:"line,omitempty"`
+	TotalIncome money.Money `json:"totalIncome"`
+	Tax         money.Money `json:"tax"`
+	TaxRefund   money.Money `json:"taxRefund"`
+
+	EffectiveTaxRate    money.Rate   `json:"effectiveTaxRate"`
+	EffectiveNetTaxRate money.Rate   `json:"effectiveNetTaxRate"`
+	MarginalTaxRate     money.Rate   `json:"marginalTaxRate"`
+	NextBracketDistance *money.Money `json:"nextBracketDistance"`
 }
 
 type TaxCalucalorMultipleRes struct {
 	Taxes []TaxCalucalorMultipleDetailRes `json:"taxes"`
 }
+
+// TaxSetting holds every admin setting used to calculate tax of a tax year
+type TaxSetting struct {
+	TaxYear           int
+	PersonalDeduction money.Money
+	Dependents        dependent.DependentDeduction
+	MaxDeductions     map[string]money.Money
+	// CapRates holds the percentage cap of every allowance type with a CapRateKey
+	CapRates        map[string]money.Rate
+	AllowanceGroups []allowanceGroup.AllowanceGroup
+	TaxBrackets     []taxBracket.TaxBracket
+}
+
+// AllowanceCap is the max deduction of an allowance type, with the optional percentage cap
+type AllowanceCap struct {
+	AllowanceType string
+	MaxDeduction  money.Money
+	CapRate       money.Rate
+	CapBase       string
+}
+
+// AllowanceCaps returns the cap of every allowance type in the order they are deducted
+func (s TaxSetting) AllowanceCaps() []AllowanceCap {
+	allowanceCaps := []AllowanceCap{}
+
+	for _, registered := range allowanceType.All() {
+		capRate := registered.CapRate
+
+		if registered.CapRateKey != "" {
+			capRate = s.CapRates[registered.Key]
+		}
+
+		allowanceCaps = append(allowanceCaps, AllowanceCap{
+			AllowanceType: registered.Key,
+			MaxDeduction:  s.MaxDeductions[registered.Key],
+			CapRate:       capRate,
+			CapBase:       registered.CapBase,
+		})
+	}
+
+	return allowanceCaps
+}
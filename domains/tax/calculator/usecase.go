@@ -1,63 +1,307 @@
 package calculator
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
 	"github.com/larb26656/assessment-tax/constant/allowanceType"
-	"github.com/larb26656/assessment-tax/domains/admin/deduction/kReceipt"
+	"github.com/larb26656/assessment-tax/constant/deductionType"
+	"github.com/larb26656/assessment-tax/constant/incomeType"
+	"github.com/larb26656/assessment-tax/constant/investmentIncome"
+	"github.com/larb26656/assessment-tax/constant/taxMethod"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/admin/allowanceGroup"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/allowance"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/dependent"
 	"github.com/larb26656/assessment-tax/domains/admin/deduction/personal"
+	"github.com/larb26656/assessment-tax/domains/admin/taxBracket"
+	"github.com/larb26656/assessment-tax/money"
 )
 
+var ErrTaxYearNotSupported = errors.New("tax year not supported")
+
 type TaxCalculatorUseCase interface {
-	CalculateAllowances(allowances []AllowanceReq, maxDonation float64, maxKReceipt float64) float64
-	CalculateTaxDeduction(personalDeduction, totalAllowances float64) float64
-	CalculateNetIncome(income, taxDeduction float64) float64
-	CalculateTax(netIncome float64, wht float64) (float64, float64, []TaxLevelRes)
+	CalculateTaxDeduction(personalDeduction, totalAllowances money.Money) money.Money
+	CalculateNetIncome(income, taxDeduction money.Money) money.Money
+	CalculateTax(netIncome money.Money, wht money.Money, taxBrackets []taxBracket.TaxBracket) (money.Money, money.Money, []TaxLevelRes, error)
+	CalculateMinimumTax(incomes []IncomeReq) (money.Money, error)
+	GetTaxSetting(year int) (TaxSetting, error)
+	CalculateWithSetting(req TaxCalculatorReq, setting TaxSetting) (TaxCalculatorRes, error)
 	Calculate(req TaxCalculatorReq) (TaxCalculatorRes, error)
-	CalculateMultiRequest(reqs []TaxCalculatorReq) (TaxCalucalorMultipleRes, error)
 }
 
 type taxCalculatorUseCase struct {
-	personalDeductionUsecase personal.PersonalDeductionUsecase
-	kReceiptDeductionUsecase kReceipt.KReceiptDeductionUsecase
+	personalDeductionUsecase  personal.PersonalDeductionUsecase
+	dependentDeductionUsecase dependent.DependentDeductionUsecase
+	allowanceDeductionUsecase allowance.AllowanceDeductionUsecase
+	allowanceGroupUsecase     allowanceGroup.AllowanceGroupUsecase
+	taxBracketUsecase         taxBracket.TaxBracketUsecase
 }
 
-func NewTaxCalculatorUseCase(personalDeductionUsecase personal.PersonalDeductionUsecase, kReceiptDeductionUsecase kReceipt.KReceiptDeductionUsecase) TaxCalculatorUseCase {
+func NewTaxCalculatorUseCase(personalDeductionUsecase personal.PersonalDeductionUsecase, dependentDeductionUsecase dependent.DependentDeductionUsecase, allowanceDeductionUsecase allowance.AllowanceDeductionUsecase, allowanceGroupUsecase allowanceGroup.AllowanceGroupUsecase, taxBracketUsecase taxBracket.TaxBracketUsecase) TaxCalculatorUseCase {
 	return &taxCalculatorUseCase{
-		personalDeductionUsecase: personalDeductionUsecase,
-		kReceiptDeductionUsecase: kReceiptDeductionUsecase,
+		personalDeductionUsecase:  personalDeductionUsecase,
+		dependentDeductionUsecase: dependentDeductionUsecase,
+		allowanceDeductionUsecase: allowanceDeductionUsecase,
+		allowanceGroupUsecase:     allowanceGroupUsecase,
+		taxBracketUsecase:         taxBracketUsecase,
 	}
 }
 
-func (t *taxCalculatorUseCase) CalculateAllowances(allowances []AllowanceReq, maxDonation float64, maxKReceipt float64) float64 {
-	var totalAllowances float64 = 0
-	var totalDonation float64 = 0
-	var totalKReceipt float64 = 0
+// explainAllowances sums the raw amount of each allowance type and caps it at its max deduction,
+// then at what is left of every group cap it shares. Types are deducted in order so a net income
+// cap base only sees the deductions before it and a group cap cuts the types deducted last.
+// fixedDeduction is everything deducted before the allowances, e.g. expenses and the personal deduction.
+func explainAllowances(allowances []AllowanceReq, allowanceCaps []AllowanceCap, allowanceGroups []allowanceGroup.AllowanceGroup, totalIncome, fixedDeduction money.Money) ([]AllowanceExplanationRes, []AllowanceGroupExplanationRes, error) {
+	amounts := make(map[string]money.Money)
 
 	for _, allowance := range allowances {
-		if allowance.AllowanceType == allowanceType.Donation {
-			totalDonation += allowance.Amount
-		} else if allowance.AllowanceType == allowanceType.KReceipt {
-			totalKReceipt += allowance.Amount
+		amounts[allowance.AllowanceType] += allowance.Amount
+	}
+
+	explanations := []AllowanceExplanationRes{}
+	groupExplanations := make([]*AllowanceGroupExplanationRes, len(allowanceGroups))
+	netIncome := totalIncome - fixedDeduction
+
+	for _, allowanceCap := range allowanceCaps {
+		amount, ok := amounts[allowanceCap.AllowanceType]
+
+		if !ok {
+			continue
+		}
+
+		explanation := AllowanceExplanationRes{
+			AllowanceType: allowanceCap.AllowanceType,
+			Amount:        amount,
+			MaxDeduction:  allowanceCap.MaxDeduction,
+		}
+
+		if allowanceCap.CapRate > 0 {
+			capBaseAmount := capBaseAmountOf(allowanceCap.CapBase, totalIncome, netIncome, amount)
+
+			explanation.CapRate = allowanceCap.CapRate
+			explanation.CapBase = allowanceCap.CapBase
+			explanation.CapBaseAmount = &capBaseAmount
+
+			rateCap, err := capBaseAmount.MulRate(allowanceCap.CapRate)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			explanation.MaxDeduction = money.Min(allowanceCap.MaxDeduction, rateCap)
+		}
+
+		deduction := money.Min(amount, explanation.MaxDeduction)
+		explanation.Deduction = deduction
+
+		for i, group := range allowanceGroups {
+			if !group.Contains(allowanceCap.AllowanceType) {
+				continue
+			}
+
+			if groupExplanations[i] == nil {
+				groupExplanations[i] = &AllowanceGroupExplanationRes{
+					AllowanceGroup: group.Key,
+					AllowanceTypes: group.AllowanceTypes,
+					MaxDeduction:   group.MaxDeduction,
+				}
+			}
+
+			groupLeft := money.Max(group.MaxDeduction-groupExplanations[i].Deduction, 0)
+			explanation.Deduction = money.Min(explanation.Deduction, groupLeft)
+		}
+
+		for i, group := range allowanceGroups {
+			if !group.Contains(allowanceCap.AllowanceType) {
+				continue
+			}
+
+			groupExplanations[i].Amount += deduction
+			groupExplanations[i].Deduction += explanation.Deduction
+			groupExplanations[i].CutOff = groupExplanations[i].Amount - groupExplanations[i].Deduction
 		}
+
+		explanation.GroupCutOff = deduction - explanation.Deduction
+		explanation.CutOff = amount - explanation.Deduction
+		netIncome -= explanation.Deduction
+
+		explanations = append(explanations, explanation)
 	}
 
-	if totalDonation > maxDonation {
-		totalDonation = maxDonation
+	// only groups with a requested allowance type are explained
+	groups := []AllowanceGroupExplanationRes{}
+
+	for _, groupExplanation := range groupExplanations {
+		if groupExplanation != nil {
+			groups = append(groups, *groupExplanation)
+		}
 	}
 
-	if totalKReceipt > maxKReceipt {
-		totalKReceipt = maxKReceipt
+	return explanations, groups, nil
+}
+
+// capBaseAmountOf returns the amount a percentage cap is taken from, never below 0
+func capBaseAmountOf(capBase string, totalIncome, netIncome, amount money.Money) money.Money {
+	switch capBase {
+	case allowanceType.CapBaseGrossIncome:
+		return money.Max(totalIncome, 0)
+	case allowanceType.CapBaseNetIncome:
+		return money.Max(netIncome, 0)
+	default:
+		return money.Max(amount, 0)
 	}
+}
+
+func sumAllowanceDeductions(explanations []AllowanceExplanationRes) money.Money {
+	var totalAllowances money.Money
 
-	totalAllowances = totalDonation + totalKReceipt
+	for _, explanation := range explanations {
+		totalAllowances += explanation.Deduction
+	}
 
 	return totalAllowances
 }
 
-func (t *taxCalculatorUseCase) CalculateTaxDeduction(personalDeduction, totalAllowances float64) float64 {
+// explainIncomes deducts the expense of every income in order. The standard expense is ExpenseRate
+// of the income capped at what is left of MaxExpense of its expense group, an actual expense
+// is deducted as it is. An expense never goes over its income.
+func explainIncomes(incomes []IncomeReq) ([]IncomeExplanationRes, error) {
+	explanations := []IncomeExplanationRes{}
+	groupExpenses := make(map[string]money.Money)
+
+	for _, income := range incomes {
+		registered, _ := incomeType.Get(income.IncomeType)
+
+		explanation := IncomeExplanationRes{
+			IncomeType: income.IncomeType,
+			Amount:     income.Amount,
+		}
+
+		if income.ActualExpense != nil {
+			explanation.ExpenseMethod = incomeType.ExpenseMethodActual
+			explanation.Expense = money.Min(*income.ActualExpense, income.Amount)
+		} else {
+			expense, err := income.Amount.MulRate(registered.ExpenseRate)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if registered.MaxExpense > 0 {
+				groupLeft := money.Max(registered.MaxExpense-groupExpenses[registered.ExpenseGroup], 0)
+				explanation.ExpenseCutOff = money.Max(expense-groupLeft, 0)
+				expense -= explanation.ExpenseCutOff
+				groupExpenses[registered.ExpenseGroup] += expense
+			}
+
+			explanation.ExpenseMethod = incomeType.ExpenseMethodStandard
+			explanation.ExpenseRate = registered.ExpenseRate
+			explanation.Expense = expense
+		}
+
+		explanation.NetIncome = income.Amount - explanation.Expense
+		explanations = append(explanations, explanation)
+	}
+
+	return explanations, nil
+}
+
+func sumIncomes(incomes []IncomeReq) money.Money {
+	var total money.Money
+
+	for _, income := range incomes {
+		total += income.Amount
+	}
+
+	return total
+}
+
+func sumIncomeExpenses(explanations []IncomeExplanationRes) money.Money {
+	var total money.Money
+
+	for _, explanation := range explanations {
+		total += explanation.Expense
+	}
+
+	return total
+}
+
+// explainDependents gives every dependent its fixed deduction. Children are counted in birth order,
+// a second or later child born from ChildExtraFromBirthYear gets the child extra on top. A spouse
+// must have no income and a parent must reach ParentMinAge with an income within the parent max income.
+func explainDependents(dependents DependentsReq, deductions dependent.DependentDeduction) []DependentExplanationRes {
+	explanations := []DependentExplanationRes{}
+
+	if dependents.Spouse != nil {
+		eligible := dependents.Spouse.Income == 0
+		explanations = append(explanations, dependentExplanation(deductionType.Spouse, eligible, deductions.Spouse, 0))
+	}
+
+	children := append([]ChildReq{}, dependents.Children...)
+
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].BirthYear < children[j].BirthYear
+	})
+
+	for i, child := range children {
+		var extra money.Money
+
+		if i > 0 && child.BirthYear >= deductionType.ChildExtraFromBirthYear {
+			extra = deductions.ChildExtra
+		}
+
+		explanation := dependentExplanation(deductionType.Child, true, deductions.Child, extra)
+		explanation.BirthYear = child.BirthYear
+		explanations = append(explanations, explanation)
+	}
+
+	for _, parent := range dependents.Parents {
+		eligible := parent.Age >= deductionType.ParentMinAge && parent.Income <= deductions.ParentMaxIncome
+		explanation := dependentExplanation(deductionType.Parent, eligible, deductions.Parent, 0)
+		explanation.Age = parent.Age
+		explanations = append(explanations, explanation)
+	}
+
+	for i := 0; i < dependents.DisabledDependents; i++ {
+		explanations = append(explanations, dependentExplanation(deductionType.DisabledDependent, true, deductions.DisabledDependent, 0))
+	}
+
+	return explanations
+}
+
+func dependentExplanation(dependentType string, eligible bool, deduction, extra money.Money) DependentExplanationRes {
+	if !eligible {
+		return DependentExplanationRes{DependentType: dependentType}
+	}
+
+	return DependentExplanationRes{
+		DependentType: dependentType,
+		Eligible:      true,
+		Extra:         extra,
+		Deduction:     deduction + extra,
+	}
+}
+
+func sumDependentDeductions(explanations []DependentExplanationRes) money.Money {
+	var total money.Money
+
+	for _, explanation := range explanations {
+		total += explanation.Deduction
+	}
+
+	return total
+}
+
+func (t *taxCalculatorUseCase) CalculateTaxDeduction(personalDeduction, totalAllowances money.Money) money.Money {
 	return totalAllowances + personalDeduction
 }
 
-func (t *taxCalculatorUseCase) CalculateNetIncome(income, taxDeduction float64) float64 {
+func (t *taxCalculatorUseCase) CalculateNetIncome(income, taxDeduction money.Money) money.Money {
 	netIncome := income - taxDeduction
 
 	if netIncome < 0 {
@@ -67,142 +311,509 @@ func (t *taxCalculatorUseCase) CalculateNetIncome(income, taxDeduction float64)
 	return netIncome
 }
 
-func (t *taxCalculatorUseCase) CalculateTax(netIncome, wht float64) (float64, float64, []TaxLevelRes) {
-	taxLevels := []TaxLevelRes{
-		{
-			Level: "0-150,000",
-			Tax:   0,
-		},
-		{
-			Level: "150,001-500,000",
-			Tax:   0,
-		},
-		{
-			Level: "500,001-1,000,000",
-			Tax:   0,
-		},
-		{
-			Level: "1,000,001-2,000,000",
-			Tax:   0,
-		},
-		{
-			Level: "2,000,001 ขึ้นไป",
-			Tax:   0,
-		},
+func (t *taxCalculatorUseCase) CalculateTax(netIncome, wht money.Money, taxBrackets []taxBracket.TaxBracket) (money.Money, money.Money, []TaxLevelRes, error) {
+	taxLevels := make([]TaxLevelRes, len(taxBrackets))
+	var tax money.Money
+
+	// brackets are ordered by level, each level show the accumulated tax up to that level
+	for i, bracket := range taxBrackets {
+		taxableIncome := taxableIncomeInBracket(netIncome, bracket)
+		bracketTax, err := taxableIncome.MulRate(bracket.Rate)
+
+		if err != nil {
+			return 0, 0, nil, err
+		}
+
+		tax += bracketTax
+
+		taxLevels[i] = TaxLevelRes{
+			Level:         bracket.Label(),
+			MinIncome:     bracket.MinIncome,
+			MaxIncome:     bracket.MaxIncome,
+			Rate:          bracket.Rate,
+			TaxableIncome: taxableIncome,
+			BracketTax:    bracketTax,
+			CumulativeTax: tax,
+			Tax:           0,
+		}
+
+		if taxableIncome > 0 {
+			taxLevels[i].Tax = tax
+		}
 	}
 
-	lastTaxVisitIndex := 0
+	tax, taxRefund := settleTax(tax, wht)
+
+	return tax, taxRefund, taxLevels, nil
+}
+
+// settleTax takes wht off the total tax, wht over the total tax is refunded
+func settleTax(totalTax, wht money.Money) (money.Money, money.Money) {
+	tax := totalTax - wht
 
-	if netIncome >= 0 {
-		taxLevels[0].Tax = 0
+	var taxRefund money.Money
+
+	if tax < 0 {
+		taxRefund = -tax
+		tax = 0
 	}
 
-	if netIncome > 150000 {
-		taxInLevel := (netIncome - 150000) * 0.1
+	return tax, taxRefund
+}
+
+// CalculateMinimumTax returns MinimumTaxRate of the gross income other than 40(1),
+// it is 0 when that income does not go over MinimumTaxThreshold
+func (t *taxCalculatorUseCase) CalculateMinimumTax(incomes []IncomeReq) (money.Money, error) {
+	nonSalaryIncome := sumNonSalaryIncomes(incomes)
 
-		if taxInLevel < 35000 {
-			taxLevels[1].Tax = taxInLevel
-		} else {
-			taxLevels[1].Tax = 35000
+	if nonSalaryIncome <= taxMethod.MinimumTaxThreshold {
+		return 0, nil
+	}
+
+	return nonSalaryIncome.MulRate(taxMethod.MinimumTaxRate)
+}
+
+func sumNonSalaryIncomes(incomes []IncomeReq) money.Money {
+	var total money.Money
+
+	for _, income := range incomes {
+		if income.IncomeType != incomeType.Salary {
+			total += income.Amount
 		}
+	}
 
-		lastTaxVisitIndex++
+	return total
+}
+
+// taxableIncomeInBracket returns the slice of net income that is taxed at the bracket rate
+func taxableIncomeInBracket(netIncome money.Money, bracket taxBracket.TaxBracket) money.Money {
+	if netIncome <= bracket.MinIncome {
+		return 0
 	}
 
-	if netIncome > 500000 {
-		taxInLevel := (netIncome-500000)*0.15 + 35000 // 35000 is the tax for the first bracket
+	if bracket.MaxIncome != nil && netIncome > *bracket.MaxIncome {
+		return *bracket.MaxIncome - bracket.MinIncome
+	}
 
-		if taxInLevel < 100000 {
-			taxLevels[2].Tax = taxInLevel
-		} else {
-			taxLevels[2].Tax = 100000
+	return netIncome - bracket.MinIncome
+}
+
+// marginalTaxLevel returns the highest level reached by net income
+func marginalTaxLevel(taxLevels []TaxLevelRes) TaxLevelRes {
+	var marginal TaxLevelRes
+
+	for i, taxLevel := range taxLevels {
+		if i == 0 || taxLevel.TaxableIncome > 0 {
+			marginal = taxLevel
 		}
+	}
+
+	return marginal
+}
+
+func (t *taxCalculatorUseCase) GetTaxSetting(year int) (TaxSetting, error) {
+	personalTaxDeduction, err := t.personalDeductionUsecase.GetDeduction(year)
 
-		lastTaxVisitIndex++
+	if err != nil {
+		return TaxSetting{}, toTaxSettingErr(year, err)
 	}
 
-	if netIncome > 1000000 {
-		taxInLevel := (netIncome-1000000)*0.2 + 100000 // 100000 is the tax for the second bracket
+	dependentDeduction, err := t.dependentDeductionUsecase.GetDeduction(year)
 
-		if taxInLevel < 300000 {
-			taxLevels[3].Tax = taxInLevel
-		} else {
-			taxLevels[3].Tax = 300000
+	if err != nil {
+		return TaxSetting{}, toTaxSettingErr(year, err)
+	}
+
+	maxDeductions := make(map[string]money.Money)
+	capRates := make(map[string]money.Rate)
+
+	for _, registered := range allowanceType.All() {
+		maxDeduction, err := t.allowanceDeductionUsecase.GetMaxDeduction(year, registered.Key)
+
+		if err != nil {
+			return TaxSetting{}, toTaxSettingErr(year, err)
+		}
+
+		maxDeductions[registered.Key] = maxDeduction
+
+		if registered.CapRateKey == "" {
+			continue
+		}
+
+		capRate, err := t.allowanceDeductionUsecase.GetCapRate(year, registered.Key)
+
+		if err != nil {
+			return TaxSetting{}, toTaxSettingErr(year, err)
+		}
+
+		capRates[registered.Key] = capRate
+	}
+
+	allowanceGroups, err := t.allowanceGroupUsecase.GetAllowanceGroups(year)
+
+	if err != nil {
+		return TaxSetting{}, toTaxSettingErr(year, err)
+	}
+
+	taxBrackets, err := t.taxBracketUsecase.GetTaxBrackets(year)
+
+	if err != nil {
+		return TaxSetting{}, toTaxSettingErr(year, err)
+	}
+
+	return TaxSetting{
+		TaxYear:           year,
+		PersonalDeduction: personalTaxDeduction,
+		Dependents:        dependentDeduction,
+		MaxDeductions:     maxDeductions,
+		CapRates:          capRates,
+		AllowanceGroups:   allowanceGroups,
+		TaxBrackets:       taxBrackets,
+	}, nil
+}
+
+// toTaxSettingErr reports a missing setting as an unsupported tax year
+func toTaxSettingErr(year int, err error) error {
+	if errors.Is(err, deduction.ErrDeductionNotFound) || errors.Is(err, taxBracket.ErrTaxBracketsNotFound) {
+		return fmt.Errorf("%w: %d", ErrTaxYearNotSupported, year)
+	}
+
+	return err
+}
+
+// investmentIncomesOf lists the interest and dividend of the request as if they were included,
+// TaxCredit is the corporate tax credit of a dividend
+func investmentIncomesOf(req TaxCalculatorReq) ([]InvestmentIncomeRes, error) {
+	var investments []InvestmentIncomeRes
+
+	if req.Interest != nil {
+		withholdingTax, err := req.Interest.Amount.MulRate(investmentIncome.InterestWithholdingRate)
+
+		if err != nil {
+			return nil, err
 		}
 
-		lastTaxVisitIndex++
+		investments = append(investments, InvestmentIncomeRes{
+			IncomeType:       investmentIncome.Interest,
+			Amount:           req.Interest.Amount,
+			RequestedOption:  req.Interest.TaxOption,
+			TaxOption:        req.Interest.TaxOption,
+			WithholdingRate:  investmentIncome.InterestWithholdingRate,
+			WithholdingTax:   withholdingTax,
+			AssessableIncome: req.Interest.Amount,
+		})
 	}
 
-	if netIncome > 2000000 {
-		taxLevels[4].Tax = (netIncome-2000000)*0.35 + 300000 // 300000 is the tax for the third bracket
-		lastTaxVisitIndex++
+	if req.Dividend != nil {
+		corporateTaxRate := investmentIncome.DefaultCorporateTaxRate
+
+		if req.Dividend.CorporateTaxRate != nil {
+			corporateTaxRate = *req.Dividend.CorporateTaxRate
+		}
+
+		taxCredit, err := req.Dividend.Amount.GrossUpTax(corporateTaxRate)
+
+		if err != nil {
+			return nil, err
+		}
+
+		withholdingTax, err := req.Dividend.Amount.MulRate(investmentIncome.DividendWithholdingRate)
+
+		if err != nil {
+			return nil, err
+		}
+
+		investments = append(investments, InvestmentIncomeRes{
+			IncomeType:       investmentIncome.Dividend,
+			Amount:           req.Dividend.Amount,
+			RequestedOption:  req.Dividend.TaxOption,
+			TaxOption:        req.Dividend.TaxOption,
+			WithholdingRate:  investmentIncome.DividendWithholdingRate,
+			WithholdingTax:   withholdingTax,
+			TaxCredit:        taxCredit,
+			AssessableIncome: req.Dividend.Amount + taxCredit,
+		})
 	}
 
-	tax := taxLevels[lastTaxVisitIndex].Tax
+	return investments, nil
+}
+
+// withTaxOption sets the option of an investment income, a final one adds nothing to the progressive tax
+func withTaxOption(investment InvestmentIncomeRes, option string) InvestmentIncomeRes {
+	investment.TaxOption = option
 
-	tax -= wht
+	if option == investmentIncome.OptionFinal {
+		investment.TaxCredit = 0
+		investment.AssessableIncome = 0
+	}
 
-	taxRefund := 0.0
+	return investment
+}
 
-	if tax < 0 {
-		taxRefund = tax * -1
-		tax = 0
+func taxOptionsKey(investments []InvestmentIncomeRes) string {
+	options := make([]string, len(investments))
 
+	for i, investment := range investments {
+		options[i] = investment.TaxOption
 	}
 
-	return tax, taxRefund, taxLevels
+	return strings.Join(options, ",")
 }
 
-func (t *taxCalculatorUseCase) Calculate(req TaxCalculatorReq) (TaxCalculatorRes, error) {
-	personalTaxDeduction, err := t.personalDeductionUsecase.GetDeduction()
+// chooseInvestmentOptions resolves every auto option to the one with less tax to pay, a tie keeps it final.
+// Every combination is tried since a dividend credit can change whether including interest pays off.
+func (t *taxCalculatorUseCase) chooseInvestmentOptions(req TaxCalculatorReq, setting TaxSetting) ([]InvestmentIncomeRes, error) {
+	requested, err := investmentIncomesOf(req)
+
+	if err != nil || len(requested) == 0 {
+		return nil, err
+	}
+
+	combinations := [][]InvestmentIncomeRes{{}}
+
+	for _, investment := range requested {
+		options := []string{investment.RequestedOption}
+
+		if investment.RequestedOption == investmentIncome.OptionAuto {
+			options = []string{investmentIncome.OptionFinal, investmentIncome.OptionInclude}
+		}
+
+		var next [][]InvestmentIncomeRes
+
+		for _, combination := range combinations {
+			for _, option := range options {
+				next = append(next, append(slices.Clone(combination), withTaxOption(investment, option)))
+			}
+		}
+
+		combinations = next
+	}
+
+	if len(combinations) == 1 {
+		return combinations[0], nil
+	}
+
+	taxToPay := make(map[string]money.Money)
+	var chosen []InvestmentIncomeRes
+
+	for _, combination := range combinations {
+		result, err := t.calculate(req, setting, combination)
+
+		if err != nil {
+			return nil, err
+		}
+
+		taxToPay[taxOptionsKey(combination)] = result.Tax - result.TaxRefund
+
+		if chosen == nil || taxToPay[taxOptionsKey(combination)] < taxToPay[taxOptionsKey(chosen)] {
+			chosen = combination
+		}
+	}
+
+	// an auto option shows the tax to pay with either option, the other incomes keep their chosen option
+	for i := range chosen {
+		if chosen[i].RequestedOption != investmentIncome.OptionAuto {
+			continue
+		}
+
+		alternative := slices.Clone(chosen)
+
+		alternative[i].TaxOption = investmentIncome.OptionFinal
+		taxIfFinal := taxToPay[taxOptionsKey(alternative)]
+
+		alternative[i].TaxOption = investmentIncome.OptionInclude
+		taxIfIncluded := taxToPay[taxOptionsKey(alternative)]
+
+		chosen[i].TaxIfFinal = &taxIfFinal
+		chosen[i].TaxIfIncluded = &taxIfIncluded
+	}
+
+	return chosen, nil
+}
+
+// includeInvestmentIncomes adds every included investment income to incomes as 40(4), the withholding tax
+// and tax credit of those are credited against the tax like wht
+func includeInvestmentIncomes(incomes []IncomeReq, investments []InvestmentIncomeRes) ([]IncomeReq, money.Money) {
+	var taxCredits money.Money
+
+	for _, investment := range investments {
+		if investment.TaxOption != investmentIncome.OptionInclude {
+			continue
+		}
+
+		incomes = append(slices.Clip(incomes), IncomeReq{
+			IncomeType: incomeType.Investment,
+			Amount:     investment.AssessableIncome,
+		})
+		taxCredits += investment.WithholdingTax + investment.TaxCredit
+	}
+
+	return incomes, taxCredits
+}
+
+// minimumTaxIncomes adds every included investment income to incomes as 40(4) at the amount actually received,
+// the dividend tax credit grosses up the progressive tax base only and is not part of the minimum tax base
+func minimumTaxIncomes(incomes []IncomeReq, investments []InvestmentIncomeRes) []IncomeReq {
+	for _, investment := range investments {
+		if investment.TaxOption != investmentIncome.OptionInclude {
+			continue
+		}
+
+		incomes = append(slices.Clip(incomes), IncomeReq{
+			IncomeType: incomeType.Investment,
+			Amount:     investment.Amount,
+		})
+	}
+
+	return incomes
+}
+
+func (t *taxCalculatorUseCase) CalculateWithSetting(req TaxCalculatorReq, setting TaxSetting) (TaxCalculatorRes, error) {
+	investments, err := t.chooseInvestmentOptions(req, setting)
 
 	if err != nil {
 		return TaxCalculatorRes{}, err
 	}
 
-	kReceiptMaxTaxDeduction, err := t.kReceiptDeductionUsecase.GetDeduction()
+	return t.calculate(req, setting, investments)
+}
+
+// calculate works out the tax with the option of every investment income already chosen
+func (t *taxCalculatorUseCase) calculate(req TaxCalculatorReq, setting TaxSetting, investments []InvestmentIncomeRes) (TaxCalculatorRes, error) {
+	incomes, taxCredits := includeInvestmentIncomes(req.Incomes, investments)
+
+	// gross income is the assessable income a percentage cap on gross income is taken from
+	grossIncome := req.TotalIncome + sumIncomes(incomes)
+	incomeExplanations, err := explainIncomes(incomes)
 
 	if err != nil {
 		return TaxCalculatorRes{}, err
 	}
 
-	totalAllowances := t.CalculateAllowances(req.Allowances, 100000, kReceiptMaxTaxDeduction)
+	totalExpenses := sumIncomeExpenses(incomeExplanations)
+
+	// dependents are fixed deductions like the personal deduction so they come before the allowances
+	dependentExplanations := explainDependents(req.Dependents, setting.Dependents)
+	totalDependents := sumDependentDeductions(dependentExplanations)
+
+	allowanceExplanations, allowanceGroupExplanations, err := explainAllowances(req.Allowances, setting.AllowanceCaps(), setting.AllowanceGroups, grossIncome, totalExpenses+setting.PersonalDeduction+totalDependents)
+
+	if err != nil {
+		return TaxCalculatorRes{}, err
+	}
+
+	totalAllowances := sumAllowanceDeductions(allowanceExplanations)
 
 	taxDeduction := t.CalculateTaxDeduction(
-		personalTaxDeduction,
+		setting.PersonalDeduction+totalDependents,
 		totalAllowances,
 	)
 	netIncome := t.CalculateNetIncome(
-		req.TotalIncome,
+		grossIncome-totalExpenses,
 		taxDeduction,
 	)
 
-	tax, taxRefund, taxLevels := t.CalculateTax(netIncome, req.WHT)
+	_, _, taxLevels, err := t.CalculateTax(netIncome, req.WHT, setting.TaxBrackets)
+
+	if err != nil {
+		return TaxCalculatorRes{}, err
+	}
+
+	marginal := marginalTaxLevel(taxLevels)
+
+	// the higher of the progressive tax and the minimum tax is paid, total income is taken as salary
+	progressiveTax := marginal.CumulativeTax
+	minimumTaxBase := minimumTaxIncomes(req.Incomes, investments)
+	minimumTax, err := t.CalculateMinimumTax(minimumTaxBase)
+
+	if err != nil {
+		return TaxCalculatorRes{}, err
+	}
+
+	method, totalTax := taxMethod.Progressive, progressiveTax
+
+	if minimumTax > progressiveTax {
+		method, totalTax = taxMethod.Minimum, minimumTax
+	}
+
+	tax, taxRefund := settleTax(totalTax, req.WHT+taxCredits)
+
+	// rates are on the tax before wht, the distance is the net income left before the next bracket starts
+	var nextBracketDistance *money.Money
+
+	if marginal.MaxIncome != nil {
+		distance := *marginal.MaxIncome - netIncome
+		nextBracketDistance = &distance
+	}
+
+	effectiveTaxRate, err := money.Ratio(totalTax, grossIncome)
+
+	if err != nil {
+		return TaxCalculatorRes{}, err
+	}
+
+	effectiveNetTaxRate, err := money.Ratio(totalTax, netIncome)
+
+	if err != nil {
+		return TaxCalculatorRes{}, err
+	}
 
 	return TaxCalculatorRes{
-		Tax:       tax,
-		TaxRefund: taxRefund,
-		TaxLevel:  taxLevels,
+		TaxYear:             setting.TaxYear,
+		Tax:                 tax,
+		TaxRefund:           taxRefund,
+		TaxLevel:            taxLevels,
+		EffectiveTaxRate:    effectiveTaxRate,
+		EffectiveNetTaxRate: effectiveNetTaxRate,
+		MarginalTaxRate:     marginal.Rate,
+		NextBracketDistance: nextBracketDistance,
+		TaxMethod:           method,
+		ProgressiveTax:      progressiveTax,
+		MinimumTax:          minimumTax,
+		InvestmentIncomes:   investments,
+		Explanation: &TaxExplanationRes{
+			TotalIncome:       grossIncome,
+			Incomes:           incomeExplanations,
+			TotalExpenses:     totalExpenses,
+			Allowances:        allowanceExplanations,
+			AllowanceGroups:   allowanceGroupExplanations,
+			TotalAllowances:   totalAllowances,
+			PersonalDeduction: setting.PersonalDeduction,
+			Dependents:        dependentExplanations,
+			TotalDependents:   totalDependents,
+			TotalDeduction:    taxDeduction,
+			NetIncome:         netIncome,
+			TaxLevel:          taxLevels,
+			MarginalLevel:     marginal.Level,
+			NonSalaryIncome:   sumNonSalaryIncomes(minimumTaxBase),
+			TotalTax:          totalTax,
+			WHT:               req.WHT,
+			TaxCredits:        taxCredits,
+			Tax:               tax,
+			TaxRefund:         taxRefund,
+		},
 	}, nil
 }
 
-func (t *taxCalculatorUseCase) CalculateMultiRequest(reqs []TaxCalculatorReq) (TaxCalucalorMultipleRes, error) {
-	var taxes []TaxCalucalorMultipleDetailRes
-	for _, req := range reqs {
-		taxResult, err := t.Calculate(req)
-
-		if err != nil {
-			return TaxCalucalorMultipleRes{}, err
-		}
+func (t *taxCalculatorUseCase) Calculate(req TaxCalculatorReq) (TaxCalculatorRes, error) {
+	setting, err := t.GetTaxSetting(taxYear.Resolve(req.TaxYear))
 
-		taxes = append(taxes, TaxCalucalorMultipleDetailRes{
-			TotalIncome: req.TotalIncome,
-			Tax:         taxResult.Tax,
-			TaxRefund:   taxResult.TaxRefund,
-		})
+	if err != nil {
+		return TaxCalculatorRes{}, err
 	}
 
-	return TaxCalucalorMultipleRes{
-		Taxes: taxes,
-	}, nil
+	return t.CalculateWithSetting(req, setting)
+}
+
+// ToMultipleDetailRes keeps the fields of a result that a batch returns for every request
+func ToMultipleDetailRes(req TaxCalculatorReq, res TaxCalculatorRes) TaxCalucalorMultipleDetailRes {
+	return TaxCalucalorMultipleDetailRes{
+		TotalIncome:         req.TotalIncome,
+		Tax:                 res.Tax,
+		TaxRefund:           res.TaxRefund,
+		EffectiveTaxRate:    res.EffectiveTaxRate,
+		EffectiveNetTaxRate: res.EffectiveNetTaxRate,
+		MarginalTaxRate:     res.MarginalTaxRate,
+		NextBracketDistance: res.NextBracketDistance,
+	}
 }
@@ -5,139 +5,496 @@ import (
 	"testing"
 
 	"github.com/larb26656/assessment-tax/constant/allowanceType"
-	"github.com/larb26656/assessment-tax/domains/admin/deduction/kReceipt"
+	"github.com/larb26656/assessment-tax/constant/deductionType"
+	"github.com/larb26656/assessment-tax/constant/incomeType"
+	"github.com/larb26656/assessment-tax/constant/investmentIncome"
+	"github.com/larb26656/assessment-tax/constant/taxMethod"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/admin/allowanceGroup"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/allowance"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/dependent"
 	"github.com/larb26656/assessment-tax/domains/admin/deduction/personal"
+	"github.com/larb26656/assessment-tax/domains/admin/taxBracket"
+	"github.com/larb26656/assessment-tax/money"
 	"github.com/stretchr/testify/assert"
 )
 
 type mockPersonalDeductionUsecase struct {
 }
 
-func (p *mockPersonalDeductionUsecase) GetDeduction() (float64, error) {
-	return 60000.0, nil
+func (p *mockPersonalDeductionUsecase) GetDeduction(taxYear int) (money.Money, error) {
+	return money.FromBaht(60000), nil
 }
 
 func (p *mockPersonalDeductionUsecase) UpdateDeduction(req personal.UpdatePersonalDeductionReq) (personal.UpdatePersonalDeductionRes, error) {
 	return personal.UpdatePersonalDeductionRes{}, nil
 }
 
-type mockKReceiptDeductionUsecase struct {
+type mockDependentDeductionUsecase struct {
+	err error
 }
 
-func (p *mockKReceiptDeductionUsecase) GetDeduction() (float64, error) {
-	return 50000.0, nil
+func (p *mockDependentDeductionUsecase) GetDeduction(taxYear int) (dependent.DependentDeduction, error) {
+	if p.err != nil {
+		return dependent.DependentDeduction{}, p.err
+	}
+
+	return mockDependentDeduction(), nil
+}
+
+func (p *mockDependentDeductionUsecase) UpdateDeduction(req dependent.UpdateDependentDeductionReq) (dependent.UpdateDependentDeductionRes, error) {
+	return dependent.UpdateDependentDeductionRes{}, nil
+}
+
+func mockDependentDeduction() dependent.DependentDeduction {
+	return dependent.DependentDeduction{
+		Spouse:            money.FromBaht(60000),
+		Child:             money.FromBaht(30000),
+		ChildExtra:        money.FromBaht(30000),
+		Parent:            money.FromBaht(30000),
+		ParentMaxIncome:   money.FromBaht(30000),
+		DisabledDependent: money.FromBaht(60000),
+	}
+}
+
+type mockAllowanceDeductionUsecase struct {
+}
+
+func (p *mockAllowanceDeductionUsecase) GetMaxDeduction(taxYear int, key string) (money.Money, error) {
+	if key == allowanceType.KReceipt {
+		return money.FromBaht(50000), nil
+	}
+
+	return money.FromBaht(100000), nil
+}
+
+func (p *mockAllowanceDeductionUsecase) GetCapRate(taxYear int, key string) (money.Rate, error) {
+	if key == allowanceType.Donation {
+		return money.FromPercent(10), nil
+	}
+
+	return 0, nil
+}
+
+func (p *mockAllowanceDeductionUsecase) GetAllowanceTypes(taxYear int) (allowance.AllowanceTypesRes, error) {
+	return allowance.AllowanceTypesRes{}, nil
+}
+
+func (p *mockAllowanceDeductionUsecase) UpdateMaxDeduction(key string, req allowance.UpdateAllowanceDeductionReq) (allowance.UpdateAllowanceDeductionRes, error) {
+	return allowance.UpdateAllowanceDeductionRes{}, nil
+}
+
+func (p *mockAllowanceDeductionUsecase) UpdateMaxDeductions(taxYear int, maxDeductions []deduction.Deduction) error {
+	return nil
+}
+
+func (p *mockAllowanceDeductionUsecase) UpdateCapRate(key string, req allowance.UpdateAllowanceCapRateReq) (allowance.UpdateAllowanceCapRateRes, error) {
+	return allowance.UpdateAllowanceCapRateRes{}, nil
+}
+
+func mockTaxSetting() TaxSetting {
+	return TaxSetting{
+		PersonalDeduction: money.FromBaht(60000),
+		Dependents:        mockDependentDeduction(),
+		MaxDeductions: map[string]money.Money{
+			allowanceType.Donation: money.FromBaht(100000),
+			allowanceType.KReceipt: money.FromBaht(50000),
+		},
+		CapRates: map[string]money.Rate{
+			allowanceType.Donation: money.FromPercent(10),
+		},
+	}
+}
+
+func mockMaxIncome(maxIncome int64) *money.Money {
+	amount := money.FromBaht(maxIncome)
+
+	return &amount
 }
 
-func (p *mockKReceiptDeductionUsecase) UpdateDeduction(req kReceipt.UpdateKReceiptDeductionReq) (kReceipt.UpdateKReceiptDeductionRes, error) {
-	return kReceipt.UpdateKReceiptDeductionRes{}, nil
+func mockCapBaseAmount(amount int64) *money.Money {
+	capBaseAmount := money.FromBaht(amount)
+
+	return &capBaseAmount
+}
+
+func mockNextBracketDistance(distance int64) *money.Money {
+	amount := money.FromBaht(distance)
+
+	return &amount
+}
+
+var mockTaxBrackets = []taxBracket.TaxBracket{
+	{Level: 1, MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(150000), Rate: money.FromPercent(0)},
+	{Level: 2, MinIncome: money.FromBaht(150000), MaxIncome: mockMaxIncome(500000), Rate: money.FromPercent(10)},
+	{Level: 3, MinIncome: money.FromBaht(500000), MaxIncome: mockMaxIncome(1000000), Rate: money.FromPercent(15)},
+	{Level: 4, MinIncome: money.FromBaht(1000000), MaxIncome: mockMaxIncome(2000000), Rate: money.FromPercent(20)},
+	{Level: 5, MinIncome: money.FromBaht(2000000), MaxIncome: nil, Rate: money.FromPercent(35)},
 }
 
-// CalculateAllowances
-func TestCalculateAllowances_ShouldCalculateCorrect_WhenCorrectInput(t *testing.T) {
+type mockAllowanceGroupUsecase struct {
+}
+
+func (p *mockAllowanceGroupUsecase) GetAllowanceGroups(taxYear int) ([]allowanceGroup.AllowanceGroup, error) {
+	return []allowanceGroup.AllowanceGroup{}, nil
+}
+
+func (p *mockAllowanceGroupUsecase) UpdateAllowanceGroups(req allowanceGroup.UpdateAllowanceGroupsReq) (allowanceGroup.AllowanceGroupsRes, error) {
+	return allowanceGroup.AllowanceGroupsRes{}, nil
+}
+
+type mockTaxBracketUsecase struct {
+}
+
+func (p *mockTaxBracketUsecase) GetTaxBrackets(taxYear int) ([]taxBracket.TaxBracket, error) {
+	return mockTaxBrackets, nil
+}
+
+func (p *mockTaxBracketUsecase) ValidateTaxBrackets(req taxBracket.UpdateTaxBracketsReq) taxBracket.ValidateTaxBracketsRes {
+	return taxBracket.ValidateTaxBracketsRes{}
+}
+
+func (p *mockTaxBracketUsecase) UpdateTaxBrackets(req taxBracket.UpdateTaxBracketsReq) (taxBracket.TaxBracketsRes, error) {
+	return taxBracket.TaxBracketsRes{}, nil
+}
+
+func TestExplainAllowances_ShouldCapEachAllowanceType_WhenCorrectInput(t *testing.T) {
 	// Arrange
-	calculator := NewTaxCalculatorUseCase(
-		&mockPersonalDeductionUsecase{},
-		&mockKReceiptDeductionUsecase{},
-	)
+	setting := mockTaxSetting()
+
 	testCases := []struct {
 		name                    string
 		allowances              []AllowanceReq
-		expectedTotalAllowances float64
+		expectedTotalAllowances money.Money
 	}{
 		{"Test case 1", []AllowanceReq{
 			{
 				AllowanceType: allowanceType.Donation,
-				Amount:        2000.0,
+				Amount:        money.FromBaht(2000),
 			},
-		}, 2000.0},
+		}, money.FromBaht(2000)},
 		{"Test case 2", []AllowanceReq{
 			{
 				AllowanceType: allowanceType.Donation,
-				Amount:        2000,
+				Amount:        money.FromBaht(2000),
 			},
 			{
 				AllowanceType: allowanceType.Donation,
-				Amount:        4000,
+				Amount:        money.FromBaht(4000),
 			},
 			{
 				AllowanceType: allowanceType.Donation,
-				Amount:        4000,
+				Amount:        money.FromBaht(4000),
 			},
-		}, 10000},
+		}, money.FromBaht(10000)},
 		{"Test case 3", []AllowanceReq{
 			{
 				AllowanceType: allowanceType.Donation,
-				Amount:        2000.0,
+				Amount:        money.FromBaht(2000),
 			},
 			{
 				AllowanceType: allowanceType.Donation,
-				Amount:        4000.0,
+				Amount:        money.FromBaht(4000),
 			},
 			{
 				AllowanceType: allowanceType.Donation,
-				Amount:        200000.0,
+				Amount:        money.FromBaht(200000),
 			},
-		}, 100000.0},
+		}, money.FromBaht(100000)},
 		{"Test case 4", []AllowanceReq{
 			{
 				AllowanceType: allowanceType.Donation,
-				Amount:        2000.0,
+				Amount:        money.FromBaht(2000),
 			},
 			{
 				AllowanceType: allowanceType.KReceipt,
-				Amount:        4000.0,
+				Amount:        money.FromBaht(4000),
 			},
-		}, 6000.0},
+		}, money.FromBaht(6000)},
 		{"Test case 5", []AllowanceReq{
 			{
 				AllowanceType: allowanceType.Donation,
-				Amount:        200000.0,
+				Amount:        money.FromBaht(200000),
 			},
 			{
 				AllowanceType: allowanceType.KReceipt,
-				Amount:        200000.0,
+				Amount:        money.FromBaht(200000),
 			},
-		}, 150000.0},
+		}, money.FromBaht(150000)},
 		{"Test case 6", []AllowanceReq{
 			{
 				AllowanceType: allowanceType.KReceipt,
-				Amount:        5000.0,
+				Amount:        money.FromBaht(5000),
 			},
-		}, 5000.0},
+		}, money.FromBaht(5000)},
 		{"Test case 7", []AllowanceReq{
 			{
 				AllowanceType: allowanceType.KReceipt,
-				Amount:        200000.0,
+				Amount:        money.FromBaht(200000),
 			},
-		}, 50000.0},
+		}, money.FromBaht(50000)},
 	}
 
 	// Act
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := calculator.CalculateAllowances(tc.allowances, 100000.0, 50000.0)
+			explanations, _, err := explainAllowances(tc.allowances, setting.AllowanceCaps(), setting.AllowanceGroups, money.FromBaht(2000000), setting.PersonalDeduction)
+			result := sumAllowanceDeductions(explanations)
 
 			// Assert
+			assert.NoError(t, err)
 			assert.Equal(t, tc.expectedTotalAllowances, result)
 		})
 	}
 }
 
+func TestExplainAllowances_ShouldApplyPercentageCap_WhenCapRateIsSet(t *testing.T) {
+	// Arrange
+	allowanceCaps := []AllowanceCap{
+		{AllowanceType: "first", MaxDeduction: money.FromBaht(100000)},
+		{AllowanceType: "gross", MaxDeduction: money.FromBaht(100000), CapRate: money.FromPercent(15), CapBase: allowanceType.CapBaseGrossIncome},
+		{AllowanceType: "net", MaxDeduction: money.FromBaht(100000), CapRate: money.FromPercent(10), CapBase: allowanceType.CapBaseNetIncome},
+		{AllowanceType: "amount", MaxDeduction: money.FromBaht(100000), CapRate: money.FromPercent(50), CapBase: allowanceType.CapBaseAmount},
+	}
+
+	testCases := []struct {
+		name               string
+		allowanceType      string
+		amount             money.Money
+		expectedDeductions []money.Money
+	}{
+		{"Test case 1", "gross", money.FromBaht(90000), []money.Money{money.FromBaht(40000), money.FromBaht(75000)}},
+		{"Test case 2", "net", money.FromBaht(90000), []money.Money{money.FromBaht(40000), money.FromBaht(40000)}},
+		{"Test case 3", "amount", money.FromBaht(90000), []money.Money{money.FromBaht(40000), money.FromBaht(45000)}},
+		{"Test case 4", "net", money.FromBaht(10000), []money.Money{money.FromBaht(40000), money.FromBaht(10000)}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			result, _, err := explainAllowances([]AllowanceReq{
+				{AllowanceType: "first", Amount: money.FromBaht(40000)},
+				{AllowanceType: tc.allowanceType, Amount: tc.amount},
+			}, allowanceCaps, []allowanceGroup.AllowanceGroup{}, money.FromBaht(500000), money.FromBaht(60000))
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, result, 2)
+			assert.Equal(t, tc.expectedDeductions[0], result[0].Deduction)
+			assert.Equal(t, tc.expectedDeductions[1], result[1].Deduction)
+			assert.Equal(t, tc.amount-tc.expectedDeductions[1], result[1].CutOff)
+		})
+	}
+}
+
+func TestExplainAllowances_ShouldApplyGroupCap_AfterPerTypeCaps(t *testing.T) {
+	// Arrange
+	allowanceCaps := []AllowanceCap{
+		{AllowanceType: "first", MaxDeduction: money.FromBaht(300000)},
+		{AllowanceType: "second", MaxDeduction: money.FromBaht(200000)},
+		{AllowanceType: "third", MaxDeduction: money.FromBaht(100000)},
+		{AllowanceType: "outside", MaxDeduction: money.FromBaht(100000)},
+	}
+	allowanceGroups := []allowanceGroup.AllowanceGroup{
+		{Key: "group", MaxDeduction: money.FromBaht(400000), AllowanceTypes: []string{"first", "second", "third"}},
+		{Key: "unused", MaxDeduction: money.FromBaht(100000), AllowanceTypes: []string{"fourth", "fifth"}},
+	}
+
+	// Act
+	result, groups, err := explainAllowances([]AllowanceReq{
+		{AllowanceType: "first", Amount: money.FromBaht(350000)},
+		{AllowanceType: "second", Amount: money.FromBaht(150000)},
+		{AllowanceType: "third", Amount: money.FromBaht(50000)},
+		{AllowanceType: "outside", Amount: money.FromBaht(50000)},
+	}, allowanceCaps, allowanceGroups, money.FromBaht(5000000), money.FromBaht(60000))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []AllowanceExplanationRes{
+		{AllowanceType: "first", Amount: money.FromBaht(350000), MaxDeduction: money.FromBaht(300000), Deduction: money.FromBaht(300000), CutOff: money.FromBaht(50000)},
+		{AllowanceType: "second", Amount: money.FromBaht(150000), MaxDeduction: money.FromBaht(200000), GroupCutOff: money.FromBaht(50000), Deduction: money.FromBaht(100000), CutOff: money.FromBaht(50000)},
+		{AllowanceType: "third", Amount: money.FromBaht(50000), MaxDeduction: money.FromBaht(100000), GroupCutOff: money.FromBaht(50000), Deduction: money.FromBaht(0), CutOff: money.FromBaht(50000)},
+		{AllowanceType: "outside", Amount: money.FromBaht(50000), MaxDeduction: money.FromBaht(100000), Deduction: money.FromBaht(50000), CutOff: money.FromBaht(0)},
+	}, result)
+	assert.Equal(t, []AllowanceGroupExplanationRes{
+		{AllowanceGroup: "group", AllowanceTypes: []string{"first", "second", "third"}, Amount: money.FromBaht(500000), MaxDeduction: money.FromBaht(400000), Deduction: money.FromBaht(400000), CutOff: money.FromBaht(100000)},
+	}, groups)
+}
+
+func TestExplainAllowances_ShouldApplyCombinedCap_WhenLifeAndHealthInsuranceExceedIt(t *testing.T) {
+	// Arrange
+	setting := TaxSetting{
+		MaxDeductions: map[string]money.Money{
+			allowanceType.LifeInsurance:         money.FromBaht(100000),
+			allowanceType.HealthInsurance:       money.FromBaht(25000),
+			allowanceType.ParentHealthInsurance: money.FromBaht(15000),
+		},
+	}
+	allowanceGroups := []allowanceGroup.AllowanceGroup{
+		{Key: "insurance", MaxDeduction: money.FromBaht(100000), AllowanceTypes: []string{allowanceType.HealthInsurance, allowanceType.LifeInsurance}},
+	}
+	var allowanceCaps []AllowanceCap
+
+	for _, allowanceCap := range setting.AllowanceCaps() {
+		switch allowanceCap.AllowanceType {
+		case allowanceType.LifeInsurance, allowanceType.HealthInsurance, allowanceType.ParentHealthInsurance:
+			allowanceCaps = append(allowanceCaps, allowanceCap)
+		}
+	}
+
+	// Act
+	result, groups, err := explainAllowances([]AllowanceReq{
+		{AllowanceType: allowanceType.LifeInsurance, Amount: money.FromBaht(90000)},
+		{AllowanceType: allowanceType.HealthInsurance, Amount: money.FromBaht(30000)},
+		{AllowanceType: allowanceType.ParentHealthInsurance, Amount: money.FromBaht(20000)},
+	}, allowanceCaps, allowanceGroups, money.FromBaht(1000000), money.FromBaht(60000))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []AllowanceExplanationRes{
+		{AllowanceType: allowanceType.LifeInsurance, Amount: money.FromBaht(90000), MaxDeduction: money.FromBaht(100000), Deduction: money.FromBaht(90000), CutOff: money.FromBaht(0)},
+		{AllowanceType: allowanceType.HealthInsurance, Amount: money.FromBaht(30000), MaxDeduction: money.FromBaht(25000), GroupCutOff: money.FromBaht(15000), Deduction: money.FromBaht(10000), CutOff: money.FromBaht(20000)},
+		{AllowanceType: allowanceType.ParentHealthInsurance, Amount: money.FromBaht(20000), MaxDeduction: money.FromBaht(15000), Deduction: money.FromBaht(15000), CutOff: money.FromBaht(5000)},
+	}, result)
+	assert.Equal(t, []AllowanceGroupExplanationRes{
+		{AllowanceGroup: "insurance", AllowanceTypes: []string{allowanceType.HealthInsurance, allowanceType.LifeInsurance}, Amount: money.FromBaht(115000), MaxDeduction: money.FromBaht(100000), Deduction: money.FromBaht(100000), CutOff: money.FromBaht(15000)},
+	}, groups)
+}
+
+// explainIncomes
+func mockActualExpense(expense int64) *money.Money {
+	amount := money.FromBaht(expense)
+
+	return &amount
+}
+
+func TestExplainIncomes_ShouldDeductExpenses_WhenCorrectInput(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		incomes              []IncomeReq
+		expectedExplanations []IncomeExplanationRes
+	}{
+		{"Test case 1", []IncomeReq{}, []IncomeExplanationRes{}},
+		{"Test case 2", []IncomeReq{
+			{IncomeType: incomeType.Salary, Amount: money.FromBaht(800000)},
+			{IncomeType: incomeType.Service, Amount: money.FromBaht(300000)},
+		}, []IncomeExplanationRes{
+			{IncomeType: incomeType.Salary, Amount: money.FromBaht(800000), ExpenseMethod: incomeType.ExpenseMethodStandard, ExpenseRate: money.FromPercent(50), ExpenseCutOff: money.FromBaht(300000), Expense: money.FromBaht(100000), NetIncome: money.FromBaht(700000)},
+			{IncomeType: incomeType.Service, Amount: money.FromBaht(300000), ExpenseMethod: incomeType.ExpenseMethodStandard, ExpenseRate: money.FromPercent(50), ExpenseCutOff: money.FromBaht(150000), Expense: money.FromBaht(0), NetIncome: money.FromBaht(300000)},
+		}},
+		{"Test case 3", []IncomeReq{
+			{IncomeType: incomeType.Salary, Amount: money.FromBaht(150000)},
+			{IncomeType: incomeType.Service, Amount: money.FromBaht(100000)},
+		}, []IncomeExplanationRes{
+			{IncomeType: incomeType.Salary, Amount: money.FromBaht(150000), ExpenseMethod: incomeType.ExpenseMethodStandard, ExpenseRate: money.FromPercent(50), Expense: money.FromBaht(75000), NetIncome: money.FromBaht(75000)},
+			{IncomeType: incomeType.Service, Amount: money.FromBaht(100000), ExpenseMethod: incomeType.ExpenseMethodStandard, ExpenseRate: money.FromPercent(50), ExpenseCutOff: money.FromBaht(25000), Expense: money.FromBaht(25000), NetIncome: money.FromBaht(75000)},
+		}},
+		{"Test case 4", []IncomeReq{
+			{IncomeType: incomeType.Rental, Amount: money.FromBaht(200000)},
+			{IncomeType: incomeType.Rental, Amount: money.FromBaht(100000), ActualExpense: mockActualExpense(50000)},
+		}, []IncomeExplanationRes{
+			{IncomeType: incomeType.Rental, Amount: money.FromBaht(200000), ExpenseMethod: incomeType.ExpenseMethodStandard, ExpenseRate: money.FromPercent(30), Expense: money.FromBaht(60000), NetIncome: money.FromBaht(140000)},
+			{IncomeType: incomeType.Rental, Amount: money.FromBaht(100000), ExpenseMethod: incomeType.ExpenseMethodActual, Expense: money.FromBaht(50000), NetIncome: money.FromBaht(50000)},
+		}},
+		{"Test case 5", []IncomeReq{
+			{IncomeType: incomeType.Professional, Amount: money.FromBaht(100000)},
+			{IncomeType: incomeType.Contracting, Amount: money.FromBaht(100000)},
+			{IncomeType: incomeType.Business, Amount: money.FromBaht(100000), ActualExpense: mockActualExpense(120000)},
+		}, []IncomeExplanationRes{
+			{IncomeType: incomeType.Professional, Amount: money.FromBaht(100000), ExpenseMethod: incomeType.ExpenseMethodStandard, ExpenseRate: money.FromPercent(60), Expense: money.FromBaht(60000), NetIncome: money.FromBaht(40000)},
+			{IncomeType: incomeType.Contracting, Amount: money.FromBaht(100000), ExpenseMethod: incomeType.ExpenseMethodStandard, ExpenseRate: money.FromPercent(60), Expense: money.FromBaht(60000), NetIncome: money.FromBaht(40000)},
+			{IncomeType: incomeType.Business, Amount: money.FromBaht(100000), ExpenseMethod: incomeType.ExpenseMethodActual, Expense: money.FromBaht(100000), NetIncome: money.FromBaht(0)},
+		}},
+		{"Test case 6", []IncomeReq{
+			{IncomeType: incomeType.Royalty, Amount: money.FromBaht(300000)},
+			{IncomeType: incomeType.Investment, Amount: money.FromBaht(50000)},
+		}, []IncomeExplanationRes{
+			{IncomeType: incomeType.Royalty, Amount: money.FromBaht(300000), ExpenseMethod: incomeType.ExpenseMethodStandard, ExpenseRate: money.FromPercent(50), ExpenseCutOff: money.FromBaht(50000), Expense: money.FromBaht(100000), NetIncome: money.FromBaht(200000)},
+			{IncomeType: incomeType.Investment, Amount: money.FromBaht(50000), ExpenseMethod: incomeType.ExpenseMethodStandard, Expense: money.FromBaht(0), NetIncome: money.FromBaht(50000)},
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			result, err := explainIncomes(tc.incomes)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedExplanations, result)
+		})
+	}
+}
+
+// explainDependents
+func TestExplainDependents_ShouldDeductFixedAmounts_WhenCorrectInput(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		dependents           DependentsReq
+		expectedExplanations []DependentExplanationRes
+	}{
+		{"Test case 1", DependentsReq{}, []DependentExplanationRes{}},
+		{"Test case 2", DependentsReq{Spouse: &SpouseReq{Income: money.FromBaht(0)}}, []DependentExplanationRes{
+			{DependentType: deductionType.Spouse, Eligible: true, Deduction: money.FromBaht(60000)},
+		}},
+		{"Test case 3", DependentsReq{Spouse: &SpouseReq{Income: money.FromBaht(1)}}, []DependentExplanationRes{
+			{DependentType: deductionType.Spouse},
+		}},
+		{"Test case 4", DependentsReq{Children: []ChildReq{{BirthYear: 2563}, {BirthYear: 2560}, {BirthYear: 2561}}}, []DependentExplanationRes{
+			{DependentType: deductionType.Child, BirthYear: 2560, Eligible: true, Deduction: money.FromBaht(30000)},
+			{DependentType: deductionType.Child, BirthYear: 2561, Eligible: true, Extra: money.FromBaht(30000), Deduction: money.FromBaht(60000)},
+			{DependentType: deductionType.Child, BirthYear: 2563, Eligible: true, Extra: money.FromBaht(30000), Deduction: money.FromBaht(60000)},
+		}},
+		{"Test case 5", DependentsReq{Children: []ChildReq{{BirthYear: 2562}, {BirthYear: 2559}, {BirthYear: 2560}}}, []DependentExplanationRes{
+			{DependentType: deductionType.Child, BirthYear: 2559, Eligible: true, Deduction: money.FromBaht(30000)},
+			{DependentType: deductionType.Child, BirthYear: 2560, Eligible: true, Deduction: money.FromBaht(30000)},
+			{DependentType: deductionType.Child, BirthYear: 2562, Eligible: true, Extra: money.FromBaht(30000), Deduction: money.FromBaht(60000)},
+		}},
+		{"Test case 6", DependentsReq{Parents: []ParentReq{
+			{Age: 60, Income: money.FromBaht(30000)},
+			{Age: 59, Income: money.FromBaht(0)},
+			{Age: 75, Income: money.FromBaht(30001)},
+		}}, []DependentExplanationRes{
+			{DependentType: deductionType.Parent, Age: 60, Eligible: true, Deduction: money.FromBaht(30000)},
+			{DependentType: deductionType.Parent, Age: 59},
+			{DependentType: deductionType.Parent, Age: 75},
+		}},
+		{"Test case 7", DependentsReq{DisabledDependents: 2}, []DependentExplanationRes{
+			{DependentType: deductionType.DisabledDependent, Eligible: true, Deduction: money.FromBaht(60000)},
+			{DependentType: deductionType.DisabledDependent, Eligible: true, Deduction: money.FromBaht(60000)},
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			result := explainDependents(tc.dependents, mockDependentDeduction())
+
+			// Assert
+			assert.Equal(t, tc.expectedExplanations, result)
+		})
+	}
+}
+
 // CalculateTaxDeduction
 func TestCalculateTaxDeduction_ShouldCalculateCorrect_WhenCorrectInput(t *testing.T) {
 	// Arrange
 	calculator := NewTaxCalculatorUseCase(
 		&mockPersonalDeductionUsecase{},
-		&mockKReceiptDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
 	)
 	testCases := []struct {
 		name                 string
-		selfTaxDeduction     float64
-		totalAllowances      float64
-		expectedTaxDeduction float64
+		selfTaxDeduction     money.Money
+		totalAllowances      money.Money
+		expectedTaxDeduction money.Money
 	}{
-		{"Test case 1", 60000.0, 20.0, 60020.0},
-		{"Test case 2", 70000.0, 30000.0, 100000.0}, // Expected tax is 5% of (200000 - 150000) Expected tax is 35% of (3000000 - 2000000) + 300000
+		{"Test case 1", money.FromBaht(60000), money.FromBaht(20), money.FromBaht(60020)},
+		{"Test case 2", money.FromBaht(70000), money.FromBaht(30000), money.FromBaht(100000)}, // Expected tax is 5% of (200000 - 150000) Expected tax is 35% of (3000000 - 2000000) + 300000
 	}
 
 	// Act
@@ -156,13 +513,16 @@ func TestCalculateNetIncome_ShouldCalculateCorrect_WhenCorrectInput(t *testing.T
 	// Arrange
 	calculator := NewTaxCalculatorUseCase(
 		&mockPersonalDeductionUsecase{},
-		&mockKReceiptDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
 	)
 	testCases := []struct {
 		name              string
-		income            float64
-		taxDeduction      float64
-		expectedNetIncome float64
+		income            money.Money
+		taxDeduction      money.Money
+		expectedNetIncome money.Money
 	}{
 		{"Test case 1", 200000, 150000, 50000},
 		{"Test case 2", 200000, 300000, 0},
@@ -184,338 +544,1062 @@ func TestCalculateTax_ShouldCalculateCorrect_WhenCorrectInput(t *testing.T) {
 	// Arrange
 	calculator := NewTaxCalculatorUseCase(
 		&mockPersonalDeductionUsecase{},
-		&mockKReceiptDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
 	)
 	testCases := []struct {
 		name              string
-		netIncome         float64
-		wht               float64
-		expectedTax       float64
-		expectedTaxRefund float64
+		netIncome         money.Money
+		wht               money.Money
+		expectedTax       money.Money
+		expectedTaxRefund money.Money
 		expectedTaxLevel  []TaxLevelRes
 	}{
 		{
 			"Test case 1",
-			100000.0,
-			0.0,
-			0.0,
-			0.0,
+			money.FromBaht(100000),
+			money.FromBaht(0),
+			money.FromBaht(0),
+			money.FromBaht(0),
 			[]TaxLevelRes{
 				{
-					Level: "0-150,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "150,001-500,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "500,001-1,000,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "1,000,001-2,000,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "2,000,001 ขึ้นไป",
-					Tax:   0.0,
+					Level:         "0-150,000",
+					MinIncome:     money.FromBaht(0),
+					MaxIncome:     mockMaxIncome(150000),
+					Rate:          money.FromPercent(0),
+					TaxableIncome: money.FromBaht(100000),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(0),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "150,001-500,000",
+					MinIncome:     money.FromBaht(150000),
+					MaxIncome:     mockMaxIncome(500000),
+					Rate:          money.FromPercent(10),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(0),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "500,001-1,000,000",
+					MinIncome:     money.FromBaht(500000),
+					MaxIncome:     mockMaxIncome(1000000),
+					Rate:          money.FromPercent(15),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(0),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "1,000,001-2,000,000",
+					MinIncome:     money.FromBaht(1000000),
+					MaxIncome:     mockMaxIncome(2000000),
+					Rate:          money.FromPercent(20),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(0),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "2,000,001 ขึ้นไป",
+					MinIncome:     money.FromBaht(2000000),
+					MaxIncome:     nil,
+					Rate:          money.FromPercent(35),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(0),
+					Tax:           money.FromBaht(0),
 				},
 			},
 		},
 		{"Test case 2",
-			200000.0,
-			0.0,
-			5000.0,
-			0.0,
+			money.FromBaht(200000),
+			money.FromBaht(0),
+			money.FromBaht(5000),
+			money.FromBaht(0),
 			[]TaxLevelRes{
 				{
-					Level: "0-150,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "150,001-500,000",
-					Tax:   5000.0,
-				},
-				{
-					Level: "500,001-1,000,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "1,000,001-2,000,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "2,000,001 ขึ้นไป",
-					Tax:   0.0,
+					Level:         "0-150,000",
+					MinIncome:     money.FromBaht(0),
+					MaxIncome:     mockMaxIncome(150000),
+					Rate:          money.FromPercent(0),
+					TaxableIncome: money.FromBaht(150000),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(0),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "150,001-500,000",
+					MinIncome:     money.FromBaht(150000),
+					MaxIncome:     mockMaxIncome(500000),
+					Rate:          money.FromPercent(10),
+					TaxableIncome: money.FromBaht(50000),
+					BracketTax:    money.FromBaht(5000),
+					CumulativeTax: money.FromBaht(5000),
+					Tax:           money.FromBaht(5000),
+				},
+				{
+					Level:         "500,001-1,000,000",
+					MinIncome:     money.FromBaht(500000),
+					MaxIncome:     mockMaxIncome(1000000),
+					Rate:          money.FromPercent(15),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(5000),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "1,000,001-2,000,000",
+					MinIncome:     money.FromBaht(1000000),
+					MaxIncome:     mockMaxIncome(2000000),
+					Rate:          money.FromPercent(20),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(5000),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "2,000,001 ขึ้นไป",
+					MinIncome:     money.FromBaht(2000000),
+					MaxIncome:     nil,
+					Rate:          money.FromPercent(35),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(5000),
+					Tax:           money.FromBaht(0),
 				},
 			},
 		}, // Expected tax is 5% of (200000 - 150000)
 		{"Test case 3",
-			440000.0,
-			0.0,
-			29000.0,
-			0.0,
+			money.FromBaht(440000),
+			money.FromBaht(0),
+			money.FromBaht(29000),
+			money.FromBaht(0),
 			[]TaxLevelRes{
 				{
-					Level: "0-150,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "150,001-500,000",
-					Tax:   29000.0,
-				},
-				{
-					Level: "500,001-1,000,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "1,000,001-2,000,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "2,000,001 ขึ้นไป",
-					Tax:   0.0,
+					Level:         "0-150,000",
+					MinIncome:     money.FromBaht(0),
+					MaxIncome:     mockMaxIncome(150000),
+					Rate:          money.FromPercent(0),
+					TaxableIncome: money.FromBaht(150000),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(0),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "150,001-500,000",
+					MinIncome:     money.FromBaht(150000),
+					MaxIncome:     mockMaxIncome(500000),
+					Rate:          money.FromPercent(10),
+					TaxableIncome: money.FromBaht(290000),
+					BracketTax:    money.FromBaht(29000),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(29000),
+				},
+				{
+					Level:         "500,001-1,000,000",
+					MinIncome:     money.FromBaht(500000),
+					MaxIncome:     mockMaxIncome(1000000),
+					Rate:          money.FromPercent(15),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "1,000,001-2,000,000",
+					MinIncome:     money.FromBaht(1000000),
+					MaxIncome:     mockMaxIncome(2000000),
+					Rate:          money.FromPercent(20),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "2,000,001 ขึ้นไป",
+					MinIncome:     money.FromBaht(2000000),
+					MaxIncome:     nil,
+					Rate:          money.FromPercent(35),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(0),
 				},
 			},
 		}, // Expected tax is 15% of (440000 - 500000) + 35000
 		{"Test case 4",
-			440000.0,
-			25000.0,
-			4000.0,
-			0.0,
+			money.FromBaht(440000),
+			money.FromBaht(25000),
+			money.FromBaht(4000),
+			money.FromBaht(0),
 			[]TaxLevelRes{
 				{
-					Level: "0-150,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "150,001-500,000",
-					Tax:   29000.0,
-				},
-				{
-					Level: "500,001-1,000,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "1,000,001-2,000,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "2,000,001 ขึ้นไป",
-					Tax:   0.0,
+					Level:         "0-150,000",
+					MinIncome:     money.FromBaht(0),
+					MaxIncome:     mockMaxIncome(150000),
+					Rate:          money.FromPercent(0),
+					TaxableIncome: money.FromBaht(150000),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(0),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "150,001-500,000",
+					MinIncome:     money.FromBaht(150000),
+					MaxIncome:     mockMaxIncome(500000),
+					Rate:          money.FromPercent(10),
+					TaxableIncome: money.FromBaht(290000),
+					BracketTax:    money.FromBaht(29000),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(29000),
+				},
+				{
+					Level:         "500,001-1,000,000",
+					MinIncome:     money.FromBaht(500000),
+					MaxIncome:     mockMaxIncome(1000000),
+					Rate:          money.FromPercent(15),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "1,000,001-2,000,000",
+					MinIncome:     money.FromBaht(1000000),
+					MaxIncome:     mockMaxIncome(2000000),
+					Rate:          money.FromPercent(20),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "2,000,001 ขึ้นไป",
+					MinIncome:     money.FromBaht(2000000),
+					MaxIncome:     nil,
+					Rate:          money.FromPercent(35),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(0),
 				},
 			},
 		},
 		{"Test case 5",
-			440000.0,
-			29000.0,
-			0.0,
-			0.0,
+			money.FromBaht(440000),
+			money.FromBaht(29000),
+			money.FromBaht(0),
+			money.FromBaht(0),
 			[]TaxLevelRes{
 				{
-					Level: "0-150,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "150,001-500,000",
-					Tax:   29000.0,
-				},
-				{
-					Level: "500,001-1,000,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "1,000,001-2,000,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "2,000,001 ขึ้นไป",
-					Tax:   0.0,
+					Level:         "0-150,000",
+					MinIncome:     money.FromBaht(0),
+					MaxIncome:     mockMaxIncome(150000),
+					Rate:          money.FromPercent(0),
+					TaxableIncome: money.FromBaht(150000),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(0),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "150,001-500,000",
+					MinIncome:     money.FromBaht(150000),
+					MaxIncome:     mockMaxIncome(500000),
+					Rate:          money.FromPercent(10),
+					TaxableIncome: money.FromBaht(290000),
+					BracketTax:    money.FromBaht(29000),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(29000),
+				},
+				{
+					Level:         "500,001-1,000,000",
+					MinIncome:     money.FromBaht(500000),
+					MaxIncome:     mockMaxIncome(1000000),
+					Rate:          money.FromPercent(15),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "1,000,001-2,000,000",
+					MinIncome:     money.FromBaht(1000000),
+					MaxIncome:     mockMaxIncome(2000000),
+					Rate:          money.FromPercent(20),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "2,000,001 ขึ้นไป",
+					MinIncome:     money.FromBaht(2000000),
+					MaxIncome:     nil,
+					Rate:          money.FromPercent(35),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(0),
 				},
 			},
 		},
 		{
 			"Test case 6",
-			440000,
-			39000,
-			0,
-			10000,
+			money.FromBaht(440000),
+			money.FromBaht(39000),
+			money.FromBaht(0),
+			money.FromBaht(10000),
 			[]TaxLevelRes{
 				{
-					Level: "0-150,000",
-					Tax:   0.0,
+					Level:         "0-150,000",
+					MinIncome:     money.FromBaht(0),
+					MaxIncome:     mockMaxIncome(150000),
+					Rate:          money.FromPercent(0),
+					TaxableIncome: money.FromBaht(150000),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(0),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "150,001-500,000",
+					MinIncome:     money.FromBaht(150000),
+					MaxIncome:     mockMaxIncome(500000),
+					Rate:          money.FromPercent(10),
+					TaxableIncome: money.FromBaht(290000),
+					BracketTax:    money.FromBaht(29000),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(29000),
+				},
+				{
+					Level:         "500,001-1,000,000",
+					MinIncome:     money.FromBaht(500000),
+					MaxIncome:     mockMaxIncome(1000000),
+					Rate:          money.FromPercent(15),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "1,000,001-2,000,000",
+					MinIncome:     money.FromBaht(1000000),
+					MaxIncome:     mockMaxIncome(2000000),
+					Rate:          money.FromPercent(20),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "2,000,001 ขึ้นไป",
+					MinIncome:     money.FromBaht(2000000),
+					MaxIncome:     nil,
+					Rate:          money.FromPercent(35),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(29000),
+					Tax:           money.FromBaht(0),
 				},
+			},
+		},
+		{"Test case 7",
+			money.FromBaht(600000),
+			money.FromBaht(0),
+			money.FromBaht(50000),
+			money.FromBaht(0),
+			[]TaxLevelRes{
 				{
-					Level: "150,001-500,000",
-					Tax:   29000.0,
+					Level:         "0-150,000",
+					MinIncome:     money.FromBaht(0),
+					MaxIncome:     mockMaxIncome(150000),
+					Rate:          money.FromPercent(0),
+					TaxableIncome: money.FromBaht(150000),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(0),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "150,001-500,000",
+					MinIncome:     money.FromBaht(150000),
+					MaxIncome:     mockMaxIncome(500000),
+					Rate:          money.FromPercent(10),
+					TaxableIncome: money.FromBaht(350000),
+					BracketTax:    money.FromBaht(35000),
+					CumulativeTax: money.FromBaht(35000),
+					Tax:           money.FromBaht(35000),
+				},
+				{
+					Level:         "500,001-1,000,000",
+					MinIncome:     money.FromBaht(500000),
+					MaxIncome:     mockMaxIncome(1000000),
+					Rate:          money.FromPercent(15),
+					TaxableIncome: money.FromBaht(100000),
+					BracketTax:    money.FromBaht(15000),
+					CumulativeTax: money.FromBaht(50000),
+					Tax:           money.FromBaht(50000),
+				},
+				{
+					Level:         "1,000,001-2,000,000",
+					MinIncome:     money.FromBaht(1000000),
+					MaxIncome:     mockMaxIncome(2000000),
+					Rate:          money.FromPercent(20),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(50000),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "2,000,001 ขึ้นไป",
+					MinIncome:     money.FromBaht(2000000),
+					MaxIncome:     nil,
+					Rate:          money.FromPercent(35),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(50000),
+					Tax:           money.FromBaht(0),
 				},
+			},
+		}, // Expected tax is 15% of (600000 - 500000) + 35000
+		{"Test case 8",
+			money.FromBaht(750000),
+			money.FromBaht(0),
+			money.FromBaht(72500),
+			money.FromBaht(0),
+			[]TaxLevelRes{
 				{
-					Level: "500,001-1,000,000",
-					Tax:   0.0,
+					Level:         "0-150,000",
+					MinIncome:     money.FromBaht(0),
+					MaxIncome:     mockMaxIncome(150000),
+					Rate:          money.FromPercent(0),
+					TaxableIncome: money.FromBaht(150000),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(0),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "150,001-500,000",
+					MinIncome:     money.FromBaht(150000),
+					MaxIncome:     mockMaxIncome(500000),
+					Rate:          money.FromPercent(10),
+					TaxableIncome: money.FromBaht(350000),
+					BracketTax:    money.FromBaht(35000),
+					CumulativeTax: money.FromBaht(35000),
+					Tax:           money.FromBaht(35000),
+				},
+				{
+					Level:         "500,001-1,000,000",
+					MinIncome:     money.FromBaht(500000),
+					MaxIncome:     mockMaxIncome(1000000),
+					Rate:          money.FromPercent(15),
+					TaxableIncome: money.FromBaht(250000),
+					BracketTax:    money.FromBaht(37500),
+					CumulativeTax: money.FromBaht(72500),
+					Tax:           money.FromBaht(72500),
+				},
+				{
+					Level:         "1,000,001-2,000,000",
+					MinIncome:     money.FromBaht(1000000),
+					MaxIncome:     mockMaxIncome(2000000),
+					Rate:          money.FromPercent(20),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(72500),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "2,000,001 ขึ้นไป",
+					MinIncome:     money.FromBaht(2000000),
+					MaxIncome:     nil,
+					Rate:          money.FromPercent(35),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(72500),
+					Tax:           money.FromBaht(0),
 				},
+			},
+		}, // Expected tax is 15% of (750000 - 500000) + 35000
+		{"Test case 9",
+			money.FromBaht(1500000),
+			money.FromBaht(0),
+			money.FromBaht(210000),
+			money.FromBaht(0),
+			[]TaxLevelRes{
 				{
-					Level: "1,000,001-2,000,000",
-					Tax:   0.0,
+					Level:         "0-150,000",
+					MinIncome:     money.FromBaht(0),
+					MaxIncome:     mockMaxIncome(150000),
+					Rate:          money.FromPercent(0),
+					TaxableIncome: money.FromBaht(150000),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(0),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "150,001-500,000",
+					MinIncome:     money.FromBaht(150000),
+					MaxIncome:     mockMaxIncome(500000),
+					Rate:          money.FromPercent(10),
+					TaxableIncome: money.FromBaht(350000),
+					BracketTax:    money.FromBaht(35000),
+					CumulativeTax: money.FromBaht(35000),
+					Tax:           money.FromBaht(35000),
+				},
+				{
+					Level:         "500,001-1,000,000",
+					MinIncome:     money.FromBaht(500000),
+					MaxIncome:     mockMaxIncome(1000000),
+					Rate:          money.FromPercent(15),
+					TaxableIncome: money.FromBaht(500000),
+					BracketTax:    money.FromBaht(75000),
+					CumulativeTax: money.FromBaht(110000),
+					Tax:           money.FromBaht(110000),
+				},
+				{
+					Level:         "1,000,001-2,000,000",
+					MinIncome:     money.FromBaht(1000000),
+					MaxIncome:     mockMaxIncome(2000000),
+					Rate:          money.FromPercent(20),
+					TaxableIncome: money.FromBaht(500000),
+					BracketTax:    money.FromBaht(100000),
+					CumulativeTax: money.FromBaht(210000),
+					Tax:           money.FromBaht(210000),
+				},
+				{
+					Level:         "2,000,001 ขึ้นไป",
+					MinIncome:     money.FromBaht(2000000),
+					MaxIncome:     nil,
+					Rate:          money.FromPercent(35),
+					TaxableIncome: money.FromBaht(0),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(210000),
+					Tax:           money.FromBaht(0),
 				},
+			},
+		}, // Expected tax is 20% of (1500000 - 1000000) + 110000
+		{"Test case 10",
+			money.FromBaht(3000000),
+			money.FromBaht(0),
+			money.FromBaht(660000),
+			money.FromBaht(0),
+			[]TaxLevelRes{
 				{
-					Level: "2,000,001 ขึ้นไป",
-					Tax:   0.0,
+					Level:         "0-150,000",
+					MinIncome:     money.FromBaht(0),
+					MaxIncome:     mockMaxIncome(150000),
+					Rate:          money.FromPercent(0),
+					TaxableIncome: money.FromBaht(150000),
+					BracketTax:    money.FromBaht(0),
+					CumulativeTax: money.FromBaht(0),
+					Tax:           money.FromBaht(0),
+				},
+				{
+					Level:         "150,001-500,000",
+					MinIncome:     money.FromBaht(150000),
+					MaxIncome:     mockMaxIncome(500000),
+					Rate:          money.FromPercent(10),
+					TaxableIncome: money.FromBaht(350000),
+					BracketTax:    money.FromBaht(35000),
+					CumulativeTax: money.FromBaht(35000),
+					Tax:           money.FromBaht(35000),
+				},
+				{
+					Level:         "500,001-1,000,000",
+					MinIncome:     money.FromBaht(500000),
+					MaxIncome:     mockMaxIncome(1000000),
+					Rate:          money.FromPercent(15),
+					TaxableIncome: money.FromBaht(500000),
+					BracketTax:    money.FromBaht(75000),
+					CumulativeTax: money.FromBaht(110000),
+					Tax:           money.FromBaht(110000),
+				},
+				{
+					Level:         "1,000,001-2,000,000",
+					MinIncome:     money.FromBaht(1000000),
+					MaxIncome:     mockMaxIncome(2000000),
+					Rate:          money.FromPercent(20),
+					TaxableIncome: money.FromBaht(1000000),
+					BracketTax:    money.FromBaht(200000),
+					CumulativeTax: money.FromBaht(310000),
+					Tax:           money.FromBaht(310000),
+				},
+				{
+					Level:         "2,000,001 ขึ้นไป",
+					MinIncome:     money.FromBaht(2000000),
+					MaxIncome:     nil,
+					Rate:          money.FromPercent(35),
+					TaxableIncome: money.FromBaht(1000000),
+					BracketTax:    money.FromBaht(350000),
+					CumulativeTax: money.FromBaht(660000),
+					Tax:           money.FromBaht(660000),
+				},
+			},
+		}, // Expected tax is 35% of (3000000 - 2000000) + 310000
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tax, taxRefund, taxLevel, err := calculator.CalculateTax(tc.netIncome, tc.wht, mockTaxBrackets)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTax, tax)
+			assert.Equal(t, tc.expectedTaxRefund, taxRefund)
+			assert.Equal(t, tc.expectedTaxLevel, taxLevel)
+		})
+	}
+}
+
+func TestCalculateTax_ShouldTruncateToSatang_WhenTaxHasFractionOfSatang(t *testing.T) {
+	// Arrange
+	calculator := NewTaxCalculatorUseCase(
+		&mockPersonalDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+	netIncome, _ := money.Parse("150100.99")
+
+	// Act
+	tax, taxRefund, _, err := calculator.CalculateTax(netIncome, money.FromBaht(0), mockTaxBrackets)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, money.Money(1009), tax) // 10% of 100.99 is 10.099
+	assert.Equal(t, money.FromBaht(0), taxRefund)
+}
+
+func TestCalculateMinimumTax_ShouldCalculateCorrect_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	calculator := NewTaxCalculatorUseCase(
+		&mockPersonalDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+
+	testCases := []struct {
+		name     string
+		incomes  []IncomeReq
+		expected money.Money
+	}{
+		{"Test case 1", []IncomeReq{}, money.FromBaht(0)},
+		{"Test case 2", []IncomeReq{
+			{IncomeType: incomeType.Salary, Amount: money.FromBaht(5000000)},
+		}, money.FromBaht(0)},
+		{"Test case 3", []IncomeReq{
+			{IncomeType: incomeType.Service, Amount: money.FromBaht(120000)},
+		}, money.FromBaht(0)},
+		{"Test case 4", []IncomeReq{
+			{IncomeType: incomeType.Service, Amount: money.FromBaht(120001)},
+		}, money.Money(60000)}, // 0.5% of 120,001 is 600.005
+		{"Test case 5", []IncomeReq{
+			{IncomeType: incomeType.Salary, Amount: money.FromBaht(1000000)},
+			{IncomeType: incomeType.Rental, Amount: money.FromBaht(100000)},
+			{IncomeType: incomeType.Business, Amount: money.FromBaht(100000)},
+		}, money.FromBaht(1000)},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := calculator.CalculateMinimumTax(tc.incomes)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+// TestCalculate
+
+type mockPersonalDeductionUsecaseGetDeductionNotFound struct {
+}
+
+func (p *mockPersonalDeductionUsecaseGetDeductionNotFound) GetDeduction(taxYear int) (money.Money, error) {
+	return money.FromBaht(0), errors.New("Not found")
+}
+
+func (p *mockPersonalDeductionUsecaseGetDeductionNotFound) UpdateDeduction(req personal.UpdatePersonalDeductionReq) (personal.UpdatePersonalDeductionRes, error) {
+	return personal.UpdatePersonalDeductionRes{}, nil
+}
+
+func TestCalculate_ShouldReturnErr_WhenGetPersonalDeductionNotFound(t *testing.T) {
+	// Arrange
+	calculator := NewTaxCalculatorUseCase(
+		&mockPersonalDeductionUsecaseGetDeductionNotFound{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+
+	req := TaxCalculatorReq{
+		TotalIncome: money.FromBaht(500000),
+		WHT:         money.FromBaht(0),
+		Allowances: []AllowanceReq{
+			{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(0)},
+		},
+	}
+
+	// Act
+	result, err := calculator.Calculate(req)
+
+	// Assert
+	assert.Equal(t, money.FromBaht(0), result.Tax)
+	assert.Equal(t, money.FromBaht(0), result.TaxRefund)
+	assert.Error(t, err)
+}
+
+func TestCalculate_ShouldReturnErrTaxYearNotSupported_WhenGetDependentDeductionNotFound(t *testing.T) {
+	// Arrange
+	calculator := NewTaxCalculatorUseCase(
+		&mockPersonalDeductionUsecase{},
+		&mockDependentDeductionUsecase{err: deduction.ErrDeductionNotFound},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+
+	req := TaxCalculatorReq{
+		TotalIncome: money.FromBaht(500000),
+		Allowances:  []AllowanceReq{},
+	}
+
+	// Act
+	_, err := calculator.Calculate(req)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrTaxYearNotSupported)
+}
+
+func TestCalculate_ShouldReturnErrOverflow_WhenAmountTooLarge(t *testing.T) {
+	// Arrange
+	calculator := NewTaxCalculatorUseCase(
+		&mockPersonalDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+	corporateTaxRate := money.Rate(9999)
+
+	req := TaxCalculatorReq{
+		TotalIncome: money.FromBaht(500000),
+		Allowances:  []AllowanceReq{},
+		Dividend:    &DividendReq{Amount: money.FromBaht(100000000000000), TaxOption: investmentIncome.OptionInclude, CorporateTaxRate: &corporateTaxRate},
+	}
+
+	// Act
+	_, err := calculator.Calculate(req)
+
+	// Assert
+	assert.ErrorIs(t, err, money.ErrOverflow)
+}
+
+func TestCalculate_ShouldDeductDependentsBeforeAllowances_WhenDependentsAreGiven(t *testing.T) {
+	// Arrange
+	calculator := NewTaxCalculatorUseCase(
+		&mockPersonalDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+
+	req := TaxCalculatorReq{
+		TotalIncome: money.FromBaht(500000),
+		Allowances: []AllowanceReq{
+			{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(100000)},
+		},
+		Dependents: DependentsReq{
+			Spouse:   &SpouseReq{Income: money.FromBaht(0)},
+			Children: []ChildReq{{BirthYear: 2560}, {BirthYear: 2562}},
+			Parents:  []ParentReq{{Age: 58, Income: money.FromBaht(0)}},
+		},
+	}
+
+	// Act
+	result, err := calculator.Calculate(req)
+
+	// Assert
+	// 500,000 - 60,000 (personal) - 150,000 (spouse and children) - 29,000 (donation, 10% of 290,000) = 261,000
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromBaht(150000), result.Explanation.TotalDependents)
+	assert.Equal(t, money.FromBaht(29000), result.Explanation.TotalAllowances)
+	assert.Equal(t, money.FromBaht(239000), result.Explanation.TotalDeduction)
+	assert.Equal(t, money.FromBaht(261000), result.Explanation.NetIncome)
+	assert.Equal(t, money.FromBaht(11100), result.Tax)
+}
+
+func TestCalculate_ShouldDeductExpenses_WhenIncomesAreGiven(t *testing.T) {
+	// Arrange
+	calculator := NewTaxCalculatorUseCase(
+		&mockPersonalDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+
+	req := TaxCalculatorReq{
+		TotalIncome: money.FromBaht(100000),
+		Allowances: []AllowanceReq{
+			{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(100000)},
+		},
+		Incomes: []IncomeReq{
+			{IncomeType: incomeType.Salary, Amount: money.FromBaht(600000)},
+			{IncomeType: incomeType.Rental, Amount: money.FromBaht(100000)},
+		},
+	}
+
+	// Act
+	result, err := calculator.Calculate(req)
+
+	// Assert
+	// 800,000 - 130,000 (expenses) - 60,000 (personal) - 61,000 (donation, 10% of 610,000) = 549,000
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromBaht(800000), result.Explanation.TotalIncome)
+	assert.Equal(t, money.FromBaht(130000), result.Explanation.TotalExpenses)
+	assert.Equal(t, money.FromBaht(61000), result.Explanation.TotalAllowances)
+	assert.Equal(t, money.FromBaht(549000), result.Explanation.NetIncome)
+	assert.Equal(t, money.FromBaht(42350), result.Tax)
+}
+
+func TestCalculate_ShouldPayHigherMethod_WhenNonSalaryIncomeIsOverThreshold(t *testing.T) {
+	// Arrange
+	calculator := NewTaxCalculatorUseCase(
+		&mockPersonalDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+
+	testCases := []struct {
+		name                   string
+		actualExpense          *money.Money
+		expectedMethod         string
+		expectedProgressiveTax money.Money
+		expectedTax            money.Money
+	}{
+		// 2,000,000 - 1,900,000 (actual expense) - 60,000 (personal) = 40,000
+		{"Test case 1", mockActualExpense(1900000), taxMethod.Minimum, money.FromBaht(0), money.FromBaht(7000)},
+		// 2,000,000 - 1,200,000 (60% expense) - 60,000 (personal) = 740,000
+		{"Test case 2", nil, taxMethod.Progressive, money.FromBaht(71000), money.FromBaht(68000)},
+	}
+
+	// Act
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := calculator.Calculate(TaxCalculatorReq{
+				WHT:        money.FromBaht(3000),
+				Allowances: []AllowanceReq{},
+				Incomes: []IncomeReq{
+					{IncomeType: incomeType.Business, Amount: money.FromBaht(2000000), ActualExpense: tc.actualExpense},
 				},
-			},
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedMethod, result.TaxMethod)
+			assert.Equal(t, tc.expectedProgressiveTax, result.ProgressiveTax)
+			assert.Equal(t, money.FromBaht(10000), result.MinimumTax)
+			assert.Equal(t, tc.expectedTax, result.Tax)
+			assert.Equal(t, money.FromBaht(2000000), result.Explanation.NonSalaryIncome)
+			assert.Equal(t, tc.expectedTax+money.FromBaht(3000), result.Explanation.TotalTax)
+		})
+	}
+}
+
+func TestCalculate_ShouldTakeDividendAmountAsMinimumTaxBase_WhenDividendIsIncluded(t *testing.T) {
+	// Arrange
+	calculator := NewTaxCalculatorUseCase(
+		&mockPersonalDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+
+	req := TaxCalculatorReq{
+		Allowances: []AllowanceReq{},
+		Incomes: []IncomeReq{
+			{IncomeType: incomeType.Business, Amount: money.FromBaht(2000000), ActualExpense: mockActualExpense(1990000)},
 		},
-		{"Test case 7",
-			600000.0,
-			0,
-			50000.0,
-			0,
-			[]TaxLevelRes{
-				{
-					Level: "0-150,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "150,001-500,000",
-					Tax:   35000.0,
-				},
-				{
-					Level: "500,001-1,000,000",
-					Tax:   50000.0,
-				},
-				{
-					Level: "1,000,001-2,000,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "2,000,001 ขึ้นไป",
-					Tax:   0.0,
-				},
-			},
-		}, // Expected tax is 15% of (600000 - 500000) + 35000
-		{"Test case 8",
-			750000.0,
-			0.0,
-			72500.0,
-			0.0,
-			[]TaxLevelRes{
-				{
-					Level: "0-150,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "150,001-500,000",
-					Tax:   35000.0,
-				},
-				{
-					Level: "500,001-1,000,000",
-					Tax:   72500.0,
-				},
-				{
-					Level: "1,000,001-2,000,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "2,000,001 ขึ้นไป",
-					Tax:   0.0,
-				},
-			},
-		}, // Expected tax is 15% of (750000 - 500000) + 35000
-		{"Test case 9",
-			1500000.0,
-			0.0,
-			200000.0,
-			0.0,
-			[]TaxLevelRes{
-				{
-					Level: "0-150,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "150,001-500,000",
-					Tax:   35000.0,
-				},
-				{
-					Level: "500,001-1,000,000",
-					Tax:   100000.0,
-				},
-				{
-					Level: "1,000,001-2,000,000",
-					Tax:   200000.0,
-				},
-				{
-					Level: "2,000,001 ขึ้นไป",
-					Tax:   0.0,
-				},
-			},
-		}, // Expected tax is 20% of (1500000 - 1000000) + 100000
-		{"Test case 10",
-			3000000.0,
-			0.0,
-			650000.0,
-			0.0,
-			[]TaxLevelRes{
-				{
-					Level: "0-150,000",
-					Tax:   0.0,
-				},
-				{
-					Level: "150,001-500,000",
-					Tax:   35000.0,
-				},
-				{
-					Level: "500,001-1,000,000",
-					Tax:   100000.0,
-				},
-				{
-					Level: "1,000,001-2,000,000",
-					Tax:   300000.0,
-				},
-				{
-					Level: "2,000,001 ขึ้นไป",
-					Tax:   650000.0,
-				},
-			},
-		}, // Expected tax is 35% of (3000000 - 2000000) + 300000
+		Dividend: &DividendReq{Amount: money.FromBaht(100000), TaxOption: investmentIncome.OptionInclude},
+	}
+
+	// Act
+	result, err := calculator.Calculate(req)
+
+	// Assert
+	assert.NoError(t, err)
+	// 2,000,000 + 125,000 (dividend grossed up) - 1,990,000 (actual expense) - 60,000 (personal) = 75,000
+	assert.Equal(t, money.FromBaht(0), result.ProgressiveTax)
+	// 0.5% of 2,000,000 + 100,000 (dividend without the 25,000 tax credit)
+	assert.Equal(t, taxMethod.Minimum, result.TaxMethod)
+	assert.Equal(t, money.FromBaht(10500), result.MinimumTax)
+	assert.Equal(t, money.FromBaht(2100000), result.Explanation.NonSalaryIncome)
+	// 10,500 - 10,000 (withholding tax) - 25,000 (tax credit)
+	assert.Equal(t, money.FromBaht(24500), result.TaxRefund)
+}
+
+func TestCalculate_ShouldPickCheaperTaxOption_WhenTaxOptionIsAuto(t *testing.T) {
+	// Arrange
+	calculator := NewTaxCalculatorUseCase(
+		&mockPersonalDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+
+	testCases := []struct {
+		name                  string
+		req                   TaxCalculatorReq
+		expectedOption        string
+		expectedTaxIfFinal    money.Money
+		expectedTaxIfIncluded money.Money
+		expectedTax           money.Money
+		expectedTaxRefund     money.Money
+	}{
+		// included dividend is 100,000 with 20,000 credit, net income 40,000 has no tax so 28,000 is refunded
+		{"Test case 1", TaxCalculatorReq{
+			Allowances: []AllowanceReq{},
+			Dividend:   &DividendReq{Amount: money.FromBaht(80000), TaxOption: investmentIncome.OptionAuto},
+		}, investmentIncome.OptionInclude, money.FromBaht(0), money.FromBaht(-28000), money.FromBaht(0), money.FromBaht(28000)},
+		// included interest is taxed at 35% which is more than the 15% withheld
+		{"Test case 2", TaxCalculatorReq{
+			TotalIncome: money.FromBaht(2000000),
+			Allowances:  []AllowanceReq{},
+			Interest:    &InterestReq{Amount: money.FromBaht(100000), TaxOption: investmentIncome.OptionAuto},
+		}, investmentIncome.OptionFinal, money.FromBaht(298000), money.FromBaht(309000), money.FromBaht(298000), money.FromBaht(0)},
 	}
 
 	// Act
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			tax, taxRefund, taxLevel := calculator.CalculateTax(tc.netIncome, tc.wht)
+			result, err := calculator.Calculate(tc.req)
 
 			// Assert
-			assert.Equal(t, tc.expectedTax, tax)
-			assert.Equal(t, tc.expectedTaxRefund, taxRefund)
-			assert.Equal(t, tc.expectedTaxLevel, taxLevel)
+			assert.NoError(t, err)
+			assert.Len(t, result.InvestmentIncomes, 1)
+			assert.Equal(t, investmentIncome.OptionAuto, result.InvestmentIncomes[0].RequestedOption)
+			assert.Equal(t, tc.expectedOption, result.InvestmentIncomes[0].TaxOption)
+			assert.Equal(t, &tc.expectedTaxIfFinal, result.InvestmentIncomes[0].TaxIfFinal)
+			assert.Equal(t, &tc.expectedTaxIfIncluded, result.InvestmentIncomes[0].TaxIfIncluded)
+			assert.Equal(t, tc.expectedTax, result.Tax)
+			assert.Equal(t, tc.expectedTaxRefund, result.TaxRefund)
 		})
 	}
 }
 
-// TestCalculate
+func TestCalculate_ShouldUseRequestedTaxOption_WhenTaxOptionIsGiven(t *testing.T) {
+	// Arrange
+	calculator := NewTaxCalculatorUseCase(
+		&mockPersonalDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+	corporateTaxRate := money.FromPercent(30)
 
-type mockPersonalDeductionUsecaseGetDeductionNotFound struct {
+	req := TaxCalculatorReq{
+		TotalIncome: money.FromBaht(500000),
+		Allowances:  []AllowanceReq{},
+		Interest:    &InterestReq{Amount: money.FromBaht(100000), TaxOption: investmentIncome.OptionInclude},
+		Dividend:    &DividendReq{Amount: money.FromBaht(70000), TaxOption: investmentIncome.OptionFinal, CorporateTaxRate: &corporateTaxRate},
+	}
+
+	expected := []InvestmentIncomeRes{
+		{
+			IncomeType:       investmentIncome.Interest,
+			Amount:           money.FromBaht(100000),
+			RequestedOption:  investmentIncome.OptionInclude,
+			TaxOption:        investmentIncome.OptionInclude,
+			WithholdingRate:  money.FromPercent(15),
+			WithholdingTax:   money.FromBaht(15000),
+			TaxCredit:        money.FromBaht(0),
+			AssessableIncome: money.FromBaht(100000),
+		},
+		{
+			IncomeType:       investmentIncome.Dividend,
+			Amount:           money.FromBaht(70000),
+			RequestedOption:  investmentIncome.OptionFinal,
+			TaxOption:        investmentIncome.OptionFinal,
+			WithholdingRate:  money.FromPercent(10),
+			WithholdingTax:   money.FromBaht(7000),
+			TaxCredit:        money.FromBaht(0),
+			AssessableIncome: money.FromBaht(0),
+		},
+	}
+
+	// Act
+	result, err := calculator.Calculate(req)
+
+	// Assert
+	// 600,000 - 60,000 (personal) = 540,000 has 41,000 tax, 15,000 withheld from interest is credited
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result.InvestmentIncomes)
+	assert.Equal(t, []IncomeExplanationRes{
+		{IncomeType: incomeType.Investment, Amount: money.FromBaht(100000), ExpenseMethod: incomeType.ExpenseMethodStandard, Expense: money.FromBaht(0), NetIncome: money.FromBaht(100000)},
+	}, result.Explanation.Incomes)
+	assert.Equal(t, money.FromBaht(15000), result.Explanation.TaxCredits)
+	assert.Equal(t, money.FromBaht(26000), result.Tax)
 }
 
-func (p *mockPersonalDeductionUsecaseGetDeductionNotFound) GetDeduction() (float64, error) {
-	return 0.0, errors.New("Not found")
+type mockAllowanceDeductionUsecaseGetMaxDeductionNotFound struct {
 }
 
-func (p *mockPersonalDeductionUsecaseGetDeductionNotFound) UpdateDeduction(req personal.UpdatePersonalDeductionReq) (personal.UpdatePersonalDeductionRes, error) {
-	return personal.UpdatePersonalDeductionRes{}, nil
+func (p *mockAllowanceDeductionUsecaseGetMaxDeductionNotFound) GetMaxDeduction(taxYear int, key string) (money.Money, error) {
+	return money.FromBaht(0), errors.New("Not found")
 }
 
-func TestCalculate_ShouldReturnErr_WhenGetPersonalDeductionNotFound(t *testing.T) {
+func (p *mockAllowanceDeductionUsecaseGetMaxDeductionNotFound) GetAllowanceTypes(taxYear int) (allowance.AllowanceTypesRes, error) {
+	return allowance.AllowanceTypesRes{}, nil
+}
+
+func (p *mockAllowanceDeductionUsecaseGetMaxDeductionNotFound) UpdateMaxDeduction(key string, req allowance.UpdateAllowanceDeductionReq) (allowance.UpdateAllowanceDeductionRes, error) {
+	return allowance.UpdateAllowanceDeductionRes{}, nil
+}
+
+func (p *mockAllowanceDeductionUsecaseGetMaxDeductionNotFound) UpdateMaxDeductions(taxYear int, maxDeductions []deduction.Deduction) error {
+	return nil
+}
+
+func (p *mockAllowanceDeductionUsecaseGetMaxDeductionNotFound) GetCapRate(taxYear int, key string) (money.Rate, error) {
+	return 0, nil
+}
+
+func (p *mockAllowanceDeductionUsecaseGetMaxDeductionNotFound) UpdateCapRate(key string, req allowance.UpdateAllowanceCapRateReq) (allowance.UpdateAllowanceCapRateRes, error) {
+	return allowance.UpdateAllowanceCapRateRes{}, nil
+}
+
+func TestCalculate_ShouldReturnErr_WhenGetMaxDeductionNotFound(t *testing.T) {
 	// Arrange
 	calculator := NewTaxCalculatorUseCase(
-		&mockPersonalDeductionUsecaseGetDeductionNotFound{},
-		&mockKReceiptDeductionUsecase{},
+		&mockPersonalDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecaseGetMaxDeductionNotFound{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
 	)
 
 	req := TaxCalculatorReq{
-		TotalIncome: 500000.0,
-		WHT:         0.0,
+		TotalIncome: money.FromBaht(500000),
+		WHT:         money.FromBaht(0),
 		Allowances: []AllowanceReq{
-			{AllowanceType: allowanceType.Donation, Amount: 0.0},
+			{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(0)},
 		},
 	}
 
@@ -523,34 +1607,120 @@ func TestCalculate_ShouldReturnErr_WhenGetPersonalDeductionNotFound(t *testing.T
 	result, err := calculator.Calculate(req)
 
 	// Assert
-	assert.Equal(t, 0.0, result.Tax)
-	assert.Equal(t, 0.0, result.TaxRefund)
+	assert.Equal(t, money.FromBaht(0), result.Tax)
+	assert.Equal(t, money.FromBaht(0), result.TaxRefund)
 	assert.Error(t, err)
 }
 
-type mockKReceiptDeductionUsecaseGetDeductionNotFound struct {
+// mockAllowanceDeductionUsecaseCaseCapRate gives the donation cap rate of a tax year, or err
+type mockAllowanceDeductionUsecaseCaseCapRate struct {
+	capRate money.Rate
+	err     error
+}
+
+func (p *mockAllowanceDeductionUsecaseCaseCapRate) GetMaxDeduction(taxYear int, key string) (money.Money, error) {
+	return money.FromBaht(100000), nil
+}
+
+func (p *mockAllowanceDeductionUsecaseCaseCapRate) GetAllowanceTypes(taxYear int) (allowance.AllowanceTypesRes, error) {
+	return allowance.AllowanceTypesRes{}, nil
+}
+
+func (p *mockAllowanceDeductionUsecaseCaseCapRate) UpdateMaxDeduction(key string, req allowance.UpdateAllowanceDeductionReq) (allowance.UpdateAllowanceDeductionRes, error) {
+	return allowance.UpdateAllowanceDeductionRes{}, nil
+}
+
+func (p *mockAllowanceDeductionUsecaseCaseCapRate) UpdateMaxDeductions(taxYear int, maxDeductions []deduction.Deduction) error {
+	return nil
+}
+
+func (p *mockAllowanceDeductionUsecaseCaseCapRate) GetCapRate(taxYear int, key string) (money.Rate, error) {
+	return p.capRate, p.err
+}
+
+func (p *mockAllowanceDeductionUsecaseCaseCapRate) UpdateCapRate(key string, req allowance.UpdateAllowanceCapRateReq) (allowance.UpdateAllowanceCapRateRes, error) {
+	return allowance.UpdateAllowanceCapRateRes{}, nil
+}
+
+func TestCalculate_ShouldCapDonationAtSettingRate_WhenCapRateIsSet(t *testing.T) {
+	// Arrange
+	calculator := NewTaxCalculatorUseCase(
+		&mockPersonalDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecaseCaseCapRate{capRate: money.FromPercent(20)},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+
+	req := TaxCalculatorReq{
+		TotalIncome: money.FromBaht(500000),
+		Allowances: []AllowanceReq{
+			{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(100000)},
+		},
+	}
+
+	// Act
+	result, err := calculator.Calculate(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromPercent(20), result.Explanation.Allowances[0].CapRate)
+	assert.Equal(t, money.FromBaht(88000), result.Explanation.Allowances[0].Deduction) // 20% of 500,000 - 60,000
+	assert.Equal(t, money.FromBaht(20200), result.Tax)                                 // 10% of 352,000 - 150,000
+}
+
+func TestCalculate_ShouldReturnErrTaxYearNotSupported_WhenGetCapRateNotFound(t *testing.T) {
+	// Arrange
+	calculator := NewTaxCalculatorUseCase(
+		&mockPersonalDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecaseCaseCapRate{err: deduction.ErrDeductionNotFound},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+
+	req := TaxCalculatorReq{
+		TotalIncome: money.FromBaht(500000),
+		Allowances:  []AllowanceReq{},
+	}
+
+	// Act
+	_, err := calculator.Calculate(req)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrTaxYearNotSupported)
+}
+
+type mockTaxBracketUsecaseGetTaxBracketsNotFound struct {
+}
+
+func (p *mockTaxBracketUsecaseGetTaxBracketsNotFound) GetTaxBrackets(taxYear int) ([]taxBracket.TaxBracket, error) {
+	return nil, errors.New("Not found")
 }
 
-func (p *mockKReceiptDeductionUsecaseGetDeductionNotFound) GetDeduction() (float64, error) {
-	return 0.0, errors.New("Not found")
+func (p *mockTaxBracketUsecaseGetTaxBracketsNotFound) ValidateTaxBrackets(req taxBracket.UpdateTaxBracketsReq) taxBracket.ValidateTaxBracketsRes {
+	return taxBracket.ValidateTaxBracketsRes{}
 }
 
-func (p *mockKReceiptDeductionUsecaseGetDeductionNotFound) UpdateDeduction(req kReceipt.UpdateKReceiptDeductionReq) (kReceipt.UpdateKReceiptDeductionRes, error) {
-	return kReceipt.UpdateKReceiptDeductionRes{}, nil
+func (p *mockTaxBracketUsecaseGetTaxBracketsNotFound) UpdateTaxBrackets(req taxBracket.UpdateTaxBracketsReq) (taxBracket.TaxBracketsRes, error) {
+	return taxBracket.TaxBracketsRes{}, nil
 }
 
-func TestCalculate_ShouldReturnErr_WhenGetKReceiptDeductionNotFound(t *testing.T) {
+func TestCalculate_ShouldReturnErr_WhenGetTaxBracketsNotFound(t *testing.T) {
 	// Arrange
 	calculator := NewTaxCalculatorUseCase(
 		&mockPersonalDeductionUsecase{},
-		&mockKReceiptDeductionUsecaseGetDeductionNotFound{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecaseGetTaxBracketsNotFound{},
 	)
 
 	req := TaxCalculatorReq{
-		TotalIncome: 500000.0,
-		WHT:         0.0,
+		TotalIncome: money.FromBaht(500000),
+		WHT:         money.FromBaht(0),
 		Allowances: []AllowanceReq{
-			{AllowanceType: allowanceType.Donation, Amount: 0.0},
+			{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(0)},
 		},
 	}
 
@@ -558,183 +1728,260 @@ func TestCalculate_ShouldReturnErr_WhenGetKReceiptDeductionNotFound(t *testing.T
 	result, err := calculator.Calculate(req)
 
 	// Assert
-	assert.Equal(t, 0.0, result.Tax)
-	assert.Equal(t, 0.0, result.TaxRefund)
+	assert.Equal(t, money.FromBaht(0), result.Tax)
+	assert.Equal(t, money.FromBaht(0), result.TaxRefund)
 	assert.Error(t, err)
 }
 
+type mockPersonalDeductionUsecaseCaseTaxYear struct {
+	taxYears []int
+}
+
+func (p *mockPersonalDeductionUsecaseCaseTaxYear) GetDeduction(taxYear int) (money.Money, error) {
+	p.taxYears = append(p.taxYears, taxYear)
+
+	if taxYear == 2568 {
+		return money.FromBaht(0), deduction.ErrDeductionNotFound
+	}
+
+	return money.FromBaht(60000), nil
+}
+
+func (p *mockPersonalDeductionUsecaseCaseTaxYear) UpdateDeduction(req personal.UpdatePersonalDeductionReq) (personal.UpdatePersonalDeductionRes, error) {
+	return personal.UpdatePersonalDeductionRes{}, nil
+}
+
+func TestCalculate_ShouldReturnErrTaxYearNotSupported_WhenTaxYearNotFound(t *testing.T) {
+	// Arrange
+	personalDeductionUsecase := &mockPersonalDeductionUsecaseCaseTaxYear{}
+	calculator := NewTaxCalculatorUseCase(
+		personalDeductionUsecase,
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+
+	req := TaxCalculatorReq{
+		TaxYear:     2568,
+		TotalIncome: money.FromBaht(500000),
+		WHT:         money.FromBaht(0),
+		Allowances: []AllowanceReq{
+			{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(0)},
+		},
+	}
+
+	// Act
+	_, err := calculator.Calculate(req)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrTaxYearNotSupported)
+	assert.Equal(t, []int{2568}, personalDeductionUsecase.taxYears)
+}
+
+func TestCalculate_ShouldUseDefaultTaxYear_WhenTaxYearNotSpecified(t *testing.T) {
+	// Arrange
+	personalDeductionUsecase := &mockPersonalDeductionUsecaseCaseTaxYear{}
+	calculator := NewTaxCalculatorUseCase(
+		personalDeductionUsecase,
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+
+	req := TaxCalculatorReq{
+		TotalIncome: money.FromBaht(500000),
+		WHT:         money.FromBaht(0),
+		Allowances: []AllowanceReq{
+			{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(0)},
+		},
+	}
+
+	// Act
+	result, err := calculator.Calculate(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, taxYear.Default, result.TaxYear)
+	assert.Equal(t, []int{taxYear.Default}, personalDeductionUsecase.taxYears)
+}
+
 func TestCalculate_ShouldCalculateCorrect_WhenCorrectInput(t *testing.T) {
 	// Arrange
 	calculator := NewTaxCalculatorUseCase(
 		&mockPersonalDeductionUsecase{},
-		&mockKReceiptDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
 	)
 
 	testCases := []struct {
 		name              string
 		req               TaxCalculatorReq
-		expectedTax       float64
-		expectedTaxRefund float64
+		expectedTax       money.Money
+		expectedTaxRefund money.Money
 		expectedTaxLevel  []TaxLevelRes
 	}{
 		{"Test case 1", TaxCalculatorReq{
-			TotalIncome: 500000.0,
-			WHT:         0.0,
+			TotalIncome: money.FromBaht(500000),
+			WHT:         money.FromBaht(0),
 			Allowances: []AllowanceReq{
-				{AllowanceType: allowanceType.Donation, Amount: 0.0},
+				{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(0)},
 			},
 		},
-			29000.0,
-			0.0,
+			money.FromBaht(29000),
+			money.FromBaht(0),
 			[]TaxLevelRes{
 				{
 					Level: "0-150,000",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 				{
 					Level: "150,001-500,000",
-					Tax:   29000.0,
+					Tax:   money.FromBaht(29000),
 				},
 				{
 					Level: "500,001-1,000,000",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 				{
 					Level: "1,000,001-2,000,000",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 				{
 					Level: "2,000,001 ขึ้นไป",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 			},
 		},
 		{"Test case 2", TaxCalculatorReq{
-			TotalIncome: 500000.0,
-			WHT:         25000.0,
+			TotalIncome: money.FromBaht(500000),
+			WHT:         money.FromBaht(25000),
 			Allowances: []AllowanceReq{
-				{AllowanceType: allowanceType.Donation, Amount: 0.0},
+				{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(0)},
 			},
 		},
-			4000.0,
-			0.0,
+			money.FromBaht(4000),
+			money.FromBaht(0),
 			[]TaxLevelRes{
 				{
 					Level: "0-150,000",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 				{
 					Level: "150,001-500,000",
-					Tax:   29000.0,
+					Tax:   money.FromBaht(29000),
 				},
 				{
 					Level: "500,001-1,000,000",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 				{
 					Level: "1,000,001-2,000,000",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 				{
 					Level: "2,000,001 ขึ้นไป",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 			},
 		},
 		{"Test case 3", TaxCalculatorReq{
-			TotalIncome: 500000.0,
-			WHT:         0.0,
+			TotalIncome: money.FromBaht(500000),
+			WHT:         money.FromBaht(0),
 			Allowances: []AllowanceReq{
-				{AllowanceType: allowanceType.Donation, Amount: 200000.0},
+				{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(200000)},
 			},
 		},
-			19000.0,
-			0.0,
+			money.FromBaht(24600),
+			money.FromBaht(0),
 			[]TaxLevelRes{
 				{
 					Level: "0-150,000",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 				{
 					Level: "150,001-500,000",
-					Tax:   19000.0,
+					Tax:   money.FromBaht(24600),
 				},
 				{
 					Level: "500,001-1,000,000",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 				{
 					Level: "1,000,001-2,000,000",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 				{
 					Level: "2,000,001 ขึ้นไป",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 			},
 		},
 		{"Test case 4", TaxCalculatorReq{
-			TotalIncome: 500000.0,
-			WHT:         29000.0,
+			TotalIncome: money.FromBaht(500000),
+			WHT:         money.FromBaht(29000),
 			Allowances: []AllowanceReq{
-				{AllowanceType: allowanceType.Donation, Amount: 200000.0},
+				{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(200000)},
 			},
 		},
-			0.0,
-			10000.0,
+			money.FromBaht(0),
+			money.FromBaht(4400),
 			[]TaxLevelRes{
 				{
 					Level: "0-150,000",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 				{
 					Level: "150,001-500,000",
-					Tax:   19000.0,
+					Tax:   money.FromBaht(24600),
 				},
 				{
 					Level: "500,001-1,000,000",
-					Tax:   100000.0,
+					Tax:   money.FromBaht(100000),
 				},
 				{
 					Level: "1,000,001-2,000,000",
-					Tax:   200000.0,
+					Tax:   money.FromBaht(200000),
 				},
 				{
 					Level: "2,000,001 ขึ้นไป",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 			},
 		},
 		{"Test case 5", TaxCalculatorReq{
-			TotalIncome: 500000.0,
-			WHT:         0.0,
+			TotalIncome: money.FromBaht(500000),
+			WHT:         money.FromBaht(0),
 			Allowances: []AllowanceReq{
-				{AllowanceType: allowanceType.KReceipt, Amount: 200000.0},
-				{AllowanceType: allowanceType.Donation, Amount: 100000.0},
+				{AllowanceType: allowanceType.KReceipt, Amount: money.FromBaht(200000)},
+				{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(100000)},
 			},
 		},
-			14000.0,
-			0.0,
+			money.FromBaht(20100),
+			money.FromBaht(0),
 			[]TaxLevelRes{
 				{
 					Level: "0-150,000",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 				{
 					Level: "150,001-500,000",
-					Tax:   14000.0,
+					Tax:   money.FromBaht(20100),
 				},
 				{
 					Level: "500,001-1,000,000",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 				{
 					Level: "1,000,001-2,000,000",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 				{
 					Level: "2,000,001 ขึ้นไป",
-					Tax:   0.0,
+					Tax:   money.FromBaht(0),
 				},
 			},
 		},
@@ -752,98 +1999,96 @@ func TestCalculate_ShouldCalculateCorrect_WhenCorrectInput(t *testing.T) {
 	}
 }
 
-// CalculateMultiRequest
-
-func TestCalculateTaxWithCSV_ShouldReturnErr_WhenGetDeductionNotFound(t *testing.T) {
+func TestCalculate_ShouldExplainEveryStep_WhenCorrectInput(t *testing.T) {
 	// Arrange
 	calculator := NewTaxCalculatorUseCase(
-		&mockPersonalDeductionUsecaseGetDeductionNotFound{},
-		&mockKReceiptDeductionUsecase{},
+		&mockPersonalDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
 	)
-
-	reqs := []TaxCalculatorReq{
-		{
-			TotalIncome: 500000.0,
-			WHT:         0.0,
-			Allowances: []AllowanceReq{
-				{AllowanceType: allowanceType.Donation, Amount: 0.0},
-			},
+	req := TaxCalculatorReq{
+		TotalIncome: money.FromBaht(500000),
+		WHT:         money.FromBaht(4000),
+		Allowances: []AllowanceReq{
+			{AllowanceType: allowanceType.KReceipt, Amount: money.FromBaht(70000)},
+			{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(150000)},
+			{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(50000)},
 		},
 	}
 
 	// Act
-	_, err := calculator.CalculateMultiRequest(reqs)
+	result, err := calculator.Calculate(req)
 
 	// Assert
-	assert.Error(t, err)
-
+	assert.NoError(t, err)
+	assert.Equal(t, &TaxExplanationRes{
+		TotalIncome:   money.FromBaht(500000),
+		Incomes:       []IncomeExplanationRes{},
+		TotalExpenses: money.FromBaht(0),
+		Allowances: []AllowanceExplanationRes{
+			{AllowanceType: allowanceType.KReceipt, Amount: money.FromBaht(70000), MaxDeduction: money.FromBaht(50000), Deduction: money.FromBaht(50000), CutOff: money.FromBaht(20000)},
+			{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(200000), CapRate: money.FromPercent(10), CapBase: allowanceType.CapBaseNetIncome, CapBaseAmount: mockCapBaseAmount(390000), MaxDeduction: money.FromBaht(39000), Deduction: money.FromBaht(39000), CutOff: money.FromBaht(161000)},
+		},
+		AllowanceGroups:   []AllowanceGroupExplanationRes{},
+		TotalAllowances:   money.FromBaht(89000),
+		PersonalDeduction: money.FromBaht(60000),
+		Dependents:        []DependentExplanationRes{},
+		TotalDeduction:    money.FromBaht(149000),
+		NetIncome:         money.FromBaht(351000),
+		TaxLevel: []TaxLevelRes{
+			{Level: "0-150,000", MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(150000), Rate: money.FromPercent(0), TaxableIncome: money.FromBaht(150000), BracketTax: money.FromBaht(0), CumulativeTax: money.FromBaht(0), Tax: money.FromBaht(0)},
+			{Level: "150,001-500,000", MinIncome: money.FromBaht(150000), MaxIncome: mockMaxIncome(500000), Rate: money.FromPercent(10), TaxableIncome: money.FromBaht(201000), BracketTax: money.FromBaht(20100), CumulativeTax: money.FromBaht(20100), Tax: money.FromBaht(20100)},
+			{Level: "500,001-1,000,000", MinIncome: money.FromBaht(500000), MaxIncome: mockMaxIncome(1000000), Rate: money.FromPercent(15), TaxableIncome: money.FromBaht(0), BracketTax: money.FromBaht(0), CumulativeTax: money.FromBaht(20100), Tax: money.FromBaht(0)},
+			{Level: "1,000,001-2,000,000", MinIncome: money.FromBaht(1000000), MaxIncome: mockMaxIncome(2000000), Rate: money.FromPercent(20), TaxableIncome: money.FromBaht(0), BracketTax: money.FromBaht(0), CumulativeTax: money.FromBaht(20100), Tax: money.FromBaht(0)},
+			{Level: "2,000,001 ขึ้นไป", MinIncome: money.FromBaht(2000000), MaxIncome: nil, Rate: money.FromPercent(35), TaxableIncome: money.FromBaht(0), BracketTax: money.FromBaht(0), CumulativeTax: money.FromBaht(20100), Tax: money.FromBaht(0)},
+		},
+		MarginalLevel: "150,001-500,000",
+		TotalTax:      money.FromBaht(20100),
+		WHT:           money.FromBaht(4000),
+		Tax:           money.FromBaht(16100),
+		TaxRefund:     money.FromBaht(0),
+	}, result.Explanation)
 }
 
-func TestCalculateMultiRequest_ShouldCalculateCorrect_WhenCorrectInput(t *testing.T) {
+func TestCalculate_ShouldReportTaxRates_WhenCorrectInput(t *testing.T) {
 	// Arrange
 	calculator := NewTaxCalculatorUseCase(
 		&mockPersonalDeductionUsecase{},
-		&mockKReceiptDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
 	)
 
 	testCases := []struct {
-		name        string
-		reqs        []TaxCalculatorReq
-		expectedRes TaxCalucalorMultipleRes
+		name                        string
+		totalIncome                 money.Money
+		expectedEffectiveTaxRate    money.Rate
+		expectedEffectiveNetTaxRate money.Rate
+		expectedMarginalTaxRate     money.Rate
+		expectedNextBracketDistance *money.Money
 	}{
-		{"Test case 1",
-			[]TaxCalculatorReq{
-				{
-					TotalIncome: 500000.0,
-					WHT:         0.0,
-					Allowances: []AllowanceReq{
-						{AllowanceType: allowanceType.Donation, Amount: 0.0},
-					},
-				},
-				{
-					TotalIncome: 600000.0,
-					WHT:         40000.0,
-					Allowances: []AllowanceReq{
-						{AllowanceType: allowanceType.Donation, Amount: 20000.0},
-					},
-				},
-				{
-					TotalIncome: 750000.0,
-					WHT:         50000.0,
-					Allowances: []AllowanceReq{
-						{AllowanceType: allowanceType.Donation, Amount: 15000.0},
-					},
-				},
-			},
-			TaxCalucalorMultipleRes{
-				Taxes: []TaxCalucalorMultipleDetailRes{
-					{
-						TotalIncome: 500000.0,
-						Tax:         29000.0,
-						TaxRefund:   0.0,
-					},
-					{
-						TotalIncome: 600000.0,
-						Tax:         0.0,
-						TaxRefund:   2000.0,
-					},
-					{
-						TotalIncome: 750000.0,
-						Tax:         11250.0,
-						TaxRefund:   0.0,
-					},
-				},
-			},
-		},
+		{"Test case 1", money.FromBaht(50000), money.Rate(0), money.Rate(0), money.FromPercent(0), mockNextBracketDistance(150000)},
+		{"Test case 2", money.FromBaht(500000), money.Rate(580), money.Rate(659), money.FromPercent(10), mockNextBracketDistance(60000)},
+		{"Test case 3", money.FromBaht(3060000), money.Rate(2156), money.Rate(2200), money.FromPercent(35), nil},
 	}
 
 	// Act
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result, _ := calculator.CalculateMultiRequest(tc.reqs)
+			result, err := calculator.Calculate(TaxCalculatorReq{
+				TotalIncome: tc.totalIncome,
+				Allowances:  []AllowanceReq{},
+			})
 
 			// Assert
-			assert.Equal(t, tc.expectedRes, result)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedEffectiveTaxRate, result.EffectiveTaxRate)
+			assert.Equal(t, tc.expectedEffectiveNetTaxRate, result.EffectiveNetTaxRate)
+			assert.Equal(t, tc.expectedMarginalTaxRate, result.MarginalTaxRate)
+			assert.Equal(t, tc.expectedNextBracketDistance, result.NextBracketDistance)
 		})
 	}
 }
@@ -0,0 +1,52 @@
+package optimizer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+)
+
+type AllowanceOptimizerHttpHandler interface {
+	OptimizeAllowances(c echo.Context) error
+}
+
+type allowanceOptimizerHttpHandler struct {
+	allowanceOptimizerUseCase AllowanceOptimizerUseCase
+}
+
+func NewAllowanceOptimizerHttpHandler(allowanceOptimizerUseCase AllowanceOptimizerUseCase) AllowanceOptimizerHttpHandler {
+	return &allowanceOptimizerHttpHandler{
+		allowanceOptimizerUseCase: allowanceOptimizerUseCase,
+	}
+}
+
+func (a *allowanceOptimizerHttpHandler) OptimizeAllowances(c echo.Context) error {
+	var req OptimizeAllowancesReq
+
+	err := c.Bind(&req)
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if err = c.Validate(req); err != nil {
+		return err
+	}
+
+	res, err := a.allowanceOptimizerUseCase.OptimizeAllowances(req)
+
+	if errors.Is(err, calculator.ErrTaxYearNotSupported) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Tax year not supported")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
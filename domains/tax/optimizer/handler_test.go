@@ -0,0 +1,139 @@
+package optimizer
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/money"
+	myValidator "github.com/larb26656/assessment-tax/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAllowanceOptimizerUseCase struct {
+}
+
+func (m *mockAllowanceOptimizerUseCase) OptimizeAllowances(req OptimizeAllowancesReq) (OptimizeAllowancesRes, error) {
+	return OptimizeAllowancesRes{
+		Budget:               money.FromBaht(60000),
+		Spent:                money.FromBaht(60000),
+		TaxWithoutAllowances: money.FromBaht(29000),
+		Tax:                  money.FromBaht(23000),
+		TaxRefund:            money.FromBaht(0),
+		TaxSaved:             money.FromBaht(6000),
+		Allowances: []AllowanceRecommendationRes{
+			{AllowanceType: "donation", Amount: money.FromBaht(60000), MaxDeduction: money.FromBaht(100000), MarginalSavingPerBaht: money.Money(10)},
+			{AllowanceType: "k-receipt", Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(50000), MarginalSavingPerBaht: money.Money(10)},
+		},
+	}, nil
+}
+
+type mockAllowanceOptimizerUseCaseCaseError struct {
+	err error
+}
+
+func (m *mockAllowanceOptimizerUseCaseCaseError) OptimizeAllowances(req OptimizeAllowancesReq) (OptimizeAllowancesRes, error) {
+	return OptimizeAllowancesRes{}, m.err
+}
+
+func mockOptimizeAllowancesHttpReq(reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	req := httptest.NewRequest(http.MethodPost, "/tax/allowance-recommendations", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+func TestOptimizeAllowancesHandler_ShouldGetBadRequest_WhenInvalidInput(t *testing.T) {
+	// Arrange
+	handler := NewAllowanceOptimizerHttpHandler(&mockAllowanceOptimizerUseCase{})
+
+	testCases := []struct {
+		name    string
+		reqBody string
+	}{
+		{"Test case 1", `{"totalIncome": "abc", "wht": 0.0, "budget": 1000.0}`},
+		{"Test case 2", `{"totalIncome": -1.0, "wht": 0.0, "budget": 1000.0}`},
+		{"Test case 3", `{"totalIncome": 500000.0, "wht": -1.0, "budget": 1000.0}`},
+		{"Test case 4", `{"totalIncome": 500000.0, "wht": 0.0, "budget": -1.0}`},
+		{"Test case 5", `{"taxYear": 1, "totalIncome": 500000.0, "wht": 0.0, "budget": 1000.0}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, _ := mockOptimizeAllowancesHttpReq(tc.reqBody)
+
+			// Act
+			err := handler.OptimizeAllowances(c)
+
+			// Assert
+			he, ok := err.(*echo.HTTPError)
+
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+func TestOptimizeAllowancesHandler_ShouldGetError_WhenUsecaseError(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{"Test case 1", calculator.ErrTaxYearNotSupported, http.StatusBadRequest},
+		{"Test case 2", errors.New("error on optimize"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler := NewAllowanceOptimizerHttpHandler(&mockAllowanceOptimizerUseCaseCaseError{err: tc.err})
+			_, c, _ := mockOptimizeAllowancesHttpReq(`{"totalIncome": 500000.0, "wht": 0.0, "budget": 60000.0}`)
+
+			// Act
+			err := handler.OptimizeAllowances(c)
+
+			// Assert
+			he, ok := err.(*echo.HTTPError)
+
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCode, he.Code)
+		})
+	}
+}
+
+func TestOptimizeAllowancesHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	handler := NewAllowanceOptimizerHttpHandler(&mockAllowanceOptimizerUseCase{})
+	_, c, rec := mockOptimizeAllowancesHttpReq(`{"totalIncome": 500000.0, "wht": 0.0, "budget": 60000.0}`)
+
+	// Act
+	err := handler.OptimizeAllowances(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.JSONEq(t, `{
+		"budget": 60000,
+		"spent": 60000,
+		"taxWithoutAllowances": 29000,
+		"tax": 23000,
+		"taxRefund": 0,
+		"taxSaved": 6000,
+		"allowances": [
+			{"allowanceType": "donation", "amount": 60000, "maxDeduction": 100000, "marginalSavingPerBaht": 0.10},
+			{"allowanceType": "k-receipt", "amount": 0, "maxDeduction": 50000, "marginalSavingPerBaht": 0.10}
+		]
+	}`, rec.Body.String())
+}
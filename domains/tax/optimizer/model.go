@@ -0,0 +1,27 @@
+package optimizer
+
+import "github.com/larb26656/assessment-tax/money"
+
+type OptimizeAllowancesReq struct {
+	TaxYear     int         `json:"taxYear" validate:"omitempty,gte=2500"`
+	TotalIncome money.Money `json:"totalIncome" validate:"gte=0"`
+	WHT         money.Money `json:"wht" validate:"gte=0"`
+	Budget      money.Money `json:"budget" validate:"gte=0"`
+}
+
+type AllowanceRecommendationRes struct {
+	AllowanceType         string      `json:"allowanceType"`
+	Amount                money.Money `json:"amount"`
+	MaxDeduction          money.Money `json:"maxDeduction"`
+	MarginalSavingPerBaht money.Money `json:"marginalSavingPerBaht"`
+}
+
+type OptimizeAllowancesRes struct {
+	Budget               money.Money                  `json:"budget"`
+	Spent                money.Money                  `json:"spent"`
+	TaxWithoutAllowances money.Money                  `json:"taxWithoutAllowances"`
+	Tax                  money.Money                  `json:"tax"`
+	TaxRefund            money.Money                  `json:"taxRefund"`
+	TaxSaved             money.Money                  `json:"taxSaved"`
+	Allowances           []AllowanceRecommendationRes `json:"allowances"`
+}
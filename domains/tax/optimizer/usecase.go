@@ -0,0 +1,193 @@
+package optimizer
+
+import (
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/money"
+)
+
+type AllowanceOptimizerUseCase interface {
+	OptimizeAllowances(req OptimizeAllowancesReq) (OptimizeAllowancesRes, error)
+}
+
+type allowanceOptimizerUseCase struct {
+	taxCalculatorUseCase calculator.TaxCalculatorUseCase
+}
+
+func NewAllowanceOptimizerUseCase(taxCalculatorUseCase calculator.TaxCalculatorUseCase) AllowanceOptimizerUseCase {
+	return &allowanceOptimizerUseCase{
+		taxCalculatorUseCase: taxCalculatorUseCase,
+	}
+}
+
+// fillAllowances spends the amount on each allowance type up to its cap, in the order they are deducted
+func fillAllowances(spend money.Money, allowanceCaps []calculator.AllowanceCap) []calculator.AllowanceReq {
+	allowances := []calculator.AllowanceReq{}
+
+	for _, allowanceCap := range allowanceCaps {
+		amount := money.Min(spend, allowanceCap.MaxDeduction)
+		spend -= amount
+
+		allowances = append(allowances, calculator.AllowanceReq{
+			AllowanceType: allowanceCap.AllowanceType,
+			Amount:        amount,
+		})
+	}
+
+	return allowances
+}
+
+// effectiveAllowanceCaps lowers each max deduction to what the calculator deducts when every type
+// before it is filled, so percentage and group caps are not spent past
+func effectiveAllowanceCaps(allowanceCaps []calculator.AllowanceCap, calculate func([]calculator.AllowanceReq) (calculator.TaxCalculatorRes, error)) ([]calculator.AllowanceCap, error) {
+	effectiveCaps := make([]calculator.AllowanceCap, len(allowanceCaps))
+	allowances := []calculator.AllowanceReq{}
+
+	for i, allowanceCap := range allowanceCaps {
+		res, err := calculate(append(append([]calculator.AllowanceReq{}, allowances...), calculator.AllowanceReq{
+			AllowanceType: allowanceCap.AllowanceType,
+			Amount:        allowanceCap.MaxDeduction,
+		}))
+
+		if err != nil {
+			return nil, err
+		}
+
+		effectiveCaps[i] = allowanceCap
+		effectiveCaps[i].MaxDeduction = deductionOf(res, allowanceCap.AllowanceType)
+
+		allowances = append(allowances, calculator.AllowanceReq{
+			AllowanceType: allowanceCap.AllowanceType,
+			Amount:        effectiveCaps[i].MaxDeduction,
+		})
+	}
+
+	return effectiveCaps, nil
+}
+
+func deductionOf(res calculator.TaxCalculatorRes, allowanceType string) money.Money {
+	for _, explanation := range res.Explanation.Allowances {
+		if explanation.AllowanceType == allowanceType {
+			return explanation.Deduction
+		}
+	}
+
+	return 0
+}
+
+// payable is the tax still to pay, negative when it is refunded
+func payable(res calculator.TaxCalculatorRes) money.Money {
+	return res.Tax - res.TaxRefund
+}
+
+// OptimizeAllowances recommends the smallest spending within budget that reaches the minimum tax.
+// Every allowance type reduces net income baht for baht so spending past a cap or past
+// the point where the tax stops falling is never recommended. Types are filled in the order
+// they are deducted up to what the calculator deducts after percentage and group caps.
+func (a *allowanceOptimizerUseCase) OptimizeAllowances(req OptimizeAllowancesReq) (OptimizeAllowancesRes, error) {
+	setting, err := a.taxCalculatorUseCase.GetTaxSetting(taxYear.Resolve(req.TaxYear))
+
+	if err != nil {
+		return OptimizeAllowancesRes{}, err
+	}
+
+	calculateWithAllowances := func(allowances []calculator.AllowanceReq) (calculator.TaxCalculatorRes, error) {
+		return a.taxCalculatorUseCase.CalculateWithSetting(calculator.TaxCalculatorReq{
+			TaxYear:     req.TaxYear,
+			TotalIncome: req.TotalIncome,
+			WHT:         req.WHT,
+			Allowances:  allowances,
+		}, setting)
+	}
+
+	allowanceCaps, err := effectiveAllowanceCaps(setting.AllowanceCaps(), calculateWithAllowances)
+
+	if err != nil {
+		return OptimizeAllowancesRes{}, err
+	}
+
+	// calcErr keeps the first error of the search, it is returned before a result is read
+	var calcErr error
+
+	calculate := func(allowances []calculator.AllowanceReq) calculator.TaxCalculatorRes {
+		res, err := calculateWithAllowances(allowances)
+
+		if err != nil && calcErr == nil {
+			calcErr = err
+		}
+
+		return res
+	}
+
+	var maxSpend money.Money
+
+	for _, allowanceCap := range allowanceCaps {
+		maxSpend += allowanceCap.MaxDeduction
+	}
+
+	maxSpend = money.Min(maxSpend, req.Budget)
+	minPayable := payable(calculate(fillAllowances(maxSpend, allowanceCaps)))
+
+	// tax never rises with more spending, find the smallest spending in whole baht that reaches the minimum
+	spendOf := func(baht money.Money) money.Money {
+		return money.Min(baht*money.Baht, maxSpend)
+	}
+
+	low, high := money.Money(0), (maxSpend+money.Baht-1)/money.Baht
+
+	for low < high {
+		mid := low + (high-low)/2
+
+		if payable(calculate(fillAllowances(spendOf(mid), allowanceCaps))) <= minPayable {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+
+	spent := spendOf(low)
+	allowances := fillAllowances(spent, allowanceCaps)
+	res := calculate(allowances)
+	withoutAllowances := calculate([]calculator.AllowanceReq{})
+
+	if calcErr != nil {
+		return OptimizeAllowancesRes{}, calcErr
+	}
+
+	maxDeductions := make(map[string]money.Money)
+
+	for _, explanation := range res.Explanation.Allowances {
+		maxDeductions[explanation.AllowanceType] = explanation.MaxDeduction
+	}
+
+	recommendations := make([]AllowanceRecommendationRes, len(allowances))
+
+	for i, allowance := range allowances {
+		// one more baht on this allowance only, the calculator ignores it past the cap
+		moreAllowances := append(append([]calculator.AllowanceReq{}, allowances...), calculator.AllowanceReq{
+			AllowanceType: allowance.AllowanceType,
+			Amount:        money.Baht,
+		})
+
+		recommendations[i] = AllowanceRecommendationRes{
+			AllowanceType:         allowance.AllowanceType,
+			Amount:                allowance.Amount,
+			MaxDeduction:          maxDeductions[allowance.AllowanceType],
+			MarginalSavingPerBaht: payable(res) - payable(calculate(moreAllowances)),
+		}
+	}
+
+	if calcErr != nil {
+		return OptimizeAllowancesRes{}, calcErr
+	}
+
+	return OptimizeAllowancesRes{
+		Budget:               req.Budget,
+		Spent:                spent,
+		TaxWithoutAllowances: withoutAllowances.Tax,
+		Tax:                  res.Tax,
+		TaxRefund:            res.TaxRefund,
+		TaxSaved:             payable(withoutAllowances) - payable(res),
+		Allowances:           recommendations,
+	}, nil
+}
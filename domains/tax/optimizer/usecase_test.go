@@ -0,0 +1,168 @@
+package optimizer
+
+import (
+	"testing"
+
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/mock"
+	"github.com/larb26656/assessment-tax/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptimizeAllowances_ShouldRecommendSmallestSpending_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	usecase := NewAllowanceOptimizerUseCase(mock.NewMockTaxCalculatorUseCase())
+
+	testCases := []struct {
+		name        string
+		req         OptimizeAllowancesReq
+		expectedRes OptimizeAllowancesRes
+	}{
+		{"Test case 1", OptimizeAllowancesReq{
+			TotalIncome: money.FromBaht(500000),
+			WHT:         money.FromBaht(0),
+			Budget:      money.FromBaht(60000),
+		}, OptimizeAllowancesRes{
+			Budget:               money.FromBaht(60000),
+			Spent:                money.FromBaht(60000),
+			TaxWithoutAllowances: money.FromBaht(29000),
+			Tax:                  money.FromBaht(23000),
+			TaxRefund:            money.FromBaht(0),
+			TaxSaved:             money.FromBaht(6000),
+			Allowances: []AllowanceRecommendationRes{
+				{AllowanceType: allowanceType.KReceipt, Amount: money.FromBaht(50000), MaxDeduction: money.FromBaht(50000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.RMF, Amount: money.FromBaht(10000), MaxDeduction: money.FromBaht(150000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.SSF, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(150000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.ProvidentFund, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(75000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.PensionInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(75000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.LifeInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(100000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.HealthInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(25000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.ParentHealthInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(15000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(38000), MarginalSavingPerBaht: money.Money(10)},
+			},
+		}},
+		{"Test case 2", OptimizeAllowancesReq{
+			TotalIncome: money.FromBaht(500000),
+			WHT:         money.FromBaht(0),
+			Budget:      money.FromBaht(200000),
+		}, OptimizeAllowancesRes{
+			Budget:               money.FromBaht(200000),
+			Spent:                money.FromBaht(200000),
+			TaxWithoutAllowances: money.FromBaht(29000),
+			Tax:                  money.FromBaht(9000),
+			TaxRefund:            money.FromBaht(0),
+			TaxSaved:             money.FromBaht(20000),
+			Allowances: []AllowanceRecommendationRes{
+				{AllowanceType: allowanceType.KReceipt, Amount: money.FromBaht(50000), MaxDeduction: money.FromBaht(50000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.RMF, Amount: money.FromBaht(150000), MaxDeduction: money.FromBaht(150000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.SSF, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(150000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.ProvidentFund, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(75000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.PensionInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(75000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.LifeInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(100000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.HealthInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(25000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.ParentHealthInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(15000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(24000), MarginalSavingPerBaht: money.Money(10)},
+			},
+		}},
+		{"Test case 3", OptimizeAllowancesReq{
+			TotalIncome: money.FromBaht(250000),
+			WHT:         money.FromBaht(5000),
+			Budget:      money.FromBaht(200000),
+		}, OptimizeAllowancesRes{
+			Budget:               money.FromBaht(200000),
+			Spent:                money.FromBaht(40000),
+			TaxWithoutAllowances: money.FromBaht(0),
+			Tax:                  money.FromBaht(0),
+			TaxRefund:            money.FromBaht(5000),
+			TaxSaved:             money.FromBaht(4000),
+			Allowances: []AllowanceRecommendationRes{
+				{AllowanceType: allowanceType.KReceipt, Amount: money.FromBaht(40000), MaxDeduction: money.FromBaht(50000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.RMF, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(75000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.SSF, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(75000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.ProvidentFund, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(37500), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.PensionInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(37500), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.LifeInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(100000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.HealthInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(25000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.ParentHealthInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(15000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(15000), MarginalSavingPerBaht: money.FromBaht(0)},
+			},
+		}},
+		{"Test case 4", OptimizeAllowancesReq{
+			TotalIncome: money.FromBaht(500000),
+			WHT:         money.FromBaht(0),
+			Budget:      money.FromBaht(0),
+		}, OptimizeAllowancesRes{
+			Budget:               money.FromBaht(0),
+			Spent:                money.FromBaht(0),
+			TaxWithoutAllowances: money.FromBaht(29000),
+			Tax:                  money.FromBaht(29000),
+			TaxRefund:            money.FromBaht(0),
+			TaxSaved:             money.FromBaht(0),
+			Allowances: []AllowanceRecommendationRes{
+				{AllowanceType: allowanceType.KReceipt, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(50000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.RMF, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(150000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.SSF, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(150000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.ProvidentFund, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(75000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.PensionInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(75000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.LifeInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(100000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.HealthInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(25000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.ParentHealthInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(15000), MarginalSavingPerBaht: money.Money(10)},
+				{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(44000), MarginalSavingPerBaht: money.Money(10)},
+			},
+		}},
+		{"Test case 5", OptimizeAllowancesReq{
+			TotalIncome: money.FromBaht(5000000),
+			WHT:         money.FromBaht(0),
+			Budget:      money.FromBaht(2000000),
+		}, OptimizeAllowancesRes{
+			Budget:               money.FromBaht(2000000),
+			Spent:                money.FromBaht(765000),
+			TaxWithoutAllowances: money.FromBaht(1339000),
+			Tax:                  money.FromBaht(1071250),
+			TaxRefund:            money.FromBaht(0),
+			TaxSaved:             money.FromBaht(267750),
+			Allowances: []AllowanceRecommendationRes{
+				{AllowanceType: allowanceType.KReceipt, Amount: money.FromBaht(50000), MaxDeduction: money.FromBaht(50000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.RMF, Amount: money.FromBaht(500000), MaxDeduction: money.FromBaht(500000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.SSF, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(200000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.ProvidentFund, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(500000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.PensionInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(200000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.LifeInsurance, Amount: money.FromBaht(100000), MaxDeduction: money.FromBaht(100000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.HealthInsurance, Amount: money.FromBaht(0), MaxDeduction: money.FromBaht(25000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.ParentHealthInsurance, Amount: money.FromBaht(15000), MaxDeduction: money.FromBaht(15000), MarginalSavingPerBaht: money.FromBaht(0)},
+				{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(100000), MaxDeduction: money.FromBaht(100000), MarginalSavingPerBaht: money.FromBaht(0)},
+			},
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			result, err := usecase.OptimizeAllowances(tc.req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedRes, result)
+		})
+	}
+}
+
+type mockTaxCalculatorUseCaseCaseTaxYearNotSupported struct {
+	calculator.TaxCalculatorUseCase
+}
+
+func (m *mockTaxCalculatorUseCaseCaseTaxYearNotSupported) GetTaxSetting(year int) (calculator.TaxSetting, error) {
+	return calculator.TaxSetting{}, calculator.ErrTaxYearNotSupported
+}
+
+func TestOptimizeAllowances_ShouldReturnErr_WhenGetTaxSettingError(t *testing.T) {
+	// Arrange
+	usecase := NewAllowanceOptimizerUseCase(&mockTaxCalculatorUseCaseCaseTaxYearNotSupported{})
+
+	// Act
+	_, err := usecase.OptimizeAllowances(OptimizeAllowancesReq{TaxYear: 2500})
+
+	// Assert
+	assert.ErrorIs(t, err, calculator.ErrTaxYearNotSupported)
+}
@@ -0,0 +1,52 @@
+package payroll
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+)
+
+type PayrollWithholdingHttpHandler interface {
+	CalculatePayrollWithholding(c echo.Context) error
+}
+
+type payrollWithholdingHttpHandler struct {
+	payrollWithholdingUseCase PayrollWithholdingUseCase
+}
+
+func NewPayrollWithholdingHttpHandler(payrollWithholdingUseCase PayrollWithholdingUseCase) PayrollWithholdingHttpHandler {
+	return &payrollWithholdingHttpHandler{
+		payrollWithholdingUseCase: payrollWithholdingUseCase,
+	}
+}
+
+func (p *payrollWithholdingHttpHandler) CalculatePayrollWithholding(c echo.Context) error {
+	var req PayrollWithholdingReq
+
+	err := c.Bind(&req)
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if err = c.Validate(req); err != nil {
+		return err
+	}
+
+	res, err := p.payrollWithholdingUseCase.Calculate(req)
+
+	if errors.Is(err, calculator.ErrTaxYearNotSupported) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Tax year not supported")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,159 @@
+package payroll
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/money"
+	myValidator "github.com/larb26656/assessment-tax/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPayrollWithholdingUseCase struct {
+}
+
+func (m *mockPayrollWithholdingUseCase) Calculate(req PayrollWithholdingReq) (PayrollWithholdingRes, error) {
+	return PayrollWithholdingRes{
+		Month:           1,
+		RemainingMonths: 12,
+		AnnualSalary:    money.FromBaht(600000),
+		AnnualIncome:    money.FromBaht(600000),
+		SalaryTax:       money.FromBaht(29000),
+		AnnualTax:       money.FromBaht(29000),
+		YTDWHT:          money.FromBaht(0),
+		SalaryWHT:       money.Money(241666),
+		BonusWHT:        money.FromBaht(0),
+		WHT:             money.Money(241666),
+		Calculation: calculator.TaxCalculatorRes{
+			Tax:       money.FromBaht(29000),
+			TaxRefund: money.FromBaht(0),
+			TaxLevel:  []calculator.TaxLevelRes{},
+		},
+	}, nil
+}
+
+type mockPayrollWithholdingUseCaseCaseError struct {
+	err error
+}
+
+func (m *mockPayrollWithholdingUseCaseCaseError) Calculate(req PayrollWithholdingReq) (PayrollWithholdingRes, error) {
+	return PayrollWithholdingRes{}, m.err
+}
+
+func mockCalculatePayrollWithholdingHttpReq(reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	req := httptest.NewRequest(http.MethodPost, "/tax/payroll-withholdings", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+func TestCalculatePayrollWithholdingHandler_ShouldGetBadRequest_WhenInvalidInput(t *testing.T) {
+	// Arrange
+	handler := NewPayrollWithholdingHttpHandler(&mockPayrollWithholdingUseCase{})
+
+	testCases := []struct {
+		name    string
+		reqBody string
+	}{
+		{"Test case 1", `{"month": "abc", "monthlySalary": 50000.0, "allowances": []}`},
+		{"Test case 2", `{"monthlySalary": 50000.0, "allowances": []}`},
+		{"Test case 3", `{"month": 13, "monthlySalary": 50000.0, "allowances": []}`},
+		{"Test case 4", `{"month": 1, "monthlySalary": -1.0, "allowances": []}`},
+		{"Test case 5", `{"month": 1, "monthlySalary": 50000.0, "bonus": -1.0, "allowances": []}`},
+		{"Test case 6", `{"month": 1, "monthlySalary": 50000.0, "ytdWht": -1.0, "allowances": []}`},
+		{"Test case 7", `{"month": 1, "monthlySalary": 50000.0}`},
+		{"Test case 8", `{"month": 1, "monthlySalary": 50000.0, "allowances": [{"allowanceType": "unknown", "amount": 100.0}]}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, _ := mockCalculatePayrollWithholdingHttpReq(tc.reqBody)
+
+			// Act
+			err := handler.CalculatePayrollWithholding(c)
+
+			// Assert
+			he, ok := err.(*echo.HTTPError)
+
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+func TestCalculatePayrollWithholdingHandler_ShouldGetError_WhenUsecaseError(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{"Test case 1", calculator.ErrTaxYearNotSupported, http.StatusBadRequest},
+		{"Test case 2", errors.New("error on calculate"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler := NewPayrollWithholdingHttpHandler(&mockPayrollWithholdingUseCaseCaseError{err: tc.err})
+			_, c, _ := mockCalculatePayrollWithholdingHttpReq(`{"month": 1, "monthlySalary": 50000.0, "allowances": []}`)
+
+			// Act
+			err := handler.CalculatePayrollWithholding(c)
+
+			// Assert
+			he, ok := err.(*echo.HTTPError)
+
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCode, he.Code)
+		})
+	}
+}
+
+func TestCalculatePayrollWithholdingHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	handler := NewPayrollWithholdingHttpHandler(&mockPayrollWithholdingUseCase{})
+	_, c, rec := mockCalculatePayrollWithholdingHttpReq(`{"month": 1, "monthlySalary": 50000.0, "allowances": []}`)
+
+	// Act
+	err := handler.CalculatePayrollWithholding(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.JSONEq(t, `{
+		"month": 1,
+		"remainingMonths": 12,
+		"annualSalary": 600000,
+		"annualIncome": 600000,
+		"salaryTax": 29000,
+		"annualTax": 29000,
+		"ytdWht": 0,
+		"salaryWht": 2416.66,
+		"bonusWht": 0,
+		"wht": 2416.66,
+		"calculation": {
+			"tax": 29000,
+			"taxRefund": 0,
+			"taxLevel": [],
+			"effectiveTaxRate": 0,
+			"effectiveNetTaxRate": 0,
+			"marginalTaxRate": 0,
+			"nextBracketDistance": null,
+			"taxMethod": "",
+			"progressiveTax": 0,
+			"minimumTax": 0
+		}
+	}`, rec.Body.String())
+}
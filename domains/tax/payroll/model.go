@@ -0,0 +1,35 @@
+package payroll
+
+import (
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/money"
+)
+
+// PayrollWithholdingReq is the pay of Month, YTDIncome and YTDWHT are what was paid and withheld
+// in the months before it, bonuses paid before are part of YTDIncome
+type PayrollWithholdingReq struct {
+	TaxYear       int                       `json:"taxYear" validate:"omitempty,gte=2500"`
+	Month         int                       `json:"month" validate:"required,gte=1,lte=12"`
+	MonthlySalary money.Money               `json:"monthlySalary" validate:"gte=0"`
+	Bonus         money.Money               `json:"bonus" validate:"gte=0"`
+	YTDIncome     money.Money               `json:"ytdIncome" validate:"gte=0"`
+	YTDWHT        money.Money               `json:"ytdWht" validate:"gte=0"`
+	Allowances    []calculator.AllowanceReq `json:"allowances" validate:"required,dive"`
+	Dependents    calculator.DependentsReq  `json:"dependents"`
+}
+
+// PayrollWithholdingRes splits the wht of the month into the share of the salary tax left for the year
+// and the whole extra tax the bonus brings, Calculation is the annual tax with the bonus
+type PayrollWithholdingRes struct {
+	Month           int                         `json:"month"`
+	RemainingMonths int                         `json:"remainingMonths"`
+	AnnualSalary    money.Money                 `json:"annualSalary"`
+	AnnualIncome    money.Money                 `json:"annualIncome"`
+	SalaryTax       money.Money                 `json:"salaryTax"`
+	AnnualTax       money.Money                 `json:"annualTax"`
+	YTDWHT          money.Money                 `json:"ytdWht"`
+	SalaryWHT       money.Money                 `json:"salaryWht"`
+	BonusWHT        money.Money                 `json:"bonusWht"`
+	WHT             money.Money                 `json:"wht"`
+	Calculation     calculator.TaxCalculatorRes `json:"calculation"`
+}
@@ -0,0 +1,83 @@
+package payroll
+
+import (
+	"github.com/larb26656/assessment-tax/constant/incomeType"
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/money"
+)
+
+const monthsInYear = 12
+
+type PayrollWithholdingUseCase interface {
+	Calculate(req PayrollWithholdingReq) (PayrollWithholdingRes, error)
+}
+
+type payrollWithholdingUseCase struct {
+	taxCalculatorUseCase calculator.TaxCalculatorUseCase
+}
+
+func NewPayrollWithholdingUseCase(taxCalculatorUseCase calculator.TaxCalculatorUseCase) PayrollWithholdingUseCase {
+	return &payrollWithholdingUseCase{
+		taxCalculatorUseCase: taxCalculatorUseCase,
+	}
+}
+
+// Calculate annualizes the salary as paid until now plus the monthly salary for the rest of the year
+// and taxes it as 40(1) income with the annual calculator. The salary tax not withheld yet is spread
+// evenly over the months left, the extra tax of the bonus is withheld in full this month.
+func (p *payrollWithholdingUseCase) Calculate(req PayrollWithholdingReq) (PayrollWithholdingRes, error) {
+	setting, err := p.taxCalculatorUseCase.GetTaxSetting(taxYear.Resolve(req.TaxYear))
+
+	if err != nil {
+		return PayrollWithholdingRes{}, err
+	}
+
+	calculate := func(annualIncome money.Money) (calculator.TaxCalculatorRes, error) {
+		return p.taxCalculatorUseCase.CalculateWithSetting(calculator.TaxCalculatorReq{
+			TaxYear:    req.TaxYear,
+			Allowances: req.Allowances,
+			Dependents: req.Dependents,
+			Incomes: []calculator.IncomeReq{
+				{IncomeType: incomeType.Salary, Amount: annualIncome},
+			},
+		}, setting)
+	}
+
+	remainingMonths := monthsInYear - req.Month + 1
+	annualSalary := req.YTDIncome + req.MonthlySalary*money.Money(remainingMonths)
+	annualIncome := annualSalary + req.Bonus
+
+	salaryRes, err := calculate(annualSalary)
+
+	if err != nil {
+		return PayrollWithholdingRes{}, err
+	}
+
+	res, err := calculate(annualIncome)
+
+	if err != nil {
+		return PayrollWithholdingRes{}, err
+	}
+
+	salaryTax := salaryRes.Tax
+	// the explanation is only given by tax/calculations with explain=true
+	res.Explanation = nil
+
+	salaryWHT := money.Max(salaryTax-req.YTDWHT, 0) / money.Money(remainingMonths)
+	bonusWHT := res.Tax - salaryTax
+
+	return PayrollWithholdingRes{
+		Month:           req.Month,
+		RemainingMonths: remainingMonths,
+		AnnualSalary:    annualSalary,
+		AnnualIncome:    annualIncome,
+		SalaryTax:       salaryTax,
+		AnnualTax:       res.Tax,
+		YTDWHT:          req.YTDWHT,
+		SalaryWHT:       salaryWHT,
+		BonusWHT:        bonusWHT,
+		WHT:             salaryWHT + bonusWHT,
+		Calculation:     res,
+	}, nil
+}
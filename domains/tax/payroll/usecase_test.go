@@ -0,0 +1,118 @@
+package payroll
+
+import (
+	"testing"
+
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/mock"
+	"github.com/larb26656/assessment-tax/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculate_ShouldReturnWHTOfMonth_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	usecase := NewPayrollWithholdingUseCase(mock.NewMockTaxCalculatorUseCase())
+
+	testCases := []struct {
+		name              string
+		req               PayrollWithholdingReq
+		expectedAnnualTax money.Money
+		expectedSalaryWHT money.Money
+		expectedBonusWHT  money.Money
+	}{
+		// 600,000 - 100,000 (expense) - 60,000 (personal) = 440,000 has 29,000 tax over 12 months
+		{"Test case 1", PayrollWithholdingReq{
+			Month:         1,
+			MonthlySalary: money.FromBaht(50000),
+			Allowances:    []calculator.AllowanceReq{},
+		}, money.FromBaht(29000), money.Money(241666), money.FromBaht(0)},
+		// the bonus takes the annual tax from 29,000 to 41,000
+		{"Test case 2", PayrollWithholdingReq{
+			Month:         12,
+			MonthlySalary: money.FromBaht(50000),
+			Bonus:         money.FromBaht(100000),
+			YTDIncome:     money.FromBaht(550000),
+			YTDWHT:        money.Money(2658326),
+			Allowances:    []calculator.AllowanceReq{},
+		}, money.FromBaht(41000), money.Money(241674), money.FromBaht(12000)},
+		// wht already over the salary tax is not given back by payroll
+		{"Test case 3", PayrollWithholdingReq{
+			Month:         7,
+			MonthlySalary: money.FromBaht(50000),
+			YTDIncome:     money.FromBaht(300000),
+			YTDWHT:        money.FromBaht(30000),
+			Allowances:    []calculator.AllowanceReq{},
+		}, money.FromBaht(29000), money.FromBaht(0), money.FromBaht(0)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			result, err := usecase.Calculate(tc.req)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedAnnualTax, result.AnnualTax)
+			assert.Equal(t, tc.expectedAnnualTax, result.Calculation.Tax)
+			assert.Nil(t, result.Calculation.Explanation)
+			assert.Equal(t, tc.expectedSalaryWHT, result.SalaryWHT)
+			assert.Equal(t, tc.expectedBonusWHT, result.BonusWHT)
+			assert.Equal(t, tc.expectedSalaryWHT+tc.expectedBonusWHT, result.WHT)
+		})
+	}
+}
+
+func TestCalculate_ShouldWithholdAnnualTax_WhenEveryMonthOfYearIsPaid(t *testing.T) {
+	// Arrange
+	usecase := NewPayrollWithholdingUseCase(mock.NewMockTaxCalculatorUseCase())
+	req := PayrollWithholdingReq{
+		MonthlySalary: money.FromBaht(50000),
+		Allowances:    []calculator.AllowanceReq{},
+	}
+
+	// Act
+	for month := 1; month <= 12; month++ {
+		req.Month = month
+		req.Bonus = 0
+
+		if month == 6 {
+			req.Bonus = money.FromBaht(100000)
+		}
+
+		result, err := usecase.Calculate(req)
+
+		assert.NoError(t, err)
+
+		req.YTDIncome += req.MonthlySalary + req.Bonus
+		req.YTDWHT += result.WHT
+	}
+
+	// Assert
+	// 700,000 - 100,000 (expense) - 60,000 (personal) = 540,000 has 41,000 tax
+	assert.Equal(t, money.FromBaht(700000), req.YTDIncome)
+	assert.Equal(t, money.FromBaht(41000), req.YTDWHT)
+}
+
+type mockTaxCalculatorUseCaseCaseTaxYearNotSupported struct {
+	calculator.TaxCalculatorUseCase
+}
+
+func (m *mockTaxCalculatorUseCaseCaseTaxYearNotSupported) GetTaxSetting(year int) (calculator.TaxSetting, error) {
+	return calculator.TaxSetting{}, calculator.ErrTaxYearNotSupported
+}
+
+func TestCalculate_ShouldReturnErr_WhenGetTaxSettingError(t *testing.T) {
+	// Arrange
+	usecase := NewPayrollWithholdingUseCase(&mockTaxCalculatorUseCaseCaseTaxYearNotSupported{})
+	req := PayrollWithholdingReq{
+		TaxYear:       2500,
+		Month:         1,
+		MonthlySalary: money.FromBaht(50000),
+	}
+
+	// Act
+	_, err := usecase.Calculate(req)
+
+	// Assert
+	assert.ErrorIs(t, err, calculator.ErrTaxYearNotSupported)
+}
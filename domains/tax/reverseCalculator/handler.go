@@ -0,0 +1,56 @@
+package reverseCalculator
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+)
+
+type ReverseTaxCalculatorHttpHandler interface {
+	CalculateReverseTax(c echo.Context) error
+}
+
+type reverseTaxCalculatorHttpHandler struct {
+	reverseTaxCalculatorUseCase ReverseTaxCalculatorUseCase
+}
+
+func NewReverseTaxCalculatorHttpHandler(reverseTaxCalculatorUseCase ReverseTaxCalculatorUseCase) ReverseTaxCalculatorHttpHandler {
+	return &reverseTaxCalculatorHttpHandler{
+		reverseTaxCalculatorUseCase: reverseTaxCalculatorUseCase,
+	}
+}
+
+func (r *reverseTaxCalculatorHttpHandler) CalculateReverseTax(c echo.Context) error {
+	var req ReverseTaxCalculatorReq
+
+	err := c.Bind(&req)
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if err = c.Validate(req); err != nil {
+		return err
+	}
+
+	res, err := r.reverseTaxCalculatorUseCase.Calculate(req)
+
+	if errors.Is(err, calculator.ErrTaxYearNotSupported) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Tax year not supported")
+	}
+
+	if errors.Is(err, ErrTargetNotReachable) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Target not reachable")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,160 @@
+package reverseCalculator
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/constant/taxMethod"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/money"
+	myValidator "github.com/larb26656/assessment-tax/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockReverseTaxCalculatorUseCase struct {
+}
+
+func (m *mockReverseTaxCalculatorUseCase) Calculate(req ReverseTaxCalculatorReq) (ReverseTaxCalculatorRes, error) {
+	return ReverseTaxCalculatorRes{
+		TotalIncome: money.FromBaht(500000),
+		TakeHome:    money.FromBaht(471000),
+		Calculation: calculator.TaxCalculatorRes{
+			Tax:            money.FromBaht(29000),
+			TaxRefund:      money.FromBaht(0),
+			TaxLevel:       []calculator.TaxLevelRes{},
+			TaxMethod:      taxMethod.Progressive,
+			ProgressiveTax: money.FromBaht(29000),
+		},
+	}, nil
+}
+
+type mockReverseTaxCalculatorUseCaseCaseError struct {
+	err error
+}
+
+func (m *mockReverseTaxCalculatorUseCaseCaseError) Calculate(req ReverseTaxCalculatorReq) (ReverseTaxCalculatorRes, error) {
+	return ReverseTaxCalculatorRes{}, m.err
+}
+
+func mockCalculateReverseTaxHttpReq(reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	req := httptest.NewRequest(http.MethodPost, "/tax/reverse-calculations", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+func TestCalculateReverseTaxHandler_ShouldGetBadRequest_WhenInvalidInput(t *testing.T) {
+	// Arrange
+	handler := NewReverseTaxCalculatorHttpHandler(&mockReverseTaxCalculatorUseCase{})
+
+	testCases := []struct {
+		name    string
+		reqBody string
+	}{
+		{"Test case 1", `{"targetTax": "abc", "wht": 0.0, "allowances": []}`},
+		{"Test case 2", `{"wht": 0.0, "allowances": []}`},
+		{"Test case 3", `{"targetTax": 1000.0, "targetTakeHome": 1000.0, "wht": 0.0, "allowances": []}`},
+		{"Test case 4", `{"targetTax": -1.0, "wht": 0.0, "allowances": []}`},
+		{"Test case 5", `{"targetTakeHome": 1000.0, "wht": -1.0, "allowances": []}`},
+		{"Test case 6", `{"targetTakeHome": 1000.0, "wht": 0.0}`},
+		{"Test case 7", `{"targetTakeHome": 1000.0, "wht": 0.0, "allowances": [{"allowanceType": "unknown", "amount": 100.0}]}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, _ := mockCalculateReverseTaxHttpReq(tc.reqBody)
+
+			// Act
+			err := handler.CalculateReverseTax(c)
+
+			// Assert
+			he, ok := err.(*echo.HTTPError)
+
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+func TestCalculateReverseTaxHandler_ShouldGetError_WhenUsecaseError(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{"Test case 1", calculator.ErrTaxYearNotSupported, http.StatusBadRequest},
+		{"Test case 2", ErrTargetNotReachable, http.StatusBadRequest},
+		{"Test case 3", errors.New("error on calculate"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler := NewReverseTaxCalculatorHttpHandler(&mockReverseTaxCalculatorUseCaseCaseError{err: tc.err})
+			_, c, _ := mockCalculateReverseTaxHttpReq(`{"targetTax": 1000.0, "wht": 0.0, "allowances": []}`)
+
+			// Act
+			err := handler.CalculateReverseTax(c)
+
+			// Assert
+			he, ok := err.(*echo.HTTPError)
+
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCode, he.Code)
+		})
+	}
+}
+
+func TestCalculateReverseTaxHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	handler := NewReverseTaxCalculatorHttpHandler(&mockReverseTaxCalculatorUseCase{})
+
+	testCases := []struct {
+		name    string
+		reqBody string
+	}{
+		{"Test case 1", `{"targetTax": 29000.0, "wht": 0.0, "allowances": []}`},
+		{"Test case 2", `{"targetTakeHome": 471000.0, "wht": 0.0, "allowances": [{"allowanceType": "donation", "amount": 0.0}]}`},
+		{"Test case 3", `{"targetTax": 0, "wht": 0.0, "allowances": []}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, rec := mockCalculateReverseTaxHttpReq(tc.reqBody)
+
+			// Act
+			err := handler.CalculateReverseTax(c)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+			assert.JSONEq(t, `{
+				"totalIncome": 500000,
+				"takeHome": 471000,
+				"calculation": {
+					"tax": 29000,
+					"taxRefund": 0,
+					"taxLevel": [],
+					"effectiveTaxRate": 0,
+					"effectiveNetTaxRate": 0,
+					"marginalTaxRate": 0,
+					"nextBracketDistance": null,
+					"taxMethod": "progressive",
+					"progressiveTax": 29000,
+					"minimumTax": 0
+				}
+			}`, rec.Body.String())
+		})
+	}
+}
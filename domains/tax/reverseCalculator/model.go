@@ -0,0 +1,21 @@
+package reverseCalculator
+
+import (
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/money"
+)
+
+// ReverseTaxCalculatorReq takes exactly one target, the tax payable after wht or the take-home income
+type ReverseTaxCalculatorReq struct {
+	TaxYear        int                       `json:"taxYear" validate:"omitempty,gte=2500"`
+	TargetTax      *money.Money              `json:"targetTax" validate:"required_without=TargetTakeHome,excluded_with=TargetTakeHome,omitempty,gte=0"`
+	TargetTakeHome *money.Money              `json:"targetTakeHome" validate:"required_without=TargetTax,excluded_with=TargetTax,omitempty,gte=0"`
+	WHT            money.Money               `json:"wht" validate:"gte=0"`
+	Allowances     []calculator.AllowanceReq `json:"allowances" validate:"required,dive"`
+}
+
+type ReverseTaxCalculatorRes struct {
+	TotalIncome money.Money                 `json:"totalIncome"`
+	TakeHome    money.Money                 `json:"takeHome"`
+	Calculation calculator.TaxCalculatorRes `json:"calculation"`
+}
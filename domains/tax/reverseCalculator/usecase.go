@@ -0,0 +1,109 @@
+package reverseCalculator
+
+import (
+	"errors"
+
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/money"
+)
+
+var ErrTargetNotReachable = errors.New("target not reachable")
+
+// maxTotalIncome bounds the search, one hundred billion baht is far above any real income
+const maxTotalIncome = 100000000000 * money.Baht
+
+type ReverseTaxCalculatorUseCase interface {
+	Calculate(req ReverseTaxCalculatorReq) (ReverseTaxCalculatorRes, error)
+}
+
+type reverseTaxCalculatorUseCase struct {
+	taxCalculatorUseCase calculator.TaxCalculatorUseCase
+}
+
+func NewReverseTaxCalculatorUseCase(taxCalculatorUseCase calculator.TaxCalculatorUseCase) ReverseTaxCalculatorUseCase {
+	return &reverseTaxCalculatorUseCase{
+		taxCalculatorUseCase: taxCalculatorUseCase,
+	}
+}
+
+// takeHome is the income left after every tax of the year, the wht already paid included
+func takeHome(totalIncome money.Money, wht money.Money, res calculator.TaxCalculatorRes) money.Money {
+	return totalIncome - wht - res.Tax + res.TaxRefund
+}
+
+// Calculate returns the smallest total income whose tax or take-home reaches the target.
+// Tax and take-home never decrease as income grows so the income is found by binary search on satang.
+func (r *reverseTaxCalculatorUseCase) Calculate(req ReverseTaxCalculatorReq) (ReverseTaxCalculatorRes, error) {
+	if req.TargetTax == nil && req.TargetTakeHome == nil {
+		return ReverseTaxCalculatorRes{}, ErrTargetNotReachable
+	}
+
+	setting, err := r.taxCalculatorUseCase.GetTaxSetting(taxYear.Resolve(req.TaxYear))
+
+	if err != nil {
+		return ReverseTaxCalculatorRes{}, err
+	}
+
+	// calcErr keeps the first error of the search, it is returned once the search stops
+	var calcErr error
+
+	calculate := func(totalIncome money.Money) calculator.TaxCalculatorRes {
+		res, err := r.taxCalculatorUseCase.CalculateWithSetting(calculator.TaxCalculatorReq{
+			TaxYear:     req.TaxYear,
+			TotalIncome: totalIncome,
+			WHT:         req.WHT,
+			Allowances:  req.Allowances,
+		}, setting)
+
+		if err != nil && calcErr == nil {
+			calcErr = err
+		}
+
+		return res
+	}
+
+	reached := func(totalIncome money.Money) bool {
+		res := calculate(totalIncome)
+
+		if req.TargetTax != nil {
+			return res.Tax >= *req.TargetTax
+		}
+
+		return takeHome(totalIncome, req.WHT, res) >= *req.TargetTakeHome
+	}
+
+	if !reached(maxTotalIncome) {
+		if calcErr != nil {
+			return ReverseTaxCalculatorRes{}, calcErr
+		}
+
+		return ReverseTaxCalculatorRes{}, ErrTargetNotReachable
+	}
+
+	low, high := money.Money(0), maxTotalIncome
+
+	for low < high {
+		mid := low + (high-low)/2
+
+		if reached(mid) {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+
+	res := calculate(low)
+	// the explanation is only given by tax/calculations with explain=true
+	res.Explanation = nil
+
+	if calcErr != nil {
+		return ReverseTaxCalculatorRes{}, calcErr
+	}
+
+	return ReverseTaxCalculatorRes{
+		TotalIncome: low,
+		TakeHome:    takeHome(low, req.WHT, res),
+		Calculation: res,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package reverseCalculator
+
+import (
+	"testing"
+
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/mock"
+	"github.com/larb26656/assessment-tax/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockTarget(baht int64) *money.Money {
+	amount := money.FromBaht(baht)
+
+	return &amount
+}
+
+func TestCalculate_ShouldReturnTotalIncome_WhenTargetTax(t *testing.T) {
+	// Arrange
+	usecase := NewReverseTaxCalculatorUseCase(mock.NewMockTaxCalculatorUseCase())
+
+	testCases := []struct {
+		name                string
+		targetTax           money.Money
+		wht                 money.Money
+		allowances          []calculator.AllowanceReq
+		expectedTotalIncome money.Money
+	}{
+		{"Test case 1", money.FromBaht(29000), money.FromBaht(0), []calculator.AllowanceReq{}, money.FromBaht(500000)},
+		{"Test case 2", money.FromBaht(4000), money.FromBaht(25000), []calculator.AllowanceReq{}, money.FromBaht(500000)},
+		{"Test case 3", money.FromBaht(27000), money.FromBaht(0), []calculator.AllowanceReq{
+			{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(20000)},
+		}, money.FromBaht(500000)},
+		{"Test case 4", money.FromBaht(0), money.FromBaht(0), []calculator.AllowanceReq{}, money.FromBaht(0)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			result, err := usecase.Calculate(ReverseTaxCalculatorReq{
+				TargetTax:  &tc.targetTax,
+				WHT:        tc.wht,
+				Allowances: tc.allowances,
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTotalIncome, result.TotalIncome)
+			assert.Equal(t, tc.targetTax, result.Calculation.Tax)
+			assert.Nil(t, result.Calculation.Explanation)
+		})
+	}
+}
+
+func TestCalculate_ShouldReturnSmallestTotalIncome_WhenTargetTakeHome(t *testing.T) {
+	// Arrange
+	usecase := NewReverseTaxCalculatorUseCase(mock.NewMockTaxCalculatorUseCase())
+
+	testCases := []struct {
+		name                string
+		targetTakeHome      money.Money
+		wht                 money.Money
+		expectedTotalIncome money.Money
+	}{
+		{"Test case 1", money.FromBaht(200000), money.FromBaht(0), money.FromBaht(200000)},
+		{"Test case 2", money.FromBaht(200000), money.FromBaht(5000), money.FromBaht(200000)},
+		// tax of 999,999.99 is truncated to satang so it already takes home 899,000
+		{"Test case 3", money.FromBaht(899000), money.FromBaht(0), money.Money(99999999)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			result, err := usecase.Calculate(ReverseTaxCalculatorReq{
+				TargetTakeHome: &tc.targetTakeHome,
+				WHT:            tc.wht,
+				Allowances:     []calculator.AllowanceReq{},
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTotalIncome, result.TotalIncome)
+			assert.Equal(t, tc.targetTakeHome, result.TakeHome)
+		})
+	}
+}
+
+func TestCalculate_ShouldReturnErrTargetNotReachable_WhenTargetTooHigh(t *testing.T) {
+	// Arrange
+	usecase := NewReverseTaxCalculatorUseCase(mock.NewMockTaxCalculatorUseCase())
+	req := ReverseTaxCalculatorReq{
+		TargetTakeHome: mockTarget(1000000000000),
+		Allowances:     []calculator.AllowanceReq{},
+	}
+
+	// Act
+	_, err := usecase.Calculate(req)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrTargetNotReachable)
+}
+
+func TestCalculate_ShouldReturnErrTargetNotReachable_WhenTargetNotSpecified(t *testing.T) {
+	// Arrange
+	usecase := NewReverseTaxCalculatorUseCase(mock.NewMockTaxCalculatorUseCase())
+
+	// Act
+	_, err := usecase.Calculate(ReverseTaxCalculatorReq{})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrTargetNotReachable)
+}
+
+type mockTaxCalculatorUseCaseCaseTaxYearNotSupported struct {
+	calculator.TaxCalculatorUseCase
+}
+
+func (m *mockTaxCalculatorUseCaseCaseTaxYearNotSupported) GetTaxSetting(year int) (calculator.TaxSetting, error) {
+	return calculator.TaxSetting{}, calculator.ErrTaxYearNotSupported
+}
+
+func TestCalculate_ShouldReturnErr_WhenGetTaxSettingError(t *testing.T) {
+	// Arrange
+	usecase := NewReverseTaxCalculatorUseCase(&mockTaxCalculatorUseCaseCaseTaxYearNotSupported{})
+	req := ReverseTaxCalculatorReq{
+		TaxYear:   2500,
+		TargetTax: mockTarget(1000),
+	}
+
+	// Act
+	_, err := usecase.Calculate(req)
+
+	// Assert
+	assert.ErrorIs(t, err, calculator.ErrTaxYearNotSupported)
+}
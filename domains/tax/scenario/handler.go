@@ -0,0 +1,52 @@
+package scenario
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+)
+
+type TaxScenarioHttpHandler interface {
+	CompareScenarios(c echo.Context) error
+}
+
+type taxScenarioHttpHandler struct {
+	taxScenarioUseCase TaxScenarioUseCase
+}
+
+func NewTaxScenarioHttpHandler(taxScenarioUseCase TaxScenarioUseCase) TaxScenarioHttpHandler {
+	return &taxScenarioHttpHandler{
+		taxScenarioUseCase: taxScenarioUseCase,
+	}
+}
+
+func (t *taxScenarioHttpHandler) CompareScenarios(c echo.Context) error {
+	var req TaxScenariosReq
+
+	err := c.Bind(&req)
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Bad request")
+	}
+
+	if err = c.Validate(req); err != nil {
+		return err
+	}
+
+	res, err := t.taxScenarioUseCase.Compare(req)
+
+	if errors.Is(err, calculator.ErrTaxYearNotSupported) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Tax year not supported")
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Something went wrong")
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
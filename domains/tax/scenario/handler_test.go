@@ -0,0 +1,190 @@
+package scenario
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/constant/taxMethod"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/money"
+	myValidator "github.com/larb26656/assessment-tax/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTaxScenarioUseCase struct {
+}
+
+func (m *mockTaxScenarioUseCase) Compare(req TaxScenariosReq) (TaxScenariosRes, error) {
+	return TaxScenariosRes{
+		Base: calculator.TaxCalculatorRes{
+			Tax:              money.FromBaht(29000),
+			TaxRefund:        money.FromBaht(0),
+			TaxLevel:         []calculator.TaxLevelRes{},
+			EffectiveTaxRate: money.Rate(580),
+			MarginalTaxRate:  money.FromPercent(10),
+			TaxMethod:        taxMethod.Progressive,
+			ProgressiveTax:   money.FromBaht(29000),
+		},
+		Scenarios: []TaxScenarioRes{
+			{
+				Name: "extra donation",
+				Result: calculator.TaxCalculatorRes{
+					Tax:              money.FromBaht(19000),
+					TaxRefund:        money.FromBaht(0),
+					TaxLevel:         []calculator.TaxLevelRes{},
+					EffectiveTaxRate: money.Rate(380),
+					MarginalTaxRate:  money.FromPercent(10),
+					TaxMethod:        taxMethod.Progressive,
+					ProgressiveTax:   money.FromBaht(19000),
+				},
+				Difference: TaxScenarioDiffRes{
+					Tax:              money.FromBaht(-10000),
+					TaxRefund:        money.FromBaht(0),
+					EffectiveTaxRate: money.Rate(-200),
+					MarginalTaxRate:  money.FromPercent(0),
+				},
+			},
+		},
+	}, nil
+}
+
+type mockTaxScenarioUseCaseCaseError struct {
+	err error
+}
+
+func (m *mockTaxScenarioUseCaseCaseError) Compare(req TaxScenariosReq) (TaxScenariosRes, error) {
+	return TaxScenariosRes{}, m.err
+}
+
+func mockCompareScenariosHttpReq(reqBody string) (*echo.Echo, echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+
+	e.Validator = myValidator.NewStructValidator(validator.New())
+
+	req := httptest.NewRequest(http.MethodPost, "/tax/scenarios", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	return e, c, rec
+}
+
+const mockBase = `"base": {"totalIncome": 500000.0, "wht": 0.0, "allowances": [{"allowanceType": "donation", "amount": 0.0}]}`
+
+func TestCompareScenariosHandler_ShouldGetBadRequest_WhenInvalidInput(t *testing.T) {
+	// Arrange
+	handler := NewTaxScenarioHttpHandler(&mockTaxScenarioUseCase{})
+
+	testCases := []struct {
+		name    string
+		reqBody string
+	}{
+		{"Test case 1", `{"base": "abc"}`},
+		{"Test case 2", `{` + mockBase + `}`},
+		{"Test case 3", `{` + mockBase + `, "scenarios": []}`},
+		{"Test case 4", `{` + mockBase + `, "scenarios": [{"wht": 1000.0}]}`},
+		{"Test case 5", `{` + mockBase + `, "scenarios": [{"name": "a", "wht": -1.0}]}`},
+		{"Test case 6", `{` + mockBase + `, "scenarios": [{"name": "a"}, {"name": "a"}]}`},
+		{"Test case 7", `{` + mockBase + `, "scenarios": [{"name": "a", "extraAllowances": [{"allowanceType": "unknown", "amount": 1.0}]}]}`},
+		{"Test case 8", `{"base": {"totalIncome": -1.0, "wht": 0.0, "allowances": []}, "scenarios": [{"name": "a"}]}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, c, _ := mockCompareScenariosHttpReq(tc.reqBody)
+
+			// Act
+			err := handler.CompareScenarios(c)
+
+			// Assert
+			he, ok := err.(*echo.HTTPError)
+
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+		})
+	}
+}
+
+func TestCompareScenariosHandler_ShouldGetError_WhenUsecaseError(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{"Test case 1", calculator.ErrTaxYearNotSupported, http.StatusBadRequest},
+		{"Test case 2", errors.New("error on compare"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			handler := NewTaxScenarioHttpHandler(&mockTaxScenarioUseCaseCaseError{err: tc.err})
+			_, c, _ := mockCompareScenariosHttpReq(`{` + mockBase + `, "scenarios": [{"name": "a"}]}`)
+
+			// Act
+			err := handler.CompareScenarios(c)
+
+			// Assert
+			he, ok := err.(*echo.HTTPError)
+
+			assert.True(t, ok)
+			assert.Equal(t, tc.expectedCode, he.Code)
+		})
+	}
+}
+
+func TestCompareScenariosHandler_ShouldGetSuccess_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	handler := NewTaxScenarioHttpHandler(&mockTaxScenarioUseCase{})
+	reqBody := `{` + mockBase + `, "scenarios": [{"name": "extra donation", "extraAllowances": [{"allowanceType": "donation", "amount": 100000.0}]}]}`
+	_, c, rec := mockCompareScenariosHttpReq(reqBody)
+
+	// Act
+	err := handler.CompareScenarios(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+	assert.JSONEq(t, `{
+		"base": {
+			"tax": 29000,
+			"taxRefund": 0,
+			"taxLevel": [],
+			"effectiveTaxRate": 5.80,
+			"effectiveNetTaxRate": 0,
+			"marginalTaxRate": 10,
+			"nextBracketDistance": null,
+			"taxMethod": "progressive",
+			"progressiveTax": 29000,
+			"minimumTax": 0
+		},
+		"scenarios": [
+			{
+				"name": "extra donation",
+				"result": {
+					"tax": 19000,
+					"taxRefund": 0,
+					"taxLevel": [],
+					"effectiveTaxRate": 3.80,
+					"effectiveNetTaxRate": 0,
+					"marginalTaxRate": 10,
+					"nextBracketDistance": null,
+					"taxMethod": "progressive",
+					"progressiveTax": 19000,
+					"minimumTax": 0
+				},
+				"difference": {
+					"tax": -10000,
+					"taxRefund": 0,
+					"effectiveTaxRate": -2.00,
+					"marginalTaxRate": 0
+				}
+			}
+		]
+	}`, rec.Body.String())
+}
@@ -0,0 +1,39 @@
+package scenario
+
+import (
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/money"
+)
+
+// TaxScenarioReq is a variation of the base request, income and wht replace the base values
+// and extra allowances are added on top of the base allowances
+type TaxScenarioReq struct {
+	Name            string                    `json:"name" validate:"required"`
+	TotalIncome     *money.Money              `json:"totalIncome" validate:"omitempty,gte=0"`
+	WHT             *money.Money              `json:"wht" validate:"omitempty,gte=0"`
+	ExtraAllowances []calculator.AllowanceReq `json:"extraAllowances" validate:"dive"`
+}
+
+type TaxScenariosReq struct {
+	Base      calculator.TaxCalculatorReq `json:"base"`
+	Scenarios []TaxScenarioReq            `json:"scenarios" validate:"required,min=1,max=20,unique=Name,dive"`
+}
+
+// TaxScenarioDiffRes is the scenario value minus the base value
+type TaxScenarioDiffRes struct {
+	Tax              money.Money `json:"tax"`
+	TaxRefund        money.Money `json:"taxRefund"`
+	EffectiveTaxRate money.Rate  `json:"effectiveTaxRate"`
+	MarginalTaxRate  money.Rate  `json:"marginalTaxRate"`
+}
+
+type TaxScenarioRes struct {
+	Name       string                      `json:"name"`
+	Result     calculator.TaxCalculatorRes `json:"result"`
+	Difference TaxScenarioDiffRes          `json:"difference"`
+}
+
+type TaxScenariosRes struct {
+	Base      calculator.TaxCalculatorRes `json:"base"`
+	Scenarios []TaxScenarioRes            `json:"scenarios"`
+}
@@ -0,0 +1,80 @@
+package scenario
+
+import (
+	"github.com/larb26656/assessment-tax/constant/taxYear"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+)
+
+type TaxScenarioUseCase interface {
+	Compare(req TaxScenariosReq) (TaxScenariosRes, error)
+}
+
+type taxScenarioUseCase struct {
+	taxCalculatorUseCase calculator.TaxCalculatorUseCase
+}
+
+func NewTaxScenarioUseCase(taxCalculatorUseCase calculator.TaxCalculatorUseCase) TaxScenarioUseCase {
+	return &taxScenarioUseCase{
+		taxCalculatorUseCase: taxCalculatorUseCase,
+	}
+}
+
+func (t *taxScenarioUseCase) calculate(req calculator.TaxCalculatorReq, setting calculator.TaxSetting) (calculator.TaxCalculatorRes, error) {
+	res, err := t.taxCalculatorUseCase.CalculateWithSetting(req, setting)
+	res.Explanation = nil
+
+	return res, err
+}
+
+// Compare calculates the base and every scenario with the same tax setting
+func (t *taxScenarioUseCase) Compare(req TaxScenariosReq) (TaxScenariosRes, error) {
+	setting, err := t.taxCalculatorUseCase.GetTaxSetting(taxYear.Resolve(req.Base.TaxYear))
+
+	if err != nil {
+		return TaxScenariosRes{}, err
+	}
+
+	base, err := t.calculate(req.Base, setting)
+
+	if err != nil {
+		return TaxScenariosRes{}, err
+	}
+
+	scenarios := make([]TaxScenarioRes, len(req.Scenarios))
+
+	for i, scenario := range req.Scenarios {
+		// a scenario keeps everything of the base, e.g. incomes and dependents
+		scenarioReq := req.Base
+		scenarioReq.Allowances = append(append([]calculator.AllowanceReq{}, req.Base.Allowances...), scenario.ExtraAllowances...)
+
+		if scenario.TotalIncome != nil {
+			scenarioReq.TotalIncome = *scenario.TotalIncome
+		}
+
+		if scenario.WHT != nil {
+			scenarioReq.WHT = *scenario.WHT
+		}
+
+		result, err := t.calculate(scenarioReq, setting)
+
+		if err != nil {
+			return TaxScenariosRes{}, err
+		}
+
+		scenarios[i] = TaxScenarioRes{
+			Name:   scenario.Name,
+			Result: result,
+			Difference: TaxScenarioDiffRes{
+				Tax:              result.Tax - base.Tax,
+				TaxRefund:        result.TaxRefund - base.TaxRefund,
+				EffectiveTaxRate: result.EffectiveTaxRate - base.EffectiveTaxRate,
+				MarginalTaxRate:  result.MarginalTaxRate - base.MarginalTaxRate,
+			},
+		}
+	}
+
+	return TaxScenariosRes{
+		Base:      base,
+		Scenarios: scenarios,
+	}, nil
+}
@@ -0,0 +1,129 @@
+package scenario
+
+import (
+	"testing"
+
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/constant/incomeType"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/mock"
+	"github.com/larb26656/assessment-tax/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockAmount(baht int64) *money.Money {
+	amount := money.FromBaht(baht)
+
+	return &amount
+}
+
+func TestCompare_ShouldReturnDifferenceFromBase_WhenCorrectInput(t *testing.T) {
+	// Arrange
+	usecase := NewTaxScenarioUseCase(mock.NewMockTaxCalculatorUseCase())
+	req := TaxScenariosReq{
+		Base: calculator.TaxCalculatorReq{
+			TotalIncome: money.FromBaht(500000),
+			WHT:         money.FromBaht(0),
+			Allowances: []calculator.AllowanceReq{
+				{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(0)},
+			},
+		},
+		Scenarios: []TaxScenarioReq{
+			{Name: "extra donation", ExtraAllowances: []calculator.AllowanceReq{
+				{AllowanceType: allowanceType.Donation, Amount: money.FromBaht(100000)},
+			}},
+			{Name: "more k-receipt", ExtraAllowances: []calculator.AllowanceReq{
+				{AllowanceType: allowanceType.KReceipt, Amount: money.FromBaht(50000)},
+			}},
+			{Name: "higher wht", WHT: mockAmount(30000)},
+			{Name: "bonus", TotalIncome: mockAmount(700000)},
+		},
+	}
+
+	testCases := []struct {
+		name               string
+		expectedTax        money.Money
+		expectedDifference TaxScenarioDiffRes
+	}{
+		{"extra donation", money.FromBaht(24600), TaxScenarioDiffRes{Tax: money.FromBaht(-4400), TaxRefund: money.FromBaht(0), EffectiveTaxRate: money.Rate(-88), MarginalTaxRate: money.FromPercent(0)}},
+		{"more k-receipt", money.FromBaht(24000), TaxScenarioDiffRes{Tax: money.FromBaht(-5000), TaxRefund: money.FromBaht(0), EffectiveTaxRate: money.Rate(-100), MarginalTaxRate: money.FromPercent(0)}},
+		{"higher wht", money.FromBaht(0), TaxScenarioDiffRes{Tax: money.FromBaht(-29000), TaxRefund: money.FromBaht(1000), EffectiveTaxRate: money.Rate(0), MarginalTaxRate: money.FromPercent(0)}},
+		{"bonus", money.FromBaht(56000), TaxScenarioDiffRes{Tax: money.FromBaht(27000), TaxRefund: money.FromBaht(0), EffectiveTaxRate: money.Rate(220), MarginalTaxRate: money.FromPercent(5)}},
+	}
+
+	// Act
+	result, err := usecase.Compare(req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromBaht(29000), result.Base.Tax)
+	assert.Nil(t, result.Base.Explanation)
+	assert.Len(t, req.Base.Allowances, 1)
+	assert.Len(t, result.Scenarios, len(testCases))
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.name, result.Scenarios[i].Name)
+			assert.Equal(t, tc.expectedTax, result.Scenarios[i].Result.Tax)
+			assert.Equal(t, tc.expectedDifference, result.Scenarios[i].Difference)
+			assert.Nil(t, result.Scenarios[i].Result.Explanation)
+		})
+	}
+}
+
+func TestCompare_ShouldKeepIncomesAndDependentsOfBase_WhenScenarioChangesAllowances(t *testing.T) {
+	// Arrange
+	usecase := NewTaxScenarioUseCase(mock.NewMockTaxCalculatorUseCase())
+	req := TaxScenariosReq{
+		Base: calculator.TaxCalculatorReq{
+			Allowances: []calculator.AllowanceReq{},
+			Incomes: []calculator.IncomeReq{
+				{IncomeType: incomeType.Salary, Amount: money.FromBaht(600000)},
+			},
+			Dependents: calculator.DependentsReq{
+				Spouse: &calculator.SpouseReq{Income: money.FromBaht(0)},
+			},
+		},
+		Scenarios: []TaxScenarioReq{
+			{Name: "k-receipt", ExtraAllowances: []calculator.AllowanceReq{
+				{AllowanceType: allowanceType.KReceipt, Amount: money.FromBaht(50000)},
+			}},
+		},
+	}
+
+	// Act
+	result, err := usecase.Compare(req)
+
+	// Assert
+	// 600,000 - 100,000 (expense) - 60,000 (personal) - 60,000 (spouse) = 380,000
+	assert.NoError(t, err)
+	assert.Equal(t, money.FromBaht(23000), result.Base.Tax)
+	assert.Equal(t, money.FromBaht(18000), result.Scenarios[0].Result.Tax)
+	assert.Equal(t, money.FromBaht(-5000), result.Scenarios[0].Difference.Tax)
+}
+
+type mockTaxCalculatorUseCaseCaseTaxYearNotSupported struct {
+	calculator.TaxCalculatorUseCase
+}
+
+func (m *mockTaxCalculatorUseCaseCaseTaxYearNotSupported) GetTaxSetting(year int) (calculator.TaxSetting, error) {
+	return calculator.TaxSetting{}, calculator.ErrTaxYearNotSupported
+}
+
+func TestCompare_ShouldReturnErr_WhenGetTaxSettingError(t *testing.T) {
+	// Arrange
+	usecase := NewTaxScenarioUseCase(&mockTaxCalculatorUseCaseCaseTaxYearNotSupported{})
+	req := TaxScenariosReq{
+		Base: calculator.TaxCalculatorReq{
+			TaxYear:     2500,
+			TotalIncome: money.FromBaht(500000),
+		},
+		Scenarios: []TaxScenarioReq{{Name: "bonus", TotalIncome: mockAmount(700000)}},
+	}
+
+	// Act
+	_, err := usecase.Compare(req)
+
+	// Assert
+	assert.ErrorIs(t, err, calculator.ErrTaxYearNotSupported)
+}
@@ -1,12 +1,113 @@
 CREATE TABLE tax_deduction_setting (
-    key VARCHAR(255) PRIMARY KEY,
-    value FLOAT8
+    tax_year INT NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    value NUMERIC(15, 2),
+    PRIMARY KEY (tax_year, key)
 );
 
 INSERT INTO
-    tax_deduction_setting ("key", value)
+    tax_deduction_setting (tax_year, "key", value)
 VALUES
-    ('personal', 60000),
-    ('k-receipt', 50000);
+    (2567, 'personal', 60000),
+    (2567, 'spouse', 60000),
+    (2567, 'child', 30000),
+    (2567, 'child-extra', 30000),
+    (2567, 'parent', 30000),
+    (2567, 'parent-max-income', 30000),
+    (2567, 'disabled-dependent', 60000),
+    (2567, 'donation', 100000),
+    (2567, 'donation-cap-rate', 10),
+    (2567, 'k-receipt', 50000),
+    (2567, 'rmf', 500000),
+    (2567, 'ssf', 200000),
+    (2567, 'provident-fund', 500000),
+    (2567, 'pension-insurance', 200000),
+    (2567, 'life-insurance', 100000),
+    (2567, 'health-insurance', 25000),
+    (2567, 'parent-health-insurance', 15000);
 
-;
\ No newline at end of file
+CREATE TABLE tax_bracket (
+    tax_year INT NOT NULL,
+    level INT NOT NULL,
+    min_income NUMERIC(15, 2) NOT NULL,
+    max_income NUMERIC(15, 2),
+    rate NUMERIC(5, 2) NOT NULL,
+    PRIMARY KEY (tax_year, level)
+);
+
+INSERT INTO
+    tax_bracket (tax_year, level, min_income, max_income, rate)
+VALUES
+    (2567, 1, 0, 150000, 0),
+    (2567, 2, 150000, 500000, 10),
+    (2567, 3, 500000, 1000000, 15),
+    (2567, 4, 1000000, 2000000, 20),
+    (2567, 5, 2000000, NULL, 35);
+
+CREATE TABLE tax_allowance_group (
+    tax_year INT NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    max_deduction NUMERIC(15, 2) NOT NULL,
+    PRIMARY KEY (tax_year, key)
+);
+
+CREATE TABLE tax_allowance_group_member (
+    tax_year INT NOT NULL,
+    group_key VARCHAR(255) NOT NULL,
+    allowance_type VARCHAR(255) NOT NULL,
+    PRIMARY KEY (tax_year, group_key, allowance_type),
+    FOREIGN KEY (tax_year, group_key) REFERENCES tax_allowance_group (tax_year, key) ON DELETE CASCADE
+);
+
+INSERT INTO
+    tax_allowance_group (tax_year, "key", max_deduction)
+VALUES
+    (2567, 'insurance', 100000),
+    (2567, 'retirement', 500000);
+
+INSERT INTO
+    tax_allowance_group_member (tax_year, group_key, allowance_type)
+VALUES
+    (2567, 'insurance', 'health-insurance'),
+    (2567, 'insurance', 'life-insurance'),
+    (2567, 'retirement', 'pension-insurance'),
+    (2567, 'retirement', 'provident-fund'),
+    (2567, 'retirement', 'rmf'),
+    (2567, 'retirement', 'ssf');
+
+CREATE TABLE tax_batch_job (
+    id VARCHAR(32) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    tax_year INT NOT NULL,
+    mode VARCHAR(20) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    total_rows INT NOT NULL,
+    processed_rows INT NOT NULL DEFAULT 0,
+    last_line INT NOT NULL DEFAULT 0,
+    attempt INT NOT NULL DEFAULT 0,
+    error_message TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX tax_batch_job_status_idx ON tax_batch_job (status, created_at);
+
+CREATE TABLE tax_batch_job_file (
+    job_id VARCHAR(32) NOT NULL,
+    chunk INT NOT NULL,
+    data BYTEA NOT NULL,
+    PRIMARY KEY (job_id, chunk),
+    FOREIGN KEY (job_id) REFERENCES tax_batch_job (id) ON DELETE CASCADE
+);
+
+CREATE TABLE tax_batch_job_result (
+    job_id VARCHAR(32) NOT NULL,
+    line INT NOT NULL,
+    result JSONB,
+    error JSONB,
+    PRIMARY KEY (job_id, line),
+    FOREIGN KEY (job_id) REFERENCES tax_batch_job (id) ON DELETE CASCADE
+);
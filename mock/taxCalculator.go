@@ -0,0 +1,153 @@
+package mock
+
+import (
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/domains/admin/allowanceGroup"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/allowance"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/dependent"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/personal"
+	"github.com/larb26656/assessment-tax/domains/admin/taxBracket"
+	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/money"
+)
+
+type mockPersonalDeductionUsecase struct {
+}
+
+func (p *mockPersonalDeductionUsecase) GetDeduction(taxYear int) (money.Money, error) {
+	return money.FromBaht(60000), nil
+}
+
+func (p *mockPersonalDeductionUsecase) UpdateDeduction(req personal.UpdatePersonalDeductionReq) (personal.UpdatePersonalDeductionRes, error) {
+	return personal.UpdatePersonalDeductionRes{}, nil
+}
+
+type mockDependentDeductionUsecase struct {
+}
+
+func (p *mockDependentDeductionUsecase) GetDeduction(taxYear int) (dependent.DependentDeduction, error) {
+	return dependent.DependentDeduction{
+		Spouse:            money.FromBaht(60000),
+		Child:             money.FromBaht(30000),
+		ChildExtra:        money.FromBaht(30000),
+		Parent:            money.FromBaht(30000),
+		ParentMaxIncome:   money.FromBaht(30000),
+		DisabledDependent: money.FromBaht(60000),
+	}, nil
+}
+
+func (p *mockDependentDeductionUsecase) UpdateDeduction(req dependent.UpdateDependentDeductionReq) (dependent.UpdateDependentDeductionRes, error) {
+	return dependent.UpdateDependentDeductionRes{}, nil
+}
+
+type mockAllowanceDeductionUsecase struct {
+}
+
+// mockMaxDeductions are the 2567 max deductions seeded in migration/init.sql
+var mockMaxDeductions = map[string]money.Money{
+	allowanceType.Donation:              money.FromBaht(100000),
+	allowanceType.KReceipt:              money.FromBaht(50000),
+	allowanceType.RMF:                   money.FromBaht(500000),
+	allowanceType.SSF:                   money.FromBaht(200000),
+	allowanceType.ProvidentFund:         money.FromBaht(500000),
+	allowanceType.PensionInsurance:      money.FromBaht(200000),
+	allowanceType.LifeInsurance:         money.FromBaht(100000),
+	allowanceType.HealthInsurance:       money.FromBaht(25000),
+	allowanceType.ParentHealthInsurance: money.FromBaht(15000),
+}
+
+func (p *mockAllowanceDeductionUsecase) GetMaxDeduction(taxYear int, key string) (money.Money, error) {
+	return mockMaxDeductions[key], nil
+}
+
+// mockCapRates are the 2567 cap rates seeded in migration/init.sql
+var mockCapRates = map[string]money.Rate{
+	allowanceType.Donation: money.FromPercent(10),
+}
+
+func (p *mockAllowanceDeductionUsecase) GetCapRate(taxYear int, key string) (money.Rate, error) {
+	return mockCapRates[key], nil
+}
+
+func (p *mockAllowanceDeductionUsecase) GetAllowanceTypes(taxYear int) (allowance.AllowanceTypesRes, error) {
+	return allowance.AllowanceTypesRes{}, nil
+}
+
+func (p *mockAllowanceDeductionUsecase) UpdateMaxDeduction(key string, req allowance.UpdateAllowanceDeductionReq) (allowance.UpdateAllowanceDeductionRes, error) {
+	return allowance.UpdateAllowanceDeductionRes{}, nil
+}
+
+func (p *mockAllowanceDeductionUsecase) UpdateMaxDeductions(taxYear int, maxDeductions []deduction.Deduction) error {
+	return nil
+}
+
+func (p *mockAllowanceDeductionUsecase) UpdateCapRate(key string, req allowance.UpdateAllowanceCapRateReq) (allowance.UpdateAllowanceCapRateRes, error) {
+	return allowance.UpdateAllowanceCapRateRes{}, nil
+}
+
+type mockAllowanceGroupUsecase struct {
+}
+
+func (p *mockAllowanceGroupUsecase) GetAllowanceGroups(taxYear int) ([]allowanceGroup.AllowanceGroup, error) {
+	return []allowanceGroup.AllowanceGroup{
+		{
+			Key:            "insurance",
+			MaxDeduction:   money.FromBaht(100000),
+			AllowanceTypes: []string{allowanceType.HealthInsurance, allowanceType.LifeInsurance},
+		},
+		{
+			Key:            "retirement",
+			MaxDeduction:   money.FromBaht(500000),
+			AllowanceTypes: []string{allowanceType.PensionInsurance, allowanceType.ProvidentFund, allowanceType.RMF, allowanceType.SSF},
+		},
+	}, nil
+}
+
+func (p *mockAllowanceGroupUsecase) UpdateAllowanceGroups(req allowanceGroup.UpdateAllowanceGroupsReq) (allowanceGroup.AllowanceGroupsRes, error) {
+	return allowanceGroup.AllowanceGroupsRes{}, nil
+}
+
+type mockTaxBracketUsecase struct {
+}
+
+func (p *mockTaxBracketUsecase) GetTaxBrackets(taxYear int) ([]taxBracket.TaxBracket, error) {
+	return NewMockTaxBrackets(), nil
+}
+
+func (p *mockTaxBracketUsecase) ValidateTaxBrackets(req taxBracket.UpdateTaxBracketsReq) taxBracket.ValidateTaxBracketsRes {
+	return taxBracket.ValidateTaxBracketsRes{}
+}
+
+func (p *mockTaxBracketUsecase) UpdateTaxBrackets(req taxBracket.UpdateTaxBracketsReq) (taxBracket.TaxBracketsRes, error) {
+	return taxBracket.TaxBracketsRes{}, nil
+}
+
+func mockMaxIncome(maxIncome int64) *money.Money {
+	amount := money.FromBaht(maxIncome)
+
+	return &amount
+}
+
+// NewMockTaxBrackets returns the 2567 progressive rate schedule
+func NewMockTaxBrackets() []taxBracket.TaxBracket {
+	return []taxBracket.TaxBracket{
+		{Level: 1, MinIncome: money.FromBaht(0), MaxIncome: mockMaxIncome(150000), Rate: money.FromPercent(0)},
+		{Level: 2, MinIncome: money.FromBaht(150000), MaxIncome: mockMaxIncome(500000), Rate: money.FromPercent(10)},
+		{Level: 3, MinIncome: money.FromBaht(500000), MaxIncome: mockMaxIncome(1000000), Rate: money.FromPercent(15)},
+		{Level: 4, MinIncome: money.FromBaht(1000000), MaxIncome: mockMaxIncome(2000000), Rate: money.FromPercent(20)},
+		{Level: 5, MinIncome: money.FromBaht(2000000), MaxIncome: nil, Rate: money.FromPercent(35)},
+	}
+}
+
+// NewMockTaxCalculatorUseCase returns the real calculator on the 2567 default settings,
+// personal deduction 60,000, the seeded dependent amounts and max deductions, the 100,000 insurance and the 500,000 retirement group caps
+func NewMockTaxCalculatorUseCase() calculator.TaxCalculatorUseCase {
+	return calculator.NewTaxCalculatorUseCase(
+		&mockPersonalDeductionUsecase{},
+		&mockDependentDeductionUsecase{},
+		&mockAllowanceDeductionUsecase{},
+		&mockAllowanceGroupUsecase{},
+		&mockTaxBracketUsecase{},
+	)
+}
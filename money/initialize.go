@@ -0,0 +1,264 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const Satang Money = 1
+const Baht Money = 100
+
+const Percent Rate = 100
+
+var ErrInvalidAmount = errors.New("invalid amount")
+var ErrOverflow = errors.New("amount overflow")
+
+// decimalPattern is a plain decimal, fractions like "1/3" and exponents like "1e3" are not amounts
+var decimalPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// Rounding policy: every amount below one satang is truncated toward zero,
+// the same way the Revenue Department drops fractions of satang on its forms.
+
+func FromBaht(baht int64) Money {
+	return Money(baht) * Baht
+}
+
+func FromPercent(percent int64) Rate {
+	return Rate(percent) * Percent
+}
+
+// Parse reads a decimal baht amount e.g. "1500.25" exactly
+func Parse(text string) (Money, error) {
+	value, err := parseScaled(text, int64(Baht))
+
+	if err != nil {
+		return 0, err
+	}
+
+	return Money(value), nil
+}
+
+// ParseRate reads a decimal percentage e.g. "12.5" exactly
+func ParseRate(text string) (Rate, error) {
+	value, err := parseScaled(text, int64(Percent))
+
+	if err != nil {
+		return 0, err
+	}
+
+	return Rate(value), nil
+}
+
+func parseScaled(text string, scale int64) (int64, error) {
+	text = strings.TrimSpace(text)
+
+	if !decimalPattern.MatchString(text) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, text)
+	}
+
+	value, ok := new(big.Rat).SetString(text)
+
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, text)
+	}
+
+	value.Mul(value, new(big.Rat).SetInt64(scale))
+
+	// Quo truncates toward zero
+	scaled := new(big.Int).Quo(value.Num(), value.Denom())
+
+	if !scaled.IsInt64() {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, text)
+	}
+
+	return scaled.Int64(), nil
+}
+
+func formatScaled(value int64, scale int64) string {
+	sign := ""
+
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, value/scale, value%scale)
+}
+
+func (m Money) String() string {
+	return formatScaled(int64(m), int64(Baht))
+}
+
+func (m Money) Float64() float64 {
+	return float64(m) / float64(Baht)
+}
+
+// mulQuo returns a * b / c truncated toward zero, ErrOverflow when it does not fit in int64
+func mulQuo(a, b, c int64) (int64, error) {
+	result := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+	result.Quo(result, big.NewInt(c))
+
+	if !result.IsInt64() {
+		return 0, fmt.Errorf("%w: %d * %d / %d", ErrOverflow, a, b, c)
+	}
+
+	return result.Int64(), nil
+}
+
+// MulRate returns rate percent of the amount, truncated to satang
+func (m Money) MulRate(rate Rate) (Money, error) {
+	result, err := mulQuo(int64(m), int64(rate), int64(100*Percent))
+
+	return Money(result), err
+}
+
+// GrossUpTax returns the tax at rate percent of the gross amount the amount was left from after that tax,
+// truncated to satang e.g. the corporate tax behind a dividend
+func (m Money) GrossUpTax(rate Rate) (Money, error) {
+	result, err := mulQuo(int64(m), int64(rate), int64(100*Percent-rate))
+
+	return Money(result), err
+}
+
+// Ratio returns part as a percentage of whole, truncated to 0.01 percent and 0 when whole is 0
+func Ratio(part, whole Money) (Rate, error) {
+	if whole == 0 {
+		return 0, nil
+	}
+
+	result, err := mulQuo(int64(part), int64(100*Percent), int64(whole))
+
+	return Rate(result), err
+}
+
+func Min(a, b Money) Money {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func Max(a, b Money) Money {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalScaled(data, int64(Baht))
+
+	if err != nil || value == nil {
+		return err
+	}
+
+	*m = Money(*value)
+
+	return nil
+}
+
+func (m *Money) Scan(src any) error {
+	value, err := scanScaled(src, int64(Baht))
+
+	if err != nil {
+		return err
+	}
+
+	*m = Money(value)
+
+	return nil
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func (r Rate) String() string {
+	return formatScaled(int64(r), int64(Percent))
+}
+
+func (r Rate) Float64() float64 {
+	return float64(r) / float64(Percent)
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalScaled(data, int64(Percent))
+
+	if err != nil || value == nil {
+		return err
+	}
+
+	*r = Rate(*value)
+
+	return nil
+}
+
+func (r *Rate) Scan(src any) error {
+	value, err := scanScaled(src, int64(Percent))
+
+	if err != nil {
+		return err
+	}
+
+	*r = Rate(value)
+
+	return nil
+}
+
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// unmarshalScaled accepts a JSON number or a numeric string, null leaves the value untouched
+func unmarshalScaled(data []byte, scale int64) (*int64, error) {
+	text := string(data)
+
+	if text == "null" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return nil, err
+		}
+	}
+
+	value, err := parseScaled(text, scale)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &value, nil
+}
+
+func scanScaled(src any, scale int64) (int64, error) {
+	switch value := src.(type) {
+	case []byte:
+		return parseScaled(string(value), scale)
+	case string:
+		return parseScaled(value, scale)
+	case int64:
+		return value * scale, nil
+	case float64:
+		return parseScaled(strconv.FormatFloat(value, 'f', -1, 64), scale)
+	}
+
+	return 0, fmt.Errorf("%w: cannot scan %T", ErrInvalidAmount, src)
+}
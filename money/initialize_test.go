@@ -0,0 +1,256 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Parse
+
+func TestParse_ShouldReturnMoney_WhenCorrectInput(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		expected Money
+	}{
+		{"Test case 1", "1500", Money(150000)},
+		{"Test case 2", "1500.25", Money(150025)},
+		{"Test case 3", " 0.1 ", Money(10)},
+		{"Test case 4", "10.129", Money(1012)},
+		{"Test case 5", "-10.129", Money(-1012)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			result, err := Parse(tc.text)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestParse_ShouldReturnError_WhenInvalidInput(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+	}{
+		{"Test case 1", ""},
+		{"Test case 2", "abc"},
+		{"Test case 3", "1,000"},
+		{"Test case 4", "1e30"},
+		{"Test case 5", "1e3"},
+		{"Test case 6", "1/3"},
+		{"Test case 7", ".5"},
+		{"Test case 8", "+1"},
+		{"Test case 9", "100000000000000000000"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			_, err := Parse(tc.text)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidAmount)
+		})
+	}
+}
+
+// MulRate
+
+func TestMulRate_ShouldTruncateToSatang_WhenResultHasFractionOfSatang(t *testing.T) {
+	testCases := []struct {
+		name     string
+		amount   Money
+		rate     Rate
+		expected Money
+	}{
+		{"Test case 1", FromBaht(350000), FromPercent(10), FromBaht(35000)},
+		{"Test case 2", Money(99), FromPercent(10), Money(9)},
+		{"Test case 3", Money(12345), Rate(1250), Money(1543)},
+		{"Test case 4", Money(-99), FromPercent(10), Money(-9)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			result, err := tc.amount.MulRate(tc.rate)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestMulRate_ShouldReturnErrOverflow_WhenResultDoesNotFitInt64(t *testing.T) {
+	// Act
+	_, err := Money(math.MaxInt64).MulRate(FromPercent(200))
+
+	// Assert
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+// GrossUpTax
+
+func TestGrossUpTax_ShouldTruncateToSatang_WhenResultHasFractionOfSatang(t *testing.T) {
+	testCases := []struct {
+		name     string
+		amount   Money
+		rate     Rate
+		expected Money
+	}{
+		{"Test case 1", FromBaht(80000), FromPercent(20), FromBaht(20000)},
+		{"Test case 2", FromBaht(77000), FromPercent(23), FromBaht(23000)},
+		{"Test case 3", FromBaht(100), FromPercent(30), Money(4285)},
+		{"Test case 4", FromBaht(100), FromPercent(0), Money(0)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			result, err := tc.amount.GrossUpTax(tc.rate)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+// Ratio
+
+func TestRatio_ShouldTruncateToHundredthOfPercent(t *testing.T) {
+	testCases := []struct {
+		name     string
+		part     Money
+		whole    Money
+		expected Rate
+	}{
+		{"Test case 1", FromBaht(29000), FromBaht(500000), Rate(580)},
+		{"Test case 2", FromBaht(1), FromBaht(3), Rate(3333)},
+		{"Test case 3", FromBaht(0), FromBaht(500000), Rate(0)},
+		{"Test case 4", FromBaht(100), FromBaht(0), Rate(0)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			result, err := Ratio(tc.part, tc.whole)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestRatio_ShouldReturnErrOverflow_WhenResultDoesNotFitInt64(t *testing.T) {
+	// Act
+	_, err := Ratio(Money(math.MaxInt64), Money(1))
+
+	// Assert
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+// JSON
+
+func TestMarshalJSON_ShouldWriteNumberWithTwoDecimals(t *testing.T) {
+	// Act
+	result, err := json.Marshal(struct {
+		Amount Money `json:"amount"`
+		Rate   Rate  `json:"rate"`
+	}{Money(-150025), Rate(1250)})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, `{"amount":-1500.25,"rate":12.50}`, string(result))
+}
+
+func TestUnmarshalJSON_ShouldReadNumberOrString(t *testing.T) {
+	testCases := []struct {
+		name     string
+		json     string
+		expected Money
+	}{
+		{"Test case 1", `{"amount":1500.25}`, Money(150025)},
+		{"Test case 2", `{"amount":"1500.25"}`, Money(150025)},
+		{"Test case 3", `{"amount":0.105}`, Money(10)},
+		{"Test case 4", `{"amount":null}`, Money(0)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var result struct {
+				Amount Money `json:"amount"`
+			}
+
+			// Act
+			err := json.Unmarshal([]byte(tc.json), &result)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result.Amount)
+		})
+	}
+}
+
+func TestUnmarshalJSON_ShouldReturnError_WhenInvalidInput(t *testing.T) {
+	// Arrange
+	var result struct {
+		Amount Money `json:"amount"`
+	}
+
+	// Act
+	err := json.Unmarshal([]byte(`{"amount":"asdasd"}`), &result)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+}
+
+// Scan
+
+func TestScan_ShouldReadDatabaseValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		src      any
+		expected Money
+	}{
+		{"Test case 1", []byte("70000.50"), Money(7000050)},
+		{"Test case 2", "70000.50", Money(7000050)},
+		{"Test case 3", int64(70000), Money(7000000)},
+		{"Test case 4", 0.1, Money(10)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			var result Money
+
+			// Act
+			err := result.Scan(tc.src)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestScan_ShouldReturnError_WhenUnsupportedType(t *testing.T) {
+	// Arrange
+	var result Money
+
+	// Act
+	err := result.Scan(true)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+}
@@ -0,0 +1,7 @@
+package money
+
+// Money is an exact amount of baht stored in satang (1/100 baht)
+type Money int64
+
+// Rate is an exact percentage stored in hundredths of a percent e.g. 10% is 1000
+type Rate int64
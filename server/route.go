@@ -1,16 +1,29 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/larb26656/assessment-tax/config"
 	"github.com/larb26656/assessment-tax/domains/admin"
+	"github.com/larb26656/assessment-tax/domains/admin/allowanceGroup"
 	"github.com/larb26656/assessment-tax/domains/admin/deduction"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/allowance"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/dependent"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/donation"
+	"github.com/larb26656/assessment-tax/domains/admin/deduction/insurance"
 	"github.com/larb26656/assessment-tax/domains/admin/deduction/kReceipt"
 	"github.com/larb26656/assessment-tax/domains/admin/deduction/personal"
+	"github.com/larb26656/assessment-tax/domains/admin/taxBracket"
+	"github.com/larb26656/assessment-tax/domains/admin/taxYear"
+	"github.com/larb26656/assessment-tax/domains/tax/batchJob"
 	"github.com/larb26656/assessment-tax/domains/tax/calculator"
+	"github.com/larb26656/assessment-tax/domains/tax/optimizer"
+	"github.com/larb26656/assessment-tax/domains/tax/payroll"
+	"github.com/larb26656/assessment-tax/domains/tax/reverseCalculator"
+	"github.com/larb26656/assessment-tax/domains/tax/scenario"
 )
 
 func RegisterRoute(appConfig *config.AppConfig, db *sql.DB, e *echo.Echo) {
@@ -22,9 +35,38 @@ func RegisterRoute(appConfig *config.AppConfig, db *sql.DB, e *echo.Echo) {
 	personalDeductionsUsecase := personal.NewPersonalDeductionUsecase(deductionRepository)
 	personalDeductionsHttpHandler := personal.NewPersonalDeductionHttpHandler(personalDeductionsUsecase)
 
+	// dependent deduction
+	dependentDeductionsUsecase := dependent.NewDependentDeductionUsecase(deductionRepository)
+	dependentDeductionsHttpHandler := dependent.NewDependentDeductionHttpHandler(dependentDeductionsUsecase)
+
+	// allowance deduction
+	allowanceDeductionsUsecase := allowance.NewAllowanceDeductionUsecase(deductionRepository)
+	allowanceDeductionsHttpHandler := allowance.NewAllowanceDeductionHttpHandler(allowanceDeductionsUsecase)
+
+	// donation deduction
+	donationDeductionsHttpHandler := donation.NewDonationDeductionHttpHandler(allowanceDeductionsUsecase)
+
 	// k-Receipt deduction
-	kReceiptDeductionsUsecase := kReceipt.NewKReceiptDeductionUsecase(deductionRepository)
-	kReceiptDeductionsHttpHandler := kReceipt.NewKReceiptDeductionHttpHandler(kReceiptDeductionsUsecase)
+	kReceiptDeductionsHttpHandler := kReceipt.NewKReceiptDeductionHttpHandler(allowanceDeductionsUsecase)
+
+	// insurance deduction
+	insuranceDeductionsUsecase := insurance.NewInsuranceDeductionUsecase(allowanceDeductionsUsecase)
+	insuranceDeductionsHttpHandler := insurance.NewInsuranceDeductionHttpHandler(insuranceDeductionsUsecase)
+
+	// allowance group
+	allowanceGroupRepository := allowanceGroup.NewAllowanceGroupRepository(db)
+	allowanceGroupUsecase := allowanceGroup.NewAllowanceGroupUsecase(allowanceGroupRepository)
+	allowanceGroupHttpHandler := allowanceGroup.NewAllowanceGroupHttpHandler(allowanceGroupUsecase)
+
+	// tax bracket
+	taxBracketRepository := taxBracket.NewTaxBracketRepository(db)
+	taxBracketUsecase := taxBracket.NewTaxBracketUsecase(taxBracketRepository)
+	taxBracketHttpHandler := taxBracket.NewTaxBracketHttpHandler(taxBracketUsecase)
+
+	// tax year
+	taxYearRepository := taxYear.NewTaxYearRepository(db)
+	taxYearUsecase := taxYear.NewTaxYearUsecase(taxYearRepository)
+	taxYearHttpHandler := taxYear.NewTaxYearHttpHandler(taxYearUsecase)
 
 	// admin
 	adminRepository := admin.NewAdminRepository(appConfig)
@@ -41,12 +83,71 @@ func RegisterRoute(appConfig *config.AppConfig, db *sql.DB, e *echo.Echo) {
 	}))
 
 	adminGroup.POST("/deductions/personal", personalDeductionsHttpHandler.UpdateDeduction)
+	adminGroup.GET("/deductions/dependents", dependentDeductionsHttpHandler.GetDeduction)
+	adminGroup.POST("/deductions/dependents", dependentDeductionsHttpHandler.UpdateDeduction)
+	adminGroup.GET("/deductions/donation", donationDeductionsHttpHandler.GetDeduction)
+	adminGroup.POST("/deductions/donation", donationDeductionsHttpHandler.UpdateDeduction)
 	adminGroup.POST("/deductions/k-receipt", kReceiptDeductionsHttpHandler.UpdateDeduction)
+	adminGroup.GET("/deductions/insurance", insuranceDeductionsHttpHandler.GetDeduction)
+	adminGroup.POST("/deductions/insurance", insuranceDeductionsHttpHandler.UpdateDeduction)
+	adminGroup.POST("/deductions/:allowanceType", allowanceDeductionsHttpHandler.UpdateMaxDeduction)
+	adminGroup.POST("/deductions/:allowanceType/cap-rate", allowanceDeductionsHttpHandler.UpdateCapRate)
+
+	adminGroup.GET("/allowance-types", allowanceDeductionsHttpHandler.GetAllowanceTypes)
+
+	adminGroup.GET("/allowance-groups", allowanceGroupHttpHandler.GetAllowanceGroups)
+	adminGroup.PUT("/allowance-groups", allowanceGroupHttpHandler.UpdateAllowanceGroups)
+
+	adminGroup.GET("/tax-brackets", taxBracketHttpHandler.GetTaxBrackets)
+	adminGroup.PUT("/tax-brackets", taxBracketHttpHandler.UpdateTaxBrackets)
+	adminGroup.POST("/tax-brackets/validate", taxBracketHttpHandler.ValidateTaxBrackets)
+
+	adminGroup.GET("/tax-years", taxYearHttpHandler.GetTaxYears)
+	adminGroup.POST("/tax-years", taxYearHttpHandler.CloneTaxYear)
 
 	// tax
-	taxCalculatorUsecase := calculator.NewTaxCalculatorUseCase(personalDeductionsUsecase, kReceiptDeductionsUsecase)
+	taxCalculatorUsecase := calculator.NewTaxCalculatorUseCase(personalDeductionsUsecase, dependentDeductionsUsecase, allowanceDeductionsUsecase, allowanceGroupUsecase, taxBracketUsecase)
 	taxCalculatorHttpHandler := calculator.NewTaxCalculatorHttpHandler(taxCalculatorUsecase)
 
 	e.POST("/tax/calculations", taxCalculatorHttpHandler.CalculateTax)
 	e.POST("/tax/calculations/upload-csv", taxCalculatorHttpHandler.CalculateTaxWithCSV)
+
+	// reverse tax
+	reverseTaxCalculatorUsecase := reverseCalculator.NewReverseTaxCalculatorUseCase(taxCalculatorUsecase)
+	reverseTaxCalculatorHttpHandler := reverseCalculator.NewReverseTaxCalculatorHttpHandler(reverseTaxCalculatorUsecase)
+
+	e.POST("/tax/reverse-calculations", reverseTaxCalculatorHttpHandler.CalculateReverseTax)
+
+	// tax scenario
+	taxScenarioUsecase := scenario.NewTaxScenarioUseCase(taxCalculatorUsecase)
+	taxScenarioHttpHandler := scenario.NewTaxScenarioHttpHandler(taxScenarioUsecase)
+
+	e.POST("/tax/scenarios", taxScenarioHttpHandler.CompareScenarios)
+
+	// allowance optimizer
+	allowanceOptimizerUsecase := optimizer.NewAllowanceOptimizerUseCase(taxCalculatorUsecase)
+	allowanceOptimizerHttpHandler := optimizer.NewAllowanceOptimizerHttpHandler(allowanceOptimizerUsecase)
+
+	e.POST("/tax/allowance-recommendations", allowanceOptimizerHttpHandler.OptimizeAllowances)
+
+	// payroll withholding
+	payrollWithholdingUsecase := payroll.NewPayrollWithholdingUseCase(taxCalculatorUsecase)
+	payrollWithholdingHttpHandler := payroll.NewPayrollWithholdingHttpHandler(payrollWithholdingUsecase)
+
+	e.POST("/tax/payroll-withholdings", payrollWithholdingHttpHandler.CalculatePayrollWithholding)
+
+	// tax batch job
+	taxBatchJobRepository := batchJob.NewTaxBatchJobRepository(db)
+	taxBatchJobUsecase := batchJob.NewTaxBatchJobUseCase(taxBatchJobRepository, taxCalculatorUsecase, e.Validator.Validate)
+	taxBatchJobHttpHandler := batchJob.NewTaxBatchJobHttpHandler(taxBatchJobUsecase)
+
+	e.POST("/tax/batch-jobs", taxBatchJobHttpHandler.CreateJob)
+	e.GET("/tax/batch-jobs/:id", taxBatchJobHttpHandler.GetJob)
+	e.GET("/tax/batch-jobs/:id/results", taxBatchJobHttpHandler.GetJobResults)
+	e.POST("/tax/batch-jobs/:id/cancel", taxBatchJobHttpHandler.CancelJob)
+
+	// workers stop with the server, a job they leave running is resumed on the next start
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	e.Server.RegisterOnShutdown(stopWorkers)
+	taxBatchJobUsecase.StartWorkers(workerCtx, batchJob.DefaultWorkers)
 }
@@ -2,20 +2,82 @@ package validator
 
 import (
 	"net/http"
+	"reflect"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+	"github.com/larb26656/assessment-tax/constant/allowanceType"
+	"github.com/larb26656/assessment-tax/constant/incomeType"
+	"github.com/larb26656/assessment-tax/money"
 )
 
 func NewStructValidator(validator *validator.Validate) *StructValidator {
+	// money tags e.g. lte=100000 are written in baht and percent
+	validator.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		switch value := field.Interface().(type) {
+		case money.Money:
+			return value.Float64()
+		case money.Rate:
+			return value.Float64()
+		}
+
+		return nil
+	}, money.Money(0), money.Rate(0))
+
+	err := validator.RegisterValidation("allowance_type", validateAllowanceType)
+
+	if err != nil {
+		panic(err)
+	}
+
+	err = validator.RegisterValidation("income_type", validateIncomeType)
+
+	if err != nil {
+		panic(err)
+	}
+
+	err = validator.RegisterValidation("actual_expense", validateActualExpense)
+
+	if err != nil {
+		panic(err)
+	}
+
 	return &StructValidator{
 		validator: validator,
 	}
 }
 
+// validateAllowanceType accepts only keys of the allowance type registry
+func validateAllowanceType(fl validator.FieldLevel) bool {
+	_, ok := allowanceType.Get(fl.Field().String())
+
+	return ok
+}
+
+// validateIncomeType accepts only keys of the income type registry
+func validateIncomeType(fl validator.FieldLevel) bool {
+	_, ok := incomeType.Get(fl.Field().String())
+
+	return ok
+}
+
+// validateActualExpense accepts an actual expense only when the IncomeType field next to it allows one
+func validateActualExpense(fl validator.FieldLevel) bool {
+	incomeTypeField := fl.Parent().FieldByName("IncomeType")
+
+	if !incomeTypeField.IsValid() {
+		return false
+	}
+
+	registered, ok := incomeType.Get(incomeTypeField.String())
+
+	return ok && registered.ActualExpense
+}
+
 func (cv *StructValidator) Validate(i interface{}) error {
 	if err := cv.validator.Struct(i); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		// the validation errors stay reachable with errors.As for callers that report per field
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 	}
 	return nil
 }
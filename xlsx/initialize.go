@@ -0,0 +1,183 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MIMEType is the content type of an xlsx workbook
+const MIMEType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// ErrClosed is returned when a row is written after the workbook was closed
+var ErrClosed = errors.New("xlsx: writer is closed")
+
+// number is a cell written as a number, leading zeros are kept as text so codes are not changed
+var number = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?$`)
+
+const contentTypesXML = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`</Types>`
+
+const relsXML = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const workbookRelsXML = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`</Relationships>`
+
+const sheetBeginXML = xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+const sheetEndXML = `</sheetData></worksheet>`
+
+// Writer streams a workbook of a single sheet, rows go to the zip as they are written so memory does
+// not grow with the sheet. It writes records like csv.Writer, a cell that is a plain decimal number
+// is written as a number and every other cell as text.
+type Writer struct {
+	zip    *zip.Writer
+	sheet  *bufio.Writer
+	row    int
+	closed bool
+}
+
+// NewWriter writes the parts of the workbook before the sheet, the sheet is named sheetName
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+	zipWriter := zip.NewWriter(w)
+
+	var name strings.Builder
+
+	if err := xml.EscapeText(&name, []byte(sheetName)); err != nil {
+		return nil, err
+	}
+
+	workbookXML := xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	for _, part := range []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", relsXML},
+		{"xl/workbook.xml", workbookXML},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+	} {
+		partWriter, err := zipWriter.Create(part.name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = io.WriteString(partWriter, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	sheetWriter, err := zipWriter.Create("xl/worksheets/sheet1.xml")
+
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := bufio.NewWriter(sheetWriter)
+
+	if _, err = sheet.WriteString(sheetBeginXML); err != nil {
+		return nil, err
+	}
+
+	return &Writer{
+		zip:   zipWriter,
+		sheet: sheet,
+	}, nil
+}
+
+// Write writes a row, an empty cell is left out of the sheet
+func (w *Writer) Write(record []string) error {
+	if w.closed {
+		return ErrClosed
+	}
+
+	w.row++
+	row := strconv.Itoa(w.row)
+
+	w.sheet.WriteString(`<row r="` + row + `">`)
+
+	for i, value := range record {
+		if value == "" {
+			continue
+		}
+
+		ref := ColumnName(i) + row
+
+		if number.MatchString(value) {
+			w.sheet.WriteString(`<c r="` + ref + `"><v>` + value + `</v></c>`)
+			continue
+		}
+
+		w.sheet.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+
+		if err := xml.EscapeText(w.sheet, []byte(value)); err != nil {
+			return err
+		}
+
+		w.sheet.WriteString(`</t></is></c>`)
+	}
+
+	_, err := w.sheet.WriteString(`</row>`)
+
+	return err
+}
+
+// Flush writes the buffered rows to the underlying writer
+func (w *Writer) Flush() error {
+	if w.closed {
+		return nil
+	}
+
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+
+	return w.zip.Flush()
+}
+
+// Close ends the sheet and writes the zip directory, it does not close the underlying writer
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+
+	if _, err := w.sheet.WriteString(sheetEndXML); err != nil {
+		return err
+	}
+
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+
+	return w.zip.Close()
+}
+
+// ColumnName returns the letters of the zero based column index e.g. 0 is "A" and 26 is "AA"
+func ColumnName(index int) string {
+	name := ""
+
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+
+	return name
+}
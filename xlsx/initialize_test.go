@@ -0,0 +1,123 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sheetXML struct {
+	Rows []struct {
+		Ref   string `xml:"r,attr"`
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readPart(t *testing.T, data []byte, name string) []byte {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+
+	part, err := reader.Open(name)
+	assert.NoError(t, err)
+
+	defer part.Close()
+
+	content, err := io.ReadAll(part)
+	assert.NoError(t, err)
+
+	return content
+}
+
+// Writer
+
+func TestWriter_ShouldWriteWorkbook_WhenRowsWritten(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+
+	writer, err := NewWriter(&buf, "Taxes & more")
+	assert.NoError(t, err)
+
+	// Act
+	assert.NoError(t, writer.Write([]string{"totalIncome", "tax"}))
+	assert.NoError(t, writer.Write([]string{"500000.00", "", "a<b", "007"}))
+	assert.NoError(t, writer.Flush())
+	assert.NoError(t, writer.Close())
+
+	// Assert
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels"} {
+		assert.NotEmpty(t, readPart(t, buf.Bytes(), name))
+	}
+
+	assert.Contains(t, string(readPart(t, buf.Bytes(), "xl/workbook.xml")), `name="Taxes &amp; more"`)
+
+	var sheet sheetXML
+
+	assert.NoError(t, xml.Unmarshal(readPart(t, buf.Bytes(), "xl/worksheets/sheet1.xml"), &sheet))
+	assert.Len(t, sheet.Rows, 2)
+
+	assert.Equal(t, "1", sheet.Rows[0].Ref)
+	assert.Equal(t, "A1", sheet.Rows[0].Cells[0].Ref)
+	assert.Equal(t, "inlineStr", sheet.Rows[0].Cells[0].Type)
+	assert.Equal(t, "totalIncome", sheet.Rows[0].Cells[0].Inline)
+
+	cells := sheet.Rows[1].Cells
+
+	assert.Len(t, cells, 3)
+	assert.Equal(t, "A2", cells[0].Ref)
+	assert.Equal(t, "", cells[0].Type)
+	assert.Equal(t, "500000.00", cells[0].Value)
+	assert.Equal(t, "C2", cells[1].Ref)
+	assert.Equal(t, "a<b", cells[1].Inline)
+	assert.Equal(t, "D2", cells[2].Ref)
+	assert.Equal(t, "inlineStr", cells[2].Type)
+	assert.Equal(t, "007", cells[2].Inline)
+}
+
+func TestWriter_ShouldReturnErrClosed_WhenWriteAfterClose(t *testing.T) {
+	// Arrange
+	writer, err := NewWriter(io.Discard, "Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	// Act
+	err = writer.Write([]string{"1"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+// ColumnName
+
+func TestColumnName_ShouldReturnLetters_WhenIndex(t *testing.T) {
+	testCases := []struct {
+		name     string
+		index    int
+		expected string
+	}{
+		{"Test case 1", 0, "A"},
+		{"Test case 2", 25, "Z"},
+		{"Test case 3", 26, "AA"},
+		{"Test case 4", 27, "AB"},
+		{"Test case 5", 701, "ZZ"},
+		{"Test case 6", 702, "AAA"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			result := ColumnName(tc.index)
+
+			// Assert
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}